@@ -0,0 +1,29 @@
+package awsapis
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+)
+
+// Interfaces
+type CloudWatchApi interface {
+	CloudWatchAlarmsDescriber
+}
+
+type CloudWatchAlarmsDescriber interface {
+	DescribeAlarms(ctx context.Context,
+		params *cloudwatch.DescribeAlarmsInput,
+		optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmsOutput, error)
+}
+
+// Implementation
+type AwsCloudWatchApi struct {
+	client *cloudwatch.Client
+}
+
+func (a *AwsCloudWatchApi) DescribeAlarms(ctx context.Context,
+	params *cloudwatch.DescribeAlarmsInput,
+	optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmsOutput, error) {
+	return a.client.DescribeAlarms(ctx, params, optFns...)
+}
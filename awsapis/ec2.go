@@ -10,6 +10,7 @@ import (
 type Ec2Api interface {
 	Ec2SubnetsDescriptor
 	Ec2InstanceTerminator
+	Ec2AvailabilityZonesDescriptor
 }
 
 type Ec2SubnetsDescriptor interface {
@@ -24,6 +25,12 @@ type Ec2InstanceTerminator interface {
 		optFns ...func(*ec2.Options)) (*ec2.TerminateInstancesOutput, error)
 }
 
+type Ec2AvailabilityZonesDescriptor interface {
+	DescribeAvailabilityZones(ctx context.Context,
+		params *ec2.DescribeAvailabilityZonesInput,
+		optFns ...func(*ec2.Options)) (*ec2.DescribeAvailabilityZonesOutput, error)
+}
+
 // Implementation
 type AwsEc2Api struct {
 	client *ec2.Client
@@ -42,3 +49,10 @@ func (a *AwsEc2Api) TerminateInstances(ctx context.Context,
 
 	return a.client.TerminateInstances(ctx, params, optFns...)
 }
+
+func (a *AwsEc2Api) DescribeAvailabilityZones(ctx context.Context,
+	params *ec2.DescribeAvailabilityZonesInput,
+	optFns ...func(*ec2.Options)) (*ec2.DescribeAvailabilityZonesOutput, error) {
+
+	return a.client.DescribeAvailabilityZones(ctx, params, optFns...)
+}
@@ -0,0 +1,65 @@
+package awsapis
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+)
+
+type EksApi interface {
+	EksNodegroupDescriptor
+	EksListClustersPaginator
+	EksListNodegroupsPaginator
+}
+
+type EksNodegroupDescriptor interface {
+	DescribeNodegroup(context.Context,
+		*eks.DescribeNodegroupInput,
+		...func(*eks.Options)) (*eks.DescribeNodegroupOutput, error)
+}
+
+type EksListClustersPaginator interface {
+	NewListClustersPaginator(
+		params *eks.ListClustersInput,
+		optFn ...func(*eks.Options)) EksListClustersPager
+}
+
+type EksListClustersPager interface {
+	HasMorePages() bool
+	NextPage(context.Context,
+		...func(*eks.Options)) (*eks.ListClustersOutput, error)
+}
+
+type EksListNodegroupsPaginator interface {
+	NewListNodegroupsPaginator(
+		params *eks.ListNodegroupsInput,
+		optFn ...func(*eks.Options)) EksListNodegroupsPager
+}
+
+type EksListNodegroupsPager interface {
+	HasMorePages() bool
+	NextPage(context.Context,
+		...func(*eks.Options)) (*eks.ListNodegroupsOutput, error)
+}
+
+type AwsEksApi struct {
+	client *eks.Client
+}
+
+func (a *AwsEksApi) NewListClustersPaginator(
+	params *eks.ListClustersInput,
+	optFn ...func(*eks.Options)) EksListClustersPager {
+	return eks.NewListClustersPaginator(a.client, params)
+}
+
+func (a *AwsEksApi) NewListNodegroupsPaginator(
+	params *eks.ListNodegroupsInput,
+	optFn ...func(*eks.Options)) EksListNodegroupsPager {
+	return eks.NewListNodegroupsPaginator(a.client, params)
+}
+
+func (a *AwsEksApi) DescribeNodegroup(ctx context.Context,
+	params *eks.DescribeNodegroupInput,
+	optFn ...func(*eks.Options)) (*eks.DescribeNodegroupOutput, error) {
+	return a.client.DescribeNodegroup(ctx, params, optFn...)
+}
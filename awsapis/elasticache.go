@@ -0,0 +1,98 @@
+package awsapis
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+)
+
+type ElastiCacheApi interface {
+	ElastiCacheReplicationGroupsDescriptor
+	ElastiCacheClustersDescriptor
+	ElastiCacheSubnetGroupsDescriptor
+	ElastiCacheFailoverTester
+	ElastiCacheTagLister
+	DescribeReplicationGroupsPaginator
+}
+
+type ElastiCacheReplicationGroupsDescriptor interface {
+	DescribeReplicationGroups(context.Context,
+		*elasticache.DescribeReplicationGroupsInput,
+		...func(*elasticache.Options)) (*elasticache.DescribeReplicationGroupsOutput, error)
+}
+
+type ElastiCacheClustersDescriptor interface {
+	DescribeCacheClusters(context.Context,
+		*elasticache.DescribeCacheClustersInput,
+		...func(*elasticache.Options)) (*elasticache.DescribeCacheClustersOutput, error)
+}
+
+type ElastiCacheSubnetGroupsDescriptor interface {
+	DescribeCacheSubnetGroups(context.Context,
+		*elasticache.DescribeCacheSubnetGroupsInput,
+		...func(*elasticache.Options)) (*elasticache.DescribeCacheSubnetGroupsOutput, error)
+}
+
+type ElastiCacheFailoverTester interface {
+	TestFailover(context.Context,
+		*elasticache.TestFailoverInput,
+		...func(*elasticache.Options)) (*elasticache.TestFailoverOutput, error)
+}
+
+type ElastiCacheTagLister interface {
+	ListTagsForResource(context.Context,
+		*elasticache.ListTagsForResourceInput,
+		...func(*elasticache.Options)) (*elasticache.ListTagsForResourceOutput, error)
+}
+
+type DescribeReplicationGroupsPaginator interface {
+	NewDescribeReplicationGroupsPaginator(
+		params *elasticache.DescribeReplicationGroupsInput,
+		optFn ...func(*elasticache.Options)) DescribeReplicationGroupsPager
+}
+
+type DescribeReplicationGroupsPager interface {
+	HasMorePages() bool
+	NextPage(context.Context,
+		...func(*elasticache.Options)) (*elasticache.DescribeReplicationGroupsOutput, error)
+}
+
+type AwsElastiCacheApi struct {
+	client *elasticache.Client
+}
+
+func (a *AwsElastiCacheApi) NewDescribeReplicationGroupsPaginator(
+	params *elasticache.DescribeReplicationGroupsInput,
+	optFn ...func(*elasticache.Options)) DescribeReplicationGroupsPager {
+	return elasticache.NewDescribeReplicationGroupsPaginator(a.client, params)
+}
+
+func (a *AwsElastiCacheApi) DescribeReplicationGroups(ctx context.Context,
+	params *elasticache.DescribeReplicationGroupsInput,
+	optFn ...func(*elasticache.Options)) (*elasticache.DescribeReplicationGroupsOutput, error) {
+	return a.client.DescribeReplicationGroups(ctx, params, optFn...)
+}
+
+func (a *AwsElastiCacheApi) DescribeCacheClusters(ctx context.Context,
+	params *elasticache.DescribeCacheClustersInput,
+	optFn ...func(*elasticache.Options)) (*elasticache.DescribeCacheClustersOutput, error) {
+	return a.client.DescribeCacheClusters(ctx, params, optFn...)
+}
+
+func (a *AwsElastiCacheApi) DescribeCacheSubnetGroups(ctx context.Context,
+	params *elasticache.DescribeCacheSubnetGroupsInput,
+	optFn ...func(*elasticache.Options)) (*elasticache.DescribeCacheSubnetGroupsOutput, error) {
+	return a.client.DescribeCacheSubnetGroups(ctx, params, optFn...)
+}
+
+func (a *AwsElastiCacheApi) TestFailover(ctx context.Context,
+	params *elasticache.TestFailoverInput,
+	optFn ...func(*elasticache.Options)) (*elasticache.TestFailoverOutput, error) {
+	return a.client.TestFailover(ctx, params, optFn...)
+}
+
+func (a *AwsElastiCacheApi) ListTagsForResource(ctx context.Context,
+	params *elasticache.ListTagsForResourceInput,
+	optFn ...func(*elasticache.Options)) (*elasticache.ListTagsForResourceOutput, error) {
+	return a.client.ListTagsForResource(ctx, params, optFn...)
+}
@@ -0,0 +1,72 @@
+package awsapis
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+)
+
+type LambdaApi interface {
+	LambdaFunctionConfigurationGetter
+	LambdaFunctionConfigurationUpdater
+	LambdaTagLister
+	ListFunctionsPaginator
+}
+
+type LambdaFunctionConfigurationGetter interface {
+	GetFunctionConfiguration(context.Context,
+		*lambda.GetFunctionConfigurationInput,
+		...func(*lambda.Options)) (*lambda.GetFunctionConfigurationOutput, error)
+}
+
+type LambdaFunctionConfigurationUpdater interface {
+	UpdateFunctionConfiguration(context.Context,
+		*lambda.UpdateFunctionConfigurationInput,
+		...func(*lambda.Options)) (*lambda.UpdateFunctionConfigurationOutput, error)
+}
+
+type LambdaTagLister interface {
+	ListTags(context.Context,
+		*lambda.ListTagsInput,
+		...func(*lambda.Options)) (*lambda.ListTagsOutput, error)
+}
+
+type ListFunctionsPaginator interface {
+	NewListFunctionsPaginator(
+		params *lambda.ListFunctionsInput,
+		optFn ...func(*lambda.Options)) ListFunctionsPager
+}
+
+type ListFunctionsPager interface {
+	HasMorePages() bool
+	NextPage(context.Context,
+		...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error)
+}
+
+type AwsLambdaApi struct {
+	client *lambda.Client
+}
+
+func (a *AwsLambdaApi) NewListFunctionsPaginator(
+	params *lambda.ListFunctionsInput,
+	optFn ...func(*lambda.Options)) ListFunctionsPager {
+	return lambda.NewListFunctionsPaginator(a.client, params)
+}
+
+func (a *AwsLambdaApi) GetFunctionConfiguration(ctx context.Context,
+	params *lambda.GetFunctionConfigurationInput,
+	optFn ...func(*lambda.Options)) (*lambda.GetFunctionConfigurationOutput, error) {
+	return a.client.GetFunctionConfiguration(ctx, params, optFn...)
+}
+
+func (a *AwsLambdaApi) UpdateFunctionConfiguration(ctx context.Context,
+	params *lambda.UpdateFunctionConfigurationInput,
+	optFn ...func(*lambda.Options)) (*lambda.UpdateFunctionConfigurationOutput, error) {
+	return a.client.UpdateFunctionConfiguration(ctx, params, optFn...)
+}
+
+func (a *AwsLambdaApi) ListTags(ctx context.Context,
+	params *lambda.ListTagsInput,
+	optFn ...func(*lambda.Options)) (*lambda.ListTagsOutput, error) {
+	return a.client.ListTags(ctx, params, optFn...)
+}
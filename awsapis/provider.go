@@ -3,10 +3,16 @@ package awsapis
 import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
 // Creates a new provider from AWS configuration
@@ -22,6 +28,12 @@ type AWSProvider interface {
 	NewEcsApi() EcsApi
 	NewAutoScalingApi() AutoScalingApi
 	NewElbV2Api() ElbV2Api
+	NewCloudWatchApi() CloudWatchApi
+	NewStsApi() StsApi
+	NewRdsApi() RdsApi
+	NewElastiCacheApi() ElastiCacheApi
+	NewEksApi() EksApi
+	NewLambdaApi() LambdaApi
 }
 
 type awsProviderImpl struct {
@@ -57,3 +69,39 @@ func (p awsProviderImpl) NewElbV2Api() ElbV2Api {
 		client: elasticloadbalancingv2.NewFromConfig(*p.awsConfig),
 	}
 }
+
+func (p awsProviderImpl) NewCloudWatchApi() CloudWatchApi {
+	return &AwsCloudWatchApi{
+		client: cloudwatch.NewFromConfig(*p.awsConfig),
+	}
+}
+
+func (p awsProviderImpl) NewStsApi() StsApi {
+	return &AwsStsApi{
+		client: sts.NewFromConfig(*p.awsConfig),
+	}
+}
+
+func (p awsProviderImpl) NewRdsApi() RdsApi {
+	return &AwsRdsApi{
+		client: rds.NewFromConfig(*p.awsConfig),
+	}
+}
+
+func (p awsProviderImpl) NewElastiCacheApi() ElastiCacheApi {
+	return &AwsElastiCacheApi{
+		client: elasticache.NewFromConfig(*p.awsConfig),
+	}
+}
+
+func (p awsProviderImpl) NewEksApi() EksApi {
+	return &AwsEksApi{
+		client: eks.NewFromConfig(*p.awsConfig),
+	}
+}
+
+func (p awsProviderImpl) NewLambdaApi() LambdaApi {
+	return &AwsLambdaApi{
+		client: lambda.NewFromConfig(*p.awsConfig),
+	}
+}
@@ -0,0 +1,104 @@
+package awsapis
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+type RdsApi interface {
+	RdsInstancesDescriptor
+	RdsInstanceRebooter
+	DescribeDBInstancesPaginator
+	RdsClustersDescriptor
+	RdsClusterFailoverer
+	DescribeDBClustersPaginator
+}
+
+type RdsInstancesDescriptor interface {
+	DescribeDBInstances(context.Context,
+		*rds.DescribeDBInstancesInput,
+		...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error)
+}
+
+type RdsInstanceRebooter interface {
+	RebootDBInstance(context.Context,
+		*rds.RebootDBInstanceInput,
+		...func(*rds.Options)) (*rds.RebootDBInstanceOutput, error)
+}
+
+type DescribeDBInstancesPaginator interface {
+	NewDescribeDBInstancesPaginator(
+		params *rds.DescribeDBInstancesInput,
+		optFn ...func(*rds.Options)) DescribeDBInstancesPager
+}
+
+type DescribeDBInstancesPager interface {
+	HasMorePages() bool
+	NextPage(context.Context,
+		...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error)
+}
+
+type RdsClustersDescriptor interface {
+	DescribeDBClusters(context.Context,
+		*rds.DescribeDBClustersInput,
+		...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error)
+}
+
+type RdsClusterFailoverer interface {
+	FailoverDBCluster(context.Context,
+		*rds.FailoverDBClusterInput,
+		...func(*rds.Options)) (*rds.FailoverDBClusterOutput, error)
+}
+
+type DescribeDBClustersPaginator interface {
+	NewDescribeDBClustersPaginator(
+		params *rds.DescribeDBClustersInput,
+		optFn ...func(*rds.Options)) DescribeDBClustersPager
+}
+
+type DescribeDBClustersPager interface {
+	HasMorePages() bool
+	NextPage(context.Context,
+		...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error)
+}
+
+type AwsRdsApi struct {
+	client *rds.Client
+}
+
+func (a *AwsRdsApi) NewDescribeDBInstancesPaginator(
+	params *rds.DescribeDBInstancesInput,
+	optFn ...func(*rds.Options)) DescribeDBInstancesPager {
+	return rds.NewDescribeDBInstancesPaginator(a.client, params)
+}
+
+func (a *AwsRdsApi) DescribeDBInstances(ctx context.Context,
+	params *rds.DescribeDBInstancesInput,
+	optFn ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	return a.client.DescribeDBInstances(ctx, params, optFn...)
+}
+
+func (a *AwsRdsApi) RebootDBInstance(ctx context.Context,
+	params *rds.RebootDBInstanceInput,
+	optFn ...func(*rds.Options)) (*rds.RebootDBInstanceOutput, error) {
+	return a.client.RebootDBInstance(ctx, params, optFn...)
+}
+
+func (a *AwsRdsApi) NewDescribeDBClustersPaginator(
+	params *rds.DescribeDBClustersInput,
+	optFn ...func(*rds.Options)) DescribeDBClustersPager {
+	return rds.NewDescribeDBClustersPaginator(a.client, params)
+}
+
+func (a *AwsRdsApi) DescribeDBClusters(ctx context.Context,
+	params *rds.DescribeDBClustersInput,
+	optFn ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
+	return a.client.DescribeDBClusters(ctx, params, optFn...)
+}
+
+func (a *AwsRdsApi) FailoverDBCluster(ctx context.Context,
+	params *rds.FailoverDBClusterInput,
+	optFn ...func(*rds.Options)) (*rds.FailoverDBClusterOutput, error) {
+	return a.client.FailoverDBCluster(ctx, params, optFn...)
+}
@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: awsapis/cloudwatch.go
+
+// Package awsapis_mocks is a generated GoMock package.
+package awsapis_mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	cloudwatch "github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCloudWatchApi is a mock of CloudWatchApi interface.
+type MockCloudWatchApi struct {
+	ctrl     *gomock.Controller
+	recorder *MockCloudWatchApiMockRecorder
+}
+
+// MockCloudWatchApiMockRecorder is the mock recorder for MockCloudWatchApi.
+type MockCloudWatchApiMockRecorder struct {
+	mock *MockCloudWatchApi
+}
+
+// NewMockCloudWatchApi creates a new mock instance.
+func NewMockCloudWatchApi(ctrl *gomock.Controller) *MockCloudWatchApi {
+	mock := &MockCloudWatchApi{ctrl: ctrl}
+	mock.recorder = &MockCloudWatchApiMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCloudWatchApi) EXPECT() *MockCloudWatchApiMockRecorder {
+	return m.recorder
+}
+
+// DescribeAlarms mocks base method.
+func (m *MockCloudWatchApi) DescribeAlarms(ctx context.Context, params *cloudwatch.DescribeAlarmsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeAlarms", varargs...)
+	ret0, _ := ret[0].(*cloudwatch.DescribeAlarmsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAlarms indicates an expected call of DescribeAlarms.
+func (mr *MockCloudWatchApiMockRecorder) DescribeAlarms(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAlarms", reflect.TypeOf((*MockCloudWatchApi)(nil).DescribeAlarms), varargs...)
+}
+
+// MockCloudWatchAlarmsDescriber is a mock of CloudWatchAlarmsDescriber interface.
+type MockCloudWatchAlarmsDescriber struct {
+	ctrl     *gomock.Controller
+	recorder *MockCloudWatchAlarmsDescriberMockRecorder
+}
+
+// MockCloudWatchAlarmsDescriberMockRecorder is the mock recorder for MockCloudWatchAlarmsDescriber.
+type MockCloudWatchAlarmsDescriberMockRecorder struct {
+	mock *MockCloudWatchAlarmsDescriber
+}
+
+// NewMockCloudWatchAlarmsDescriber creates a new mock instance.
+func NewMockCloudWatchAlarmsDescriber(ctrl *gomock.Controller) *MockCloudWatchAlarmsDescriber {
+	mock := &MockCloudWatchAlarmsDescriber{ctrl: ctrl}
+	mock.recorder = &MockCloudWatchAlarmsDescriberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCloudWatchAlarmsDescriber) EXPECT() *MockCloudWatchAlarmsDescriberMockRecorder {
+	return m.recorder
+}
+
+// DescribeAlarms mocks base method.
+func (m *MockCloudWatchAlarmsDescriber) DescribeAlarms(ctx context.Context, params *cloudwatch.DescribeAlarmsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeAlarms", varargs...)
+	ret0, _ := ret[0].(*cloudwatch.DescribeAlarmsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAlarms indicates an expected call of DescribeAlarms.
+func (mr *MockCloudWatchAlarmsDescriberMockRecorder) DescribeAlarms(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAlarms", reflect.TypeOf((*MockCloudWatchAlarmsDescriber)(nil).DescribeAlarms), varargs...)
+}
@@ -35,6 +35,26 @@ func (m *MockEc2Api) EXPECT() *MockEc2ApiMockRecorder {
 	return m.recorder
 }
 
+// DescribeAvailabilityZones mocks base method.
+func (m *MockEc2Api) DescribeAvailabilityZones(ctx context.Context, params *ec2.DescribeAvailabilityZonesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAvailabilityZonesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeAvailabilityZones", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeAvailabilityZonesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAvailabilityZones indicates an expected call of DescribeAvailabilityZones.
+func (mr *MockEc2ApiMockRecorder) DescribeAvailabilityZones(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAvailabilityZones", reflect.TypeOf((*MockEc2Api)(nil).DescribeAvailabilityZones), varargs...)
+}
+
 // DescribeSubnets mocks base method.
 func (m *MockEc2Api) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
 	m.ctrl.T.Helper()
@@ -160,3 +180,46 @@ func (mr *MockEc2InstanceTerminatorMockRecorder) TerminateInstances(ctx, params
 	varargs := append([]interface{}{ctx, params}, optFns...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TerminateInstances", reflect.TypeOf((*MockEc2InstanceTerminator)(nil).TerminateInstances), varargs...)
 }
+
+// MockEc2AvailabilityZonesDescriptor is a mock of Ec2AvailabilityZonesDescriptor interface.
+type MockEc2AvailabilityZonesDescriptor struct {
+	ctrl     *gomock.Controller
+	recorder *MockEc2AvailabilityZonesDescriptorMockRecorder
+}
+
+// MockEc2AvailabilityZonesDescriptorMockRecorder is the mock recorder for MockEc2AvailabilityZonesDescriptor.
+type MockEc2AvailabilityZonesDescriptorMockRecorder struct {
+	mock *MockEc2AvailabilityZonesDescriptor
+}
+
+// NewMockEc2AvailabilityZonesDescriptor creates a new mock instance.
+func NewMockEc2AvailabilityZonesDescriptor(ctrl *gomock.Controller) *MockEc2AvailabilityZonesDescriptor {
+	mock := &MockEc2AvailabilityZonesDescriptor{ctrl: ctrl}
+	mock.recorder = &MockEc2AvailabilityZonesDescriptorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEc2AvailabilityZonesDescriptor) EXPECT() *MockEc2AvailabilityZonesDescriptorMockRecorder {
+	return m.recorder
+}
+
+// DescribeAvailabilityZones mocks base method.
+func (m *MockEc2AvailabilityZonesDescriptor) DescribeAvailabilityZones(ctx context.Context, params *ec2.DescribeAvailabilityZonesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAvailabilityZonesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeAvailabilityZones", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeAvailabilityZonesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAvailabilityZones indicates an expected call of DescribeAvailabilityZones.
+func (mr *MockEc2AvailabilityZonesDescriptorMockRecorder) DescribeAvailabilityZones(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAvailabilityZones", reflect.TypeOf((*MockEc2AvailabilityZonesDescriptor)(nil).DescribeAvailabilityZones), varargs...)
+}
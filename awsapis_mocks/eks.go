@@ -0,0 +1,336 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: awsapis/eks.go
+
+// Package awsapis_mocks is a generated GoMock package.
+package awsapis_mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	eks "github.com/aws/aws-sdk-go-v2/service/eks"
+	awsapis "github.com/mcastellin/aws-fail-az/awsapis"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEksApi is a mock of EksApi interface.
+type MockEksApi struct {
+	ctrl     *gomock.Controller
+	recorder *MockEksApiMockRecorder
+}
+
+// MockEksApiMockRecorder is the mock recorder for MockEksApi.
+type MockEksApiMockRecorder struct {
+	mock *MockEksApi
+}
+
+// NewMockEksApi creates a new mock instance.
+func NewMockEksApi(ctrl *gomock.Controller) *MockEksApi {
+	mock := &MockEksApi{ctrl: ctrl}
+	mock.recorder = &MockEksApiMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEksApi) EXPECT() *MockEksApiMockRecorder {
+	return m.recorder
+}
+
+// DescribeNodegroup mocks base method.
+func (m *MockEksApi) DescribeNodegroup(arg0 context.Context, arg1 *eks.DescribeNodegroupInput, arg2 ...func(*eks.Options)) (*eks.DescribeNodegroupOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeNodegroup", varargs...)
+	ret0, _ := ret[0].(*eks.DescribeNodegroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeNodegroup indicates an expected call of DescribeNodegroup.
+func (mr *MockEksApiMockRecorder) DescribeNodegroup(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeNodegroup", reflect.TypeOf((*MockEksApi)(nil).DescribeNodegroup), varargs...)
+}
+
+// NewListClustersPaginator mocks base method.
+func (m *MockEksApi) NewListClustersPaginator(params *eks.ListClustersInput, optFn ...func(*eks.Options)) awsapis.EksListClustersPager {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFn {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListClustersPaginator", varargs...)
+	ret0, _ := ret[0].(awsapis.EksListClustersPager)
+	return ret0
+}
+
+// NewListClustersPaginator indicates an expected call of NewListClustersPaginator.
+func (mr *MockEksApiMockRecorder) NewListClustersPaginator(params interface{}, optFn ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFn...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListClustersPaginator", reflect.TypeOf((*MockEksApi)(nil).NewListClustersPaginator), varargs...)
+}
+
+// NewListNodegroupsPaginator mocks base method.
+func (m *MockEksApi) NewListNodegroupsPaginator(params *eks.ListNodegroupsInput, optFn ...func(*eks.Options)) awsapis.EksListNodegroupsPager {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFn {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListNodegroupsPaginator", varargs...)
+	ret0, _ := ret[0].(awsapis.EksListNodegroupsPager)
+	return ret0
+}
+
+// NewListNodegroupsPaginator indicates an expected call of NewListNodegroupsPaginator.
+func (mr *MockEksApiMockRecorder) NewListNodegroupsPaginator(params interface{}, optFn ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFn...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListNodegroupsPaginator", reflect.TypeOf((*MockEksApi)(nil).NewListNodegroupsPaginator), varargs...)
+}
+
+// MockEksNodegroupDescriptor is a mock of EksNodegroupDescriptor interface.
+type MockEksNodegroupDescriptor struct {
+	ctrl     *gomock.Controller
+	recorder *MockEksNodegroupDescriptorMockRecorder
+}
+
+// MockEksNodegroupDescriptorMockRecorder is the mock recorder for MockEksNodegroupDescriptor.
+type MockEksNodegroupDescriptorMockRecorder struct {
+	mock *MockEksNodegroupDescriptor
+}
+
+// NewMockEksNodegroupDescriptor creates a new mock instance.
+func NewMockEksNodegroupDescriptor(ctrl *gomock.Controller) *MockEksNodegroupDescriptor {
+	mock := &MockEksNodegroupDescriptor{ctrl: ctrl}
+	mock.recorder = &MockEksNodegroupDescriptorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEksNodegroupDescriptor) EXPECT() *MockEksNodegroupDescriptorMockRecorder {
+	return m.recorder
+}
+
+// DescribeNodegroup mocks base method.
+func (m *MockEksNodegroupDescriptor) DescribeNodegroup(arg0 context.Context, arg1 *eks.DescribeNodegroupInput, arg2 ...func(*eks.Options)) (*eks.DescribeNodegroupOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeNodegroup", varargs...)
+	ret0, _ := ret[0].(*eks.DescribeNodegroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeNodegroup indicates an expected call of DescribeNodegroup.
+func (mr *MockEksNodegroupDescriptorMockRecorder) DescribeNodegroup(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeNodegroup", reflect.TypeOf((*MockEksNodegroupDescriptor)(nil).DescribeNodegroup), varargs...)
+}
+
+// MockEksListClustersPaginator is a mock of EksListClustersPaginator interface.
+type MockEksListClustersPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockEksListClustersPaginatorMockRecorder
+}
+
+// MockEksListClustersPaginatorMockRecorder is the mock recorder for MockEksListClustersPaginator.
+type MockEksListClustersPaginatorMockRecorder struct {
+	mock *MockEksListClustersPaginator
+}
+
+// NewMockEksListClustersPaginator creates a new mock instance.
+func NewMockEksListClustersPaginator(ctrl *gomock.Controller) *MockEksListClustersPaginator {
+	mock := &MockEksListClustersPaginator{ctrl: ctrl}
+	mock.recorder = &MockEksListClustersPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEksListClustersPaginator) EXPECT() *MockEksListClustersPaginatorMockRecorder {
+	return m.recorder
+}
+
+// NewListClustersPaginator mocks base method.
+func (m *MockEksListClustersPaginator) NewListClustersPaginator(params *eks.ListClustersInput, optFn ...func(*eks.Options)) awsapis.EksListClustersPager {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFn {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListClustersPaginator", varargs...)
+	ret0, _ := ret[0].(awsapis.EksListClustersPager)
+	return ret0
+}
+
+// NewListClustersPaginator indicates an expected call of NewListClustersPaginator.
+func (mr *MockEksListClustersPaginatorMockRecorder) NewListClustersPaginator(params interface{}, optFn ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFn...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListClustersPaginator", reflect.TypeOf((*MockEksListClustersPaginator)(nil).NewListClustersPaginator), varargs...)
+}
+
+// MockEksListClustersPager is a mock of EksListClustersPager interface.
+type MockEksListClustersPager struct {
+	ctrl     *gomock.Controller
+	recorder *MockEksListClustersPagerMockRecorder
+}
+
+// MockEksListClustersPagerMockRecorder is the mock recorder for MockEksListClustersPager.
+type MockEksListClustersPagerMockRecorder struct {
+	mock *MockEksListClustersPager
+}
+
+// NewMockEksListClustersPager creates a new mock instance.
+func NewMockEksListClustersPager(ctrl *gomock.Controller) *MockEksListClustersPager {
+	mock := &MockEksListClustersPager{ctrl: ctrl}
+	mock.recorder = &MockEksListClustersPagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEksListClustersPager) EXPECT() *MockEksListClustersPagerMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockEksListClustersPager) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockEksListClustersPagerMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockEksListClustersPager)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockEksListClustersPager) NextPage(arg0 context.Context, arg1 ...func(*eks.Options)) (*eks.ListClustersOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*eks.ListClustersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockEksListClustersPagerMockRecorder) NextPage(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockEksListClustersPager)(nil).NextPage), varargs...)
+}
+
+// MockEksListNodegroupsPaginator is a mock of EksListNodegroupsPaginator interface.
+type MockEksListNodegroupsPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockEksListNodegroupsPaginatorMockRecorder
+}
+
+// MockEksListNodegroupsPaginatorMockRecorder is the mock recorder for MockEksListNodegroupsPaginator.
+type MockEksListNodegroupsPaginatorMockRecorder struct {
+	mock *MockEksListNodegroupsPaginator
+}
+
+// NewMockEksListNodegroupsPaginator creates a new mock instance.
+func NewMockEksListNodegroupsPaginator(ctrl *gomock.Controller) *MockEksListNodegroupsPaginator {
+	mock := &MockEksListNodegroupsPaginator{ctrl: ctrl}
+	mock.recorder = &MockEksListNodegroupsPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEksListNodegroupsPaginator) EXPECT() *MockEksListNodegroupsPaginatorMockRecorder {
+	return m.recorder
+}
+
+// NewListNodegroupsPaginator mocks base method.
+func (m *MockEksListNodegroupsPaginator) NewListNodegroupsPaginator(params *eks.ListNodegroupsInput, optFn ...func(*eks.Options)) awsapis.EksListNodegroupsPager {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFn {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListNodegroupsPaginator", varargs...)
+	ret0, _ := ret[0].(awsapis.EksListNodegroupsPager)
+	return ret0
+}
+
+// NewListNodegroupsPaginator indicates an expected call of NewListNodegroupsPaginator.
+func (mr *MockEksListNodegroupsPaginatorMockRecorder) NewListNodegroupsPaginator(params interface{}, optFn ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFn...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListNodegroupsPaginator", reflect.TypeOf((*MockEksListNodegroupsPaginator)(nil).NewListNodegroupsPaginator), varargs...)
+}
+
+// MockEksListNodegroupsPager is a mock of EksListNodegroupsPager interface.
+type MockEksListNodegroupsPager struct {
+	ctrl     *gomock.Controller
+	recorder *MockEksListNodegroupsPagerMockRecorder
+}
+
+// MockEksListNodegroupsPagerMockRecorder is the mock recorder for MockEksListNodegroupsPager.
+type MockEksListNodegroupsPagerMockRecorder struct {
+	mock *MockEksListNodegroupsPager
+}
+
+// NewMockEksListNodegroupsPager creates a new mock instance.
+func NewMockEksListNodegroupsPager(ctrl *gomock.Controller) *MockEksListNodegroupsPager {
+	mock := &MockEksListNodegroupsPager{ctrl: ctrl}
+	mock.recorder = &MockEksListNodegroupsPagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEksListNodegroupsPager) EXPECT() *MockEksListNodegroupsPagerMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockEksListNodegroupsPager) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockEksListNodegroupsPagerMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockEksListNodegroupsPager)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockEksListNodegroupsPager) NextPage(arg0 context.Context, arg1 ...func(*eks.Options)) (*eks.ListNodegroupsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*eks.ListNodegroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockEksListNodegroupsPagerMockRecorder) NextPage(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockEksListNodegroupsPager)(nil).NextPage), varargs...)
+}
@@ -0,0 +1,470 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: awsapis/elasticache.go
+
+// Package awsapis_mocks is a generated GoMock package.
+package awsapis_mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	elasticache "github.com/aws/aws-sdk-go-v2/service/elasticache"
+	awsapis "github.com/mcastellin/aws-fail-az/awsapis"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockElastiCacheApi is a mock of ElastiCacheApi interface.
+type MockElastiCacheApi struct {
+	ctrl     *gomock.Controller
+	recorder *MockElastiCacheApiMockRecorder
+}
+
+// MockElastiCacheApiMockRecorder is the mock recorder for MockElastiCacheApi.
+type MockElastiCacheApiMockRecorder struct {
+	mock *MockElastiCacheApi
+}
+
+// NewMockElastiCacheApi creates a new mock instance.
+func NewMockElastiCacheApi(ctrl *gomock.Controller) *MockElastiCacheApi {
+	mock := &MockElastiCacheApi{ctrl: ctrl}
+	mock.recorder = &MockElastiCacheApiMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockElastiCacheApi) EXPECT() *MockElastiCacheApiMockRecorder {
+	return m.recorder
+}
+
+// DescribeCacheClusters mocks base method.
+func (m *MockElastiCacheApi) DescribeCacheClusters(arg0 context.Context, arg1 *elasticache.DescribeCacheClustersInput, arg2 ...func(*elasticache.Options)) (*elasticache.DescribeCacheClustersOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeCacheClusters", varargs...)
+	ret0, _ := ret[0].(*elasticache.DescribeCacheClustersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeCacheClusters indicates an expected call of DescribeCacheClusters.
+func (mr *MockElastiCacheApiMockRecorder) DescribeCacheClusters(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCacheClusters", reflect.TypeOf((*MockElastiCacheApi)(nil).DescribeCacheClusters), varargs...)
+}
+
+// DescribeCacheSubnetGroups mocks base method.
+func (m *MockElastiCacheApi) DescribeCacheSubnetGroups(arg0 context.Context, arg1 *elasticache.DescribeCacheSubnetGroupsInput, arg2 ...func(*elasticache.Options)) (*elasticache.DescribeCacheSubnetGroupsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeCacheSubnetGroups", varargs...)
+	ret0, _ := ret[0].(*elasticache.DescribeCacheSubnetGroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeCacheSubnetGroups indicates an expected call of DescribeCacheSubnetGroups.
+func (mr *MockElastiCacheApiMockRecorder) DescribeCacheSubnetGroups(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCacheSubnetGroups", reflect.TypeOf((*MockElastiCacheApi)(nil).DescribeCacheSubnetGroups), varargs...)
+}
+
+// DescribeReplicationGroups mocks base method.
+func (m *MockElastiCacheApi) DescribeReplicationGroups(arg0 context.Context, arg1 *elasticache.DescribeReplicationGroupsInput, arg2 ...func(*elasticache.Options)) (*elasticache.DescribeReplicationGroupsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeReplicationGroups", varargs...)
+	ret0, _ := ret[0].(*elasticache.DescribeReplicationGroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeReplicationGroups indicates an expected call of DescribeReplicationGroups.
+func (mr *MockElastiCacheApiMockRecorder) DescribeReplicationGroups(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeReplicationGroups", reflect.TypeOf((*MockElastiCacheApi)(nil).DescribeReplicationGroups), varargs...)
+}
+
+// ListTagsForResource mocks base method.
+func (m *MockElastiCacheApi) ListTagsForResource(arg0 context.Context, arg1 *elasticache.ListTagsForResourceInput, arg2 ...func(*elasticache.Options)) (*elasticache.ListTagsForResourceOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListTagsForResource", varargs...)
+	ret0, _ := ret[0].(*elasticache.ListTagsForResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagsForResource indicates an expected call of ListTagsForResource.
+func (mr *MockElastiCacheApiMockRecorder) ListTagsForResource(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagsForResource", reflect.TypeOf((*MockElastiCacheApi)(nil).ListTagsForResource), varargs...)
+}
+
+// NewDescribeReplicationGroupsPaginator mocks base method.
+func (m *MockElastiCacheApi) NewDescribeReplicationGroupsPaginator(params *elasticache.DescribeReplicationGroupsInput, optFn ...func(*elasticache.Options)) awsapis.DescribeReplicationGroupsPager {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFn {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewDescribeReplicationGroupsPaginator", varargs...)
+	ret0, _ := ret[0].(awsapis.DescribeReplicationGroupsPager)
+	return ret0
+}
+
+// NewDescribeReplicationGroupsPaginator indicates an expected call of NewDescribeReplicationGroupsPaginator.
+func (mr *MockElastiCacheApiMockRecorder) NewDescribeReplicationGroupsPaginator(params interface{}, optFn ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFn...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewDescribeReplicationGroupsPaginator", reflect.TypeOf((*MockElastiCacheApi)(nil).NewDescribeReplicationGroupsPaginator), varargs...)
+}
+
+// TestFailover mocks base method.
+func (m *MockElastiCacheApi) TestFailover(arg0 context.Context, arg1 *elasticache.TestFailoverInput, arg2 ...func(*elasticache.Options)) (*elasticache.TestFailoverOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "TestFailover", varargs...)
+	ret0, _ := ret[0].(*elasticache.TestFailoverOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TestFailover indicates an expected call of TestFailover.
+func (mr *MockElastiCacheApiMockRecorder) TestFailover(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestFailover", reflect.TypeOf((*MockElastiCacheApi)(nil).TestFailover), varargs...)
+}
+
+// MockElastiCacheReplicationGroupsDescriptor is a mock of ElastiCacheReplicationGroupsDescriptor interface.
+type MockElastiCacheReplicationGroupsDescriptor struct {
+	ctrl     *gomock.Controller
+	recorder *MockElastiCacheReplicationGroupsDescriptorMockRecorder
+}
+
+// MockElastiCacheReplicationGroupsDescriptorMockRecorder is the mock recorder for MockElastiCacheReplicationGroupsDescriptor.
+type MockElastiCacheReplicationGroupsDescriptorMockRecorder struct {
+	mock *MockElastiCacheReplicationGroupsDescriptor
+}
+
+// NewMockElastiCacheReplicationGroupsDescriptor creates a new mock instance.
+func NewMockElastiCacheReplicationGroupsDescriptor(ctrl *gomock.Controller) *MockElastiCacheReplicationGroupsDescriptor {
+	mock := &MockElastiCacheReplicationGroupsDescriptor{ctrl: ctrl}
+	mock.recorder = &MockElastiCacheReplicationGroupsDescriptorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockElastiCacheReplicationGroupsDescriptor) EXPECT() *MockElastiCacheReplicationGroupsDescriptorMockRecorder {
+	return m.recorder
+}
+
+// DescribeReplicationGroups mocks base method.
+func (m *MockElastiCacheReplicationGroupsDescriptor) DescribeReplicationGroups(arg0 context.Context, arg1 *elasticache.DescribeReplicationGroupsInput, arg2 ...func(*elasticache.Options)) (*elasticache.DescribeReplicationGroupsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeReplicationGroups", varargs...)
+	ret0, _ := ret[0].(*elasticache.DescribeReplicationGroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeReplicationGroups indicates an expected call of DescribeReplicationGroups.
+func (mr *MockElastiCacheReplicationGroupsDescriptorMockRecorder) DescribeReplicationGroups(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeReplicationGroups", reflect.TypeOf((*MockElastiCacheReplicationGroupsDescriptor)(nil).DescribeReplicationGroups), varargs...)
+}
+
+// MockElastiCacheClustersDescriptor is a mock of ElastiCacheClustersDescriptor interface.
+type MockElastiCacheClustersDescriptor struct {
+	ctrl     *gomock.Controller
+	recorder *MockElastiCacheClustersDescriptorMockRecorder
+}
+
+// MockElastiCacheClustersDescriptorMockRecorder is the mock recorder for MockElastiCacheClustersDescriptor.
+type MockElastiCacheClustersDescriptorMockRecorder struct {
+	mock *MockElastiCacheClustersDescriptor
+}
+
+// NewMockElastiCacheClustersDescriptor creates a new mock instance.
+func NewMockElastiCacheClustersDescriptor(ctrl *gomock.Controller) *MockElastiCacheClustersDescriptor {
+	mock := &MockElastiCacheClustersDescriptor{ctrl: ctrl}
+	mock.recorder = &MockElastiCacheClustersDescriptorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockElastiCacheClustersDescriptor) EXPECT() *MockElastiCacheClustersDescriptorMockRecorder {
+	return m.recorder
+}
+
+// DescribeCacheClusters mocks base method.
+func (m *MockElastiCacheClustersDescriptor) DescribeCacheClusters(arg0 context.Context, arg1 *elasticache.DescribeCacheClustersInput, arg2 ...func(*elasticache.Options)) (*elasticache.DescribeCacheClustersOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeCacheClusters", varargs...)
+	ret0, _ := ret[0].(*elasticache.DescribeCacheClustersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeCacheClusters indicates an expected call of DescribeCacheClusters.
+func (mr *MockElastiCacheClustersDescriptorMockRecorder) DescribeCacheClusters(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCacheClusters", reflect.TypeOf((*MockElastiCacheClustersDescriptor)(nil).DescribeCacheClusters), varargs...)
+}
+
+// MockElastiCacheSubnetGroupsDescriptor is a mock of ElastiCacheSubnetGroupsDescriptor interface.
+type MockElastiCacheSubnetGroupsDescriptor struct {
+	ctrl     *gomock.Controller
+	recorder *MockElastiCacheSubnetGroupsDescriptorMockRecorder
+}
+
+// MockElastiCacheSubnetGroupsDescriptorMockRecorder is the mock recorder for MockElastiCacheSubnetGroupsDescriptor.
+type MockElastiCacheSubnetGroupsDescriptorMockRecorder struct {
+	mock *MockElastiCacheSubnetGroupsDescriptor
+}
+
+// NewMockElastiCacheSubnetGroupsDescriptor creates a new mock instance.
+func NewMockElastiCacheSubnetGroupsDescriptor(ctrl *gomock.Controller) *MockElastiCacheSubnetGroupsDescriptor {
+	mock := &MockElastiCacheSubnetGroupsDescriptor{ctrl: ctrl}
+	mock.recorder = &MockElastiCacheSubnetGroupsDescriptorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockElastiCacheSubnetGroupsDescriptor) EXPECT() *MockElastiCacheSubnetGroupsDescriptorMockRecorder {
+	return m.recorder
+}
+
+// DescribeCacheSubnetGroups mocks base method.
+func (m *MockElastiCacheSubnetGroupsDescriptor) DescribeCacheSubnetGroups(arg0 context.Context, arg1 *elasticache.DescribeCacheSubnetGroupsInput, arg2 ...func(*elasticache.Options)) (*elasticache.DescribeCacheSubnetGroupsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeCacheSubnetGroups", varargs...)
+	ret0, _ := ret[0].(*elasticache.DescribeCacheSubnetGroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeCacheSubnetGroups indicates an expected call of DescribeCacheSubnetGroups.
+func (mr *MockElastiCacheSubnetGroupsDescriptorMockRecorder) DescribeCacheSubnetGroups(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCacheSubnetGroups", reflect.TypeOf((*MockElastiCacheSubnetGroupsDescriptor)(nil).DescribeCacheSubnetGroups), varargs...)
+}
+
+// MockElastiCacheFailoverTester is a mock of ElastiCacheFailoverTester interface.
+type MockElastiCacheFailoverTester struct {
+	ctrl     *gomock.Controller
+	recorder *MockElastiCacheFailoverTesterMockRecorder
+}
+
+// MockElastiCacheFailoverTesterMockRecorder is the mock recorder for MockElastiCacheFailoverTester.
+type MockElastiCacheFailoverTesterMockRecorder struct {
+	mock *MockElastiCacheFailoverTester
+}
+
+// NewMockElastiCacheFailoverTester creates a new mock instance.
+func NewMockElastiCacheFailoverTester(ctrl *gomock.Controller) *MockElastiCacheFailoverTester {
+	mock := &MockElastiCacheFailoverTester{ctrl: ctrl}
+	mock.recorder = &MockElastiCacheFailoverTesterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockElastiCacheFailoverTester) EXPECT() *MockElastiCacheFailoverTesterMockRecorder {
+	return m.recorder
+}
+
+// TestFailover mocks base method.
+func (m *MockElastiCacheFailoverTester) TestFailover(arg0 context.Context, arg1 *elasticache.TestFailoverInput, arg2 ...func(*elasticache.Options)) (*elasticache.TestFailoverOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "TestFailover", varargs...)
+	ret0, _ := ret[0].(*elasticache.TestFailoverOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TestFailover indicates an expected call of TestFailover.
+func (mr *MockElastiCacheFailoverTesterMockRecorder) TestFailover(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestFailover", reflect.TypeOf((*MockElastiCacheFailoverTester)(nil).TestFailover), varargs...)
+}
+
+// MockElastiCacheTagLister is a mock of ElastiCacheTagLister interface.
+type MockElastiCacheTagLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockElastiCacheTagListerMockRecorder
+}
+
+// MockElastiCacheTagListerMockRecorder is the mock recorder for MockElastiCacheTagLister.
+type MockElastiCacheTagListerMockRecorder struct {
+	mock *MockElastiCacheTagLister
+}
+
+// NewMockElastiCacheTagLister creates a new mock instance.
+func NewMockElastiCacheTagLister(ctrl *gomock.Controller) *MockElastiCacheTagLister {
+	mock := &MockElastiCacheTagLister{ctrl: ctrl}
+	mock.recorder = &MockElastiCacheTagListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockElastiCacheTagLister) EXPECT() *MockElastiCacheTagListerMockRecorder {
+	return m.recorder
+}
+
+// ListTagsForResource mocks base method.
+func (m *MockElastiCacheTagLister) ListTagsForResource(arg0 context.Context, arg1 *elasticache.ListTagsForResourceInput, arg2 ...func(*elasticache.Options)) (*elasticache.ListTagsForResourceOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListTagsForResource", varargs...)
+	ret0, _ := ret[0].(*elasticache.ListTagsForResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagsForResource indicates an expected call of ListTagsForResource.
+func (mr *MockElastiCacheTagListerMockRecorder) ListTagsForResource(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagsForResource", reflect.TypeOf((*MockElastiCacheTagLister)(nil).ListTagsForResource), varargs...)
+}
+
+// MockDescribeReplicationGroupsPaginator is a mock of DescribeReplicationGroupsPaginator interface.
+type MockDescribeReplicationGroupsPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockDescribeReplicationGroupsPaginatorMockRecorder
+}
+
+// MockDescribeReplicationGroupsPaginatorMockRecorder is the mock recorder for MockDescribeReplicationGroupsPaginator.
+type MockDescribeReplicationGroupsPaginatorMockRecorder struct {
+	mock *MockDescribeReplicationGroupsPaginator
+}
+
+// NewMockDescribeReplicationGroupsPaginator creates a new mock instance.
+func NewMockDescribeReplicationGroupsPaginator(ctrl *gomock.Controller) *MockDescribeReplicationGroupsPaginator {
+	mock := &MockDescribeReplicationGroupsPaginator{ctrl: ctrl}
+	mock.recorder = &MockDescribeReplicationGroupsPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDescribeReplicationGroupsPaginator) EXPECT() *MockDescribeReplicationGroupsPaginatorMockRecorder {
+	return m.recorder
+}
+
+// NewDescribeReplicationGroupsPaginator mocks base method.
+func (m *MockDescribeReplicationGroupsPaginator) NewDescribeReplicationGroupsPaginator(params *elasticache.DescribeReplicationGroupsInput, optFn ...func(*elasticache.Options)) awsapis.DescribeReplicationGroupsPager {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFn {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewDescribeReplicationGroupsPaginator", varargs...)
+	ret0, _ := ret[0].(awsapis.DescribeReplicationGroupsPager)
+	return ret0
+}
+
+// NewDescribeReplicationGroupsPaginator indicates an expected call of NewDescribeReplicationGroupsPaginator.
+func (mr *MockDescribeReplicationGroupsPaginatorMockRecorder) NewDescribeReplicationGroupsPaginator(params interface{}, optFn ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFn...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewDescribeReplicationGroupsPaginator", reflect.TypeOf((*MockDescribeReplicationGroupsPaginator)(nil).NewDescribeReplicationGroupsPaginator), varargs...)
+}
+
+// MockDescribeReplicationGroupsPager is a mock of DescribeReplicationGroupsPager interface.
+type MockDescribeReplicationGroupsPager struct {
+	ctrl     *gomock.Controller
+	recorder *MockDescribeReplicationGroupsPagerMockRecorder
+}
+
+// MockDescribeReplicationGroupsPagerMockRecorder is the mock recorder for MockDescribeReplicationGroupsPager.
+type MockDescribeReplicationGroupsPagerMockRecorder struct {
+	mock *MockDescribeReplicationGroupsPager
+}
+
+// NewMockDescribeReplicationGroupsPager creates a new mock instance.
+func NewMockDescribeReplicationGroupsPager(ctrl *gomock.Controller) *MockDescribeReplicationGroupsPager {
+	mock := &MockDescribeReplicationGroupsPager{ctrl: ctrl}
+	mock.recorder = &MockDescribeReplicationGroupsPagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDescribeReplicationGroupsPager) EXPECT() *MockDescribeReplicationGroupsPagerMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockDescribeReplicationGroupsPager) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockDescribeReplicationGroupsPagerMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockDescribeReplicationGroupsPager)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockDescribeReplicationGroupsPager) NextPage(arg0 context.Context, arg1 ...func(*elasticache.Options)) (*elasticache.DescribeReplicationGroupsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*elasticache.DescribeReplicationGroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockDescribeReplicationGroupsPagerMockRecorder) NextPage(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockDescribeReplicationGroupsPager)(nil).NextPage), varargs...)
+}
@@ -0,0 +1,344 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: awsapis/lambda.go
+
+// Package awsapis_mocks is a generated GoMock package.
+package awsapis_mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	lambda "github.com/aws/aws-sdk-go-v2/service/lambda"
+	awsapis "github.com/mcastellin/aws-fail-az/awsapis"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLambdaApi is a mock of LambdaApi interface.
+type MockLambdaApi struct {
+	ctrl     *gomock.Controller
+	recorder *MockLambdaApiMockRecorder
+}
+
+// MockLambdaApiMockRecorder is the mock recorder for MockLambdaApi.
+type MockLambdaApiMockRecorder struct {
+	mock *MockLambdaApi
+}
+
+// NewMockLambdaApi creates a new mock instance.
+func NewMockLambdaApi(ctrl *gomock.Controller) *MockLambdaApi {
+	mock := &MockLambdaApi{ctrl: ctrl}
+	mock.recorder = &MockLambdaApiMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLambdaApi) EXPECT() *MockLambdaApiMockRecorder {
+	return m.recorder
+}
+
+// GetFunctionConfiguration mocks base method.
+func (m *MockLambdaApi) GetFunctionConfiguration(arg0 context.Context, arg1 *lambda.GetFunctionConfigurationInput, arg2 ...func(*lambda.Options)) (*lambda.GetFunctionConfigurationOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetFunctionConfiguration", varargs...)
+	ret0, _ := ret[0].(*lambda.GetFunctionConfigurationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFunctionConfiguration indicates an expected call of GetFunctionConfiguration.
+func (mr *MockLambdaApiMockRecorder) GetFunctionConfiguration(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFunctionConfiguration", reflect.TypeOf((*MockLambdaApi)(nil).GetFunctionConfiguration), varargs...)
+}
+
+// ListTags mocks base method.
+func (m *MockLambdaApi) ListTags(arg0 context.Context, arg1 *lambda.ListTagsInput, arg2 ...func(*lambda.Options)) (*lambda.ListTagsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListTags", varargs...)
+	ret0, _ := ret[0].(*lambda.ListTagsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTags indicates an expected call of ListTags.
+func (mr *MockLambdaApiMockRecorder) ListTags(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockLambdaApi)(nil).ListTags), varargs...)
+}
+
+// NewListFunctionsPaginator mocks base method.
+func (m *MockLambdaApi) NewListFunctionsPaginator(params *lambda.ListFunctionsInput, optFn ...func(*lambda.Options)) awsapis.ListFunctionsPager {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFn {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListFunctionsPaginator", varargs...)
+	ret0, _ := ret[0].(awsapis.ListFunctionsPager)
+	return ret0
+}
+
+// NewListFunctionsPaginator indicates an expected call of NewListFunctionsPaginator.
+func (mr *MockLambdaApiMockRecorder) NewListFunctionsPaginator(params interface{}, optFn ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFn...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListFunctionsPaginator", reflect.TypeOf((*MockLambdaApi)(nil).NewListFunctionsPaginator), varargs...)
+}
+
+// UpdateFunctionConfiguration mocks base method.
+func (m *MockLambdaApi) UpdateFunctionConfiguration(arg0 context.Context, arg1 *lambda.UpdateFunctionConfigurationInput, arg2 ...func(*lambda.Options)) (*lambda.UpdateFunctionConfigurationOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateFunctionConfiguration", varargs...)
+	ret0, _ := ret[0].(*lambda.UpdateFunctionConfigurationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFunctionConfiguration indicates an expected call of UpdateFunctionConfiguration.
+func (mr *MockLambdaApiMockRecorder) UpdateFunctionConfiguration(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFunctionConfiguration", reflect.TypeOf((*MockLambdaApi)(nil).UpdateFunctionConfiguration), varargs...)
+}
+
+// MockLambdaFunctionConfigurationGetter is a mock of LambdaFunctionConfigurationGetter interface.
+type MockLambdaFunctionConfigurationGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockLambdaFunctionConfigurationGetterMockRecorder
+}
+
+// MockLambdaFunctionConfigurationGetterMockRecorder is the mock recorder for MockLambdaFunctionConfigurationGetter.
+type MockLambdaFunctionConfigurationGetterMockRecorder struct {
+	mock *MockLambdaFunctionConfigurationGetter
+}
+
+// NewMockLambdaFunctionConfigurationGetter creates a new mock instance.
+func NewMockLambdaFunctionConfigurationGetter(ctrl *gomock.Controller) *MockLambdaFunctionConfigurationGetter {
+	mock := &MockLambdaFunctionConfigurationGetter{ctrl: ctrl}
+	mock.recorder = &MockLambdaFunctionConfigurationGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLambdaFunctionConfigurationGetter) EXPECT() *MockLambdaFunctionConfigurationGetterMockRecorder {
+	return m.recorder
+}
+
+// GetFunctionConfiguration mocks base method.
+func (m *MockLambdaFunctionConfigurationGetter) GetFunctionConfiguration(arg0 context.Context, arg1 *lambda.GetFunctionConfigurationInput, arg2 ...func(*lambda.Options)) (*lambda.GetFunctionConfigurationOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetFunctionConfiguration", varargs...)
+	ret0, _ := ret[0].(*lambda.GetFunctionConfigurationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFunctionConfiguration indicates an expected call of GetFunctionConfiguration.
+func (mr *MockLambdaFunctionConfigurationGetterMockRecorder) GetFunctionConfiguration(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFunctionConfiguration", reflect.TypeOf((*MockLambdaFunctionConfigurationGetter)(nil).GetFunctionConfiguration), varargs...)
+}
+
+// MockLambdaFunctionConfigurationUpdater is a mock of LambdaFunctionConfigurationUpdater interface.
+type MockLambdaFunctionConfigurationUpdater struct {
+	ctrl     *gomock.Controller
+	recorder *MockLambdaFunctionConfigurationUpdaterMockRecorder
+}
+
+// MockLambdaFunctionConfigurationUpdaterMockRecorder is the mock recorder for MockLambdaFunctionConfigurationUpdater.
+type MockLambdaFunctionConfigurationUpdaterMockRecorder struct {
+	mock *MockLambdaFunctionConfigurationUpdater
+}
+
+// NewMockLambdaFunctionConfigurationUpdater creates a new mock instance.
+func NewMockLambdaFunctionConfigurationUpdater(ctrl *gomock.Controller) *MockLambdaFunctionConfigurationUpdater {
+	mock := &MockLambdaFunctionConfigurationUpdater{ctrl: ctrl}
+	mock.recorder = &MockLambdaFunctionConfigurationUpdaterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLambdaFunctionConfigurationUpdater) EXPECT() *MockLambdaFunctionConfigurationUpdaterMockRecorder {
+	return m.recorder
+}
+
+// UpdateFunctionConfiguration mocks base method.
+func (m *MockLambdaFunctionConfigurationUpdater) UpdateFunctionConfiguration(arg0 context.Context, arg1 *lambda.UpdateFunctionConfigurationInput, arg2 ...func(*lambda.Options)) (*lambda.UpdateFunctionConfigurationOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateFunctionConfiguration", varargs...)
+	ret0, _ := ret[0].(*lambda.UpdateFunctionConfigurationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFunctionConfiguration indicates an expected call of UpdateFunctionConfiguration.
+func (mr *MockLambdaFunctionConfigurationUpdaterMockRecorder) UpdateFunctionConfiguration(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFunctionConfiguration", reflect.TypeOf((*MockLambdaFunctionConfigurationUpdater)(nil).UpdateFunctionConfiguration), varargs...)
+}
+
+// MockLambdaTagLister is a mock of LambdaTagLister interface.
+type MockLambdaTagLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockLambdaTagListerMockRecorder
+}
+
+// MockLambdaTagListerMockRecorder is the mock recorder for MockLambdaTagLister.
+type MockLambdaTagListerMockRecorder struct {
+	mock *MockLambdaTagLister
+}
+
+// NewMockLambdaTagLister creates a new mock instance.
+func NewMockLambdaTagLister(ctrl *gomock.Controller) *MockLambdaTagLister {
+	mock := &MockLambdaTagLister{ctrl: ctrl}
+	mock.recorder = &MockLambdaTagListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLambdaTagLister) EXPECT() *MockLambdaTagListerMockRecorder {
+	return m.recorder
+}
+
+// ListTags mocks base method.
+func (m *MockLambdaTagLister) ListTags(arg0 context.Context, arg1 *lambda.ListTagsInput, arg2 ...func(*lambda.Options)) (*lambda.ListTagsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListTags", varargs...)
+	ret0, _ := ret[0].(*lambda.ListTagsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTags indicates an expected call of ListTags.
+func (mr *MockLambdaTagListerMockRecorder) ListTags(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockLambdaTagLister)(nil).ListTags), varargs...)
+}
+
+// MockListFunctionsPaginator is a mock of ListFunctionsPaginator interface.
+type MockListFunctionsPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockListFunctionsPaginatorMockRecorder
+}
+
+// MockListFunctionsPaginatorMockRecorder is the mock recorder for MockListFunctionsPaginator.
+type MockListFunctionsPaginatorMockRecorder struct {
+	mock *MockListFunctionsPaginator
+}
+
+// NewMockListFunctionsPaginator creates a new mock instance.
+func NewMockListFunctionsPaginator(ctrl *gomock.Controller) *MockListFunctionsPaginator {
+	mock := &MockListFunctionsPaginator{ctrl: ctrl}
+	mock.recorder = &MockListFunctionsPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockListFunctionsPaginator) EXPECT() *MockListFunctionsPaginatorMockRecorder {
+	return m.recorder
+}
+
+// NewListFunctionsPaginator mocks base method.
+func (m *MockListFunctionsPaginator) NewListFunctionsPaginator(params *lambda.ListFunctionsInput, optFn ...func(*lambda.Options)) awsapis.ListFunctionsPager {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFn {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewListFunctionsPaginator", varargs...)
+	ret0, _ := ret[0].(awsapis.ListFunctionsPager)
+	return ret0
+}
+
+// NewListFunctionsPaginator indicates an expected call of NewListFunctionsPaginator.
+func (mr *MockListFunctionsPaginatorMockRecorder) NewListFunctionsPaginator(params interface{}, optFn ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFn...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewListFunctionsPaginator", reflect.TypeOf((*MockListFunctionsPaginator)(nil).NewListFunctionsPaginator), varargs...)
+}
+
+// MockListFunctionsPager is a mock of ListFunctionsPager interface.
+type MockListFunctionsPager struct {
+	ctrl     *gomock.Controller
+	recorder *MockListFunctionsPagerMockRecorder
+}
+
+// MockListFunctionsPagerMockRecorder is the mock recorder for MockListFunctionsPager.
+type MockListFunctionsPagerMockRecorder struct {
+	mock *MockListFunctionsPager
+}
+
+// NewMockListFunctionsPager creates a new mock instance.
+func NewMockListFunctionsPager(ctrl *gomock.Controller) *MockListFunctionsPager {
+	mock := &MockListFunctionsPager{ctrl: ctrl}
+	mock.recorder = &MockListFunctionsPagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockListFunctionsPager) EXPECT() *MockListFunctionsPagerMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockListFunctionsPager) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockListFunctionsPagerMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockListFunctionsPager)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockListFunctionsPager) NextPage(arg0 context.Context, arg1 ...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*lambda.ListFunctionsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockListFunctionsPagerMockRecorder) NextPage(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockListFunctionsPager)(nil).NextPage), varargs...)
+}
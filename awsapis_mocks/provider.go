@@ -48,6 +48,20 @@ func (mr *MockAWSProviderMockRecorder) NewAutoScalingApi() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewAutoScalingApi", reflect.TypeOf((*MockAWSProvider)(nil).NewAutoScalingApi))
 }
 
+// NewCloudWatchApi mocks base method.
+func (m *MockAWSProvider) NewCloudWatchApi() awsapis.CloudWatchApi {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewCloudWatchApi")
+	ret0, _ := ret[0].(awsapis.CloudWatchApi)
+	return ret0
+}
+
+// NewCloudWatchApi indicates an expected call of NewCloudWatchApi.
+func (mr *MockAWSProviderMockRecorder) NewCloudWatchApi() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewCloudWatchApi", reflect.TypeOf((*MockAWSProvider)(nil).NewCloudWatchApi))
+}
+
 // NewDynamodbApi mocks base method.
 func (m *MockAWSProvider) NewDynamodbApi() awsapis.DynamodbApi {
 	m.ctrl.T.Helper()
@@ -90,6 +104,34 @@ func (mr *MockAWSProviderMockRecorder) NewEcsApi() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewEcsApi", reflect.TypeOf((*MockAWSProvider)(nil).NewEcsApi))
 }
 
+// NewEksApi mocks base method.
+func (m *MockAWSProvider) NewEksApi() awsapis.EksApi {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewEksApi")
+	ret0, _ := ret[0].(awsapis.EksApi)
+	return ret0
+}
+
+// NewEksApi indicates an expected call of NewEksApi.
+func (mr *MockAWSProviderMockRecorder) NewEksApi() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewEksApi", reflect.TypeOf((*MockAWSProvider)(nil).NewEksApi))
+}
+
+// NewElastiCacheApi mocks base method.
+func (m *MockAWSProvider) NewElastiCacheApi() awsapis.ElastiCacheApi {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewElastiCacheApi")
+	ret0, _ := ret[0].(awsapis.ElastiCacheApi)
+	return ret0
+}
+
+// NewElastiCacheApi indicates an expected call of NewElastiCacheApi.
+func (mr *MockAWSProviderMockRecorder) NewElastiCacheApi() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewElastiCacheApi", reflect.TypeOf((*MockAWSProvider)(nil).NewElastiCacheApi))
+}
+
 // NewElbV2Api mocks base method.
 func (m *MockAWSProvider) NewElbV2Api() awsapis.ElbV2Api {
 	m.ctrl.T.Helper()
@@ -103,3 +145,45 @@ func (mr *MockAWSProviderMockRecorder) NewElbV2Api() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewElbV2Api", reflect.TypeOf((*MockAWSProvider)(nil).NewElbV2Api))
 }
+
+// NewLambdaApi mocks base method.
+func (m *MockAWSProvider) NewLambdaApi() awsapis.LambdaApi {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewLambdaApi")
+	ret0, _ := ret[0].(awsapis.LambdaApi)
+	return ret0
+}
+
+// NewLambdaApi indicates an expected call of NewLambdaApi.
+func (mr *MockAWSProviderMockRecorder) NewLambdaApi() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewLambdaApi", reflect.TypeOf((*MockAWSProvider)(nil).NewLambdaApi))
+}
+
+// NewRdsApi mocks base method.
+func (m *MockAWSProvider) NewRdsApi() awsapis.RdsApi {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewRdsApi")
+	ret0, _ := ret[0].(awsapis.RdsApi)
+	return ret0
+}
+
+// NewRdsApi indicates an expected call of NewRdsApi.
+func (mr *MockAWSProviderMockRecorder) NewRdsApi() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewRdsApi", reflect.TypeOf((*MockAWSProvider)(nil).NewRdsApi))
+}
+
+// NewStsApi mocks base method.
+func (m *MockAWSProvider) NewStsApi() awsapis.StsApi {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewStsApi")
+	ret0, _ := ret[0].(awsapis.StsApi)
+	return ret0
+}
+
+// NewStsApi indicates an expected call of NewStsApi.
+func (mr *MockAWSProviderMockRecorder) NewStsApi() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewStsApi", reflect.TypeOf((*MockAWSProvider)(nil).NewStsApi))
+}
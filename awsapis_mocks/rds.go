@@ -0,0 +1,525 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: awsapis/rds.go
+
+// Package awsapis_mocks is a generated GoMock package.
+package awsapis_mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	rds "github.com/aws/aws-sdk-go-v2/service/rds"
+	awsapis "github.com/mcastellin/aws-fail-az/awsapis"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRdsApi is a mock of RdsApi interface.
+type MockRdsApi struct {
+	ctrl     *gomock.Controller
+	recorder *MockRdsApiMockRecorder
+}
+
+// MockRdsApiMockRecorder is the mock recorder for MockRdsApi.
+type MockRdsApiMockRecorder struct {
+	mock *MockRdsApi
+}
+
+// NewMockRdsApi creates a new mock instance.
+func NewMockRdsApi(ctrl *gomock.Controller) *MockRdsApi {
+	mock := &MockRdsApi{ctrl: ctrl}
+	mock.recorder = &MockRdsApiMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRdsApi) EXPECT() *MockRdsApiMockRecorder {
+	return m.recorder
+}
+
+// DescribeDBClusters mocks base method.
+func (m *MockRdsApi) DescribeDBClusters(arg0 context.Context, arg1 *rds.DescribeDBClustersInput, arg2 ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDBClusters", varargs...)
+	ret0, _ := ret[0].(*rds.DescribeDBClustersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDBClusters indicates an expected call of DescribeDBClusters.
+func (mr *MockRdsApiMockRecorder) DescribeDBClusters(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDBClusters", reflect.TypeOf((*MockRdsApi)(nil).DescribeDBClusters), varargs...)
+}
+
+// DescribeDBInstances mocks base method.
+func (m *MockRdsApi) DescribeDBInstances(arg0 context.Context, arg1 *rds.DescribeDBInstancesInput, arg2 ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDBInstances", varargs...)
+	ret0, _ := ret[0].(*rds.DescribeDBInstancesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDBInstances indicates an expected call of DescribeDBInstances.
+func (mr *MockRdsApiMockRecorder) DescribeDBInstances(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDBInstances", reflect.TypeOf((*MockRdsApi)(nil).DescribeDBInstances), varargs...)
+}
+
+// FailoverDBCluster mocks base method.
+func (m *MockRdsApi) FailoverDBCluster(arg0 context.Context, arg1 *rds.FailoverDBClusterInput, arg2 ...func(*rds.Options)) (*rds.FailoverDBClusterOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FailoverDBCluster", varargs...)
+	ret0, _ := ret[0].(*rds.FailoverDBClusterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailoverDBCluster indicates an expected call of FailoverDBCluster.
+func (mr *MockRdsApiMockRecorder) FailoverDBCluster(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailoverDBCluster", reflect.TypeOf((*MockRdsApi)(nil).FailoverDBCluster), varargs...)
+}
+
+// NewDescribeDBClustersPaginator mocks base method.
+func (m *MockRdsApi) NewDescribeDBClustersPaginator(params *rds.DescribeDBClustersInput, optFn ...func(*rds.Options)) awsapis.DescribeDBClustersPager {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFn {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewDescribeDBClustersPaginator", varargs...)
+	ret0, _ := ret[0].(awsapis.DescribeDBClustersPager)
+	return ret0
+}
+
+// NewDescribeDBClustersPaginator indicates an expected call of NewDescribeDBClustersPaginator.
+func (mr *MockRdsApiMockRecorder) NewDescribeDBClustersPaginator(params interface{}, optFn ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFn...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewDescribeDBClustersPaginator", reflect.TypeOf((*MockRdsApi)(nil).NewDescribeDBClustersPaginator), varargs...)
+}
+
+// NewDescribeDBInstancesPaginator mocks base method.
+func (m *MockRdsApi) NewDescribeDBInstancesPaginator(params *rds.DescribeDBInstancesInput, optFn ...func(*rds.Options)) awsapis.DescribeDBInstancesPager {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFn {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewDescribeDBInstancesPaginator", varargs...)
+	ret0, _ := ret[0].(awsapis.DescribeDBInstancesPager)
+	return ret0
+}
+
+// NewDescribeDBInstancesPaginator indicates an expected call of NewDescribeDBInstancesPaginator.
+func (mr *MockRdsApiMockRecorder) NewDescribeDBInstancesPaginator(params interface{}, optFn ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFn...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewDescribeDBInstancesPaginator", reflect.TypeOf((*MockRdsApi)(nil).NewDescribeDBInstancesPaginator), varargs...)
+}
+
+// RebootDBInstance mocks base method.
+func (m *MockRdsApi) RebootDBInstance(arg0 context.Context, arg1 *rds.RebootDBInstanceInput, arg2 ...func(*rds.Options)) (*rds.RebootDBInstanceOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RebootDBInstance", varargs...)
+	ret0, _ := ret[0].(*rds.RebootDBInstanceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebootDBInstance indicates an expected call of RebootDBInstance.
+func (mr *MockRdsApiMockRecorder) RebootDBInstance(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebootDBInstance", reflect.TypeOf((*MockRdsApi)(nil).RebootDBInstance), varargs...)
+}
+
+// MockRdsInstancesDescriptor is a mock of RdsInstancesDescriptor interface.
+type MockRdsInstancesDescriptor struct {
+	ctrl     *gomock.Controller
+	recorder *MockRdsInstancesDescriptorMockRecorder
+}
+
+// MockRdsInstancesDescriptorMockRecorder is the mock recorder for MockRdsInstancesDescriptor.
+type MockRdsInstancesDescriptorMockRecorder struct {
+	mock *MockRdsInstancesDescriptor
+}
+
+// NewMockRdsInstancesDescriptor creates a new mock instance.
+func NewMockRdsInstancesDescriptor(ctrl *gomock.Controller) *MockRdsInstancesDescriptor {
+	mock := &MockRdsInstancesDescriptor{ctrl: ctrl}
+	mock.recorder = &MockRdsInstancesDescriptorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRdsInstancesDescriptor) EXPECT() *MockRdsInstancesDescriptorMockRecorder {
+	return m.recorder
+}
+
+// DescribeDBInstances mocks base method.
+func (m *MockRdsInstancesDescriptor) DescribeDBInstances(arg0 context.Context, arg1 *rds.DescribeDBInstancesInput, arg2 ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDBInstances", varargs...)
+	ret0, _ := ret[0].(*rds.DescribeDBInstancesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDBInstances indicates an expected call of DescribeDBInstances.
+func (mr *MockRdsInstancesDescriptorMockRecorder) DescribeDBInstances(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDBInstances", reflect.TypeOf((*MockRdsInstancesDescriptor)(nil).DescribeDBInstances), varargs...)
+}
+
+// MockRdsInstanceRebooter is a mock of RdsInstanceRebooter interface.
+type MockRdsInstanceRebooter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRdsInstanceRebooterMockRecorder
+}
+
+// MockRdsInstanceRebooterMockRecorder is the mock recorder for MockRdsInstanceRebooter.
+type MockRdsInstanceRebooterMockRecorder struct {
+	mock *MockRdsInstanceRebooter
+}
+
+// NewMockRdsInstanceRebooter creates a new mock instance.
+func NewMockRdsInstanceRebooter(ctrl *gomock.Controller) *MockRdsInstanceRebooter {
+	mock := &MockRdsInstanceRebooter{ctrl: ctrl}
+	mock.recorder = &MockRdsInstanceRebooterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRdsInstanceRebooter) EXPECT() *MockRdsInstanceRebooterMockRecorder {
+	return m.recorder
+}
+
+// RebootDBInstance mocks base method.
+func (m *MockRdsInstanceRebooter) RebootDBInstance(arg0 context.Context, arg1 *rds.RebootDBInstanceInput, arg2 ...func(*rds.Options)) (*rds.RebootDBInstanceOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RebootDBInstance", varargs...)
+	ret0, _ := ret[0].(*rds.RebootDBInstanceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebootDBInstance indicates an expected call of RebootDBInstance.
+func (mr *MockRdsInstanceRebooterMockRecorder) RebootDBInstance(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebootDBInstance", reflect.TypeOf((*MockRdsInstanceRebooter)(nil).RebootDBInstance), varargs...)
+}
+
+// MockDescribeDBInstancesPaginator is a mock of DescribeDBInstancesPaginator interface.
+type MockDescribeDBInstancesPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockDescribeDBInstancesPaginatorMockRecorder
+}
+
+// MockDescribeDBInstancesPaginatorMockRecorder is the mock recorder for MockDescribeDBInstancesPaginator.
+type MockDescribeDBInstancesPaginatorMockRecorder struct {
+	mock *MockDescribeDBInstancesPaginator
+}
+
+// NewMockDescribeDBInstancesPaginator creates a new mock instance.
+func NewMockDescribeDBInstancesPaginator(ctrl *gomock.Controller) *MockDescribeDBInstancesPaginator {
+	mock := &MockDescribeDBInstancesPaginator{ctrl: ctrl}
+	mock.recorder = &MockDescribeDBInstancesPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDescribeDBInstancesPaginator) EXPECT() *MockDescribeDBInstancesPaginatorMockRecorder {
+	return m.recorder
+}
+
+// NewDescribeDBInstancesPaginator mocks base method.
+func (m *MockDescribeDBInstancesPaginator) NewDescribeDBInstancesPaginator(params *rds.DescribeDBInstancesInput, optFn ...func(*rds.Options)) awsapis.DescribeDBInstancesPager {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFn {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewDescribeDBInstancesPaginator", varargs...)
+	ret0, _ := ret[0].(awsapis.DescribeDBInstancesPager)
+	return ret0
+}
+
+// NewDescribeDBInstancesPaginator indicates an expected call of NewDescribeDBInstancesPaginator.
+func (mr *MockDescribeDBInstancesPaginatorMockRecorder) NewDescribeDBInstancesPaginator(params interface{}, optFn ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFn...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewDescribeDBInstancesPaginator", reflect.TypeOf((*MockDescribeDBInstancesPaginator)(nil).NewDescribeDBInstancesPaginator), varargs...)
+}
+
+// MockDescribeDBInstancesPager is a mock of DescribeDBInstancesPager interface.
+type MockDescribeDBInstancesPager struct {
+	ctrl     *gomock.Controller
+	recorder *MockDescribeDBInstancesPagerMockRecorder
+}
+
+// MockDescribeDBInstancesPagerMockRecorder is the mock recorder for MockDescribeDBInstancesPager.
+type MockDescribeDBInstancesPagerMockRecorder struct {
+	mock *MockDescribeDBInstancesPager
+}
+
+// NewMockDescribeDBInstancesPager creates a new mock instance.
+func NewMockDescribeDBInstancesPager(ctrl *gomock.Controller) *MockDescribeDBInstancesPager {
+	mock := &MockDescribeDBInstancesPager{ctrl: ctrl}
+	mock.recorder = &MockDescribeDBInstancesPagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDescribeDBInstancesPager) EXPECT() *MockDescribeDBInstancesPagerMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockDescribeDBInstancesPager) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockDescribeDBInstancesPagerMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockDescribeDBInstancesPager)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockDescribeDBInstancesPager) NextPage(arg0 context.Context, arg1 ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*rds.DescribeDBInstancesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockDescribeDBInstancesPagerMockRecorder) NextPage(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockDescribeDBInstancesPager)(nil).NextPage), varargs...)
+}
+
+// MockRdsClustersDescriptor is a mock of RdsClustersDescriptor interface.
+type MockRdsClustersDescriptor struct {
+	ctrl     *gomock.Controller
+	recorder *MockRdsClustersDescriptorMockRecorder
+}
+
+// MockRdsClustersDescriptorMockRecorder is the mock recorder for MockRdsClustersDescriptor.
+type MockRdsClustersDescriptorMockRecorder struct {
+	mock *MockRdsClustersDescriptor
+}
+
+// NewMockRdsClustersDescriptor creates a new mock instance.
+func NewMockRdsClustersDescriptor(ctrl *gomock.Controller) *MockRdsClustersDescriptor {
+	mock := &MockRdsClustersDescriptor{ctrl: ctrl}
+	mock.recorder = &MockRdsClustersDescriptorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRdsClustersDescriptor) EXPECT() *MockRdsClustersDescriptorMockRecorder {
+	return m.recorder
+}
+
+// DescribeDBClusters mocks base method.
+func (m *MockRdsClustersDescriptor) DescribeDBClusters(arg0 context.Context, arg1 *rds.DescribeDBClustersInput, arg2 ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDBClusters", varargs...)
+	ret0, _ := ret[0].(*rds.DescribeDBClustersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDBClusters indicates an expected call of DescribeDBClusters.
+func (mr *MockRdsClustersDescriptorMockRecorder) DescribeDBClusters(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDBClusters", reflect.TypeOf((*MockRdsClustersDescriptor)(nil).DescribeDBClusters), varargs...)
+}
+
+// MockRdsClusterFailoverer is a mock of RdsClusterFailoverer interface.
+type MockRdsClusterFailoverer struct {
+	ctrl     *gomock.Controller
+	recorder *MockRdsClusterFailovererMockRecorder
+}
+
+// MockRdsClusterFailovererMockRecorder is the mock recorder for MockRdsClusterFailoverer.
+type MockRdsClusterFailovererMockRecorder struct {
+	mock *MockRdsClusterFailoverer
+}
+
+// NewMockRdsClusterFailoverer creates a new mock instance.
+func NewMockRdsClusterFailoverer(ctrl *gomock.Controller) *MockRdsClusterFailoverer {
+	mock := &MockRdsClusterFailoverer{ctrl: ctrl}
+	mock.recorder = &MockRdsClusterFailovererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRdsClusterFailoverer) EXPECT() *MockRdsClusterFailovererMockRecorder {
+	return m.recorder
+}
+
+// FailoverDBCluster mocks base method.
+func (m *MockRdsClusterFailoverer) FailoverDBCluster(arg0 context.Context, arg1 *rds.FailoverDBClusterInput, arg2 ...func(*rds.Options)) (*rds.FailoverDBClusterOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FailoverDBCluster", varargs...)
+	ret0, _ := ret[0].(*rds.FailoverDBClusterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailoverDBCluster indicates an expected call of FailoverDBCluster.
+func (mr *MockRdsClusterFailovererMockRecorder) FailoverDBCluster(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailoverDBCluster", reflect.TypeOf((*MockRdsClusterFailoverer)(nil).FailoverDBCluster), varargs...)
+}
+
+// MockDescribeDBClustersPaginator is a mock of DescribeDBClustersPaginator interface.
+type MockDescribeDBClustersPaginator struct {
+	ctrl     *gomock.Controller
+	recorder *MockDescribeDBClustersPaginatorMockRecorder
+}
+
+// MockDescribeDBClustersPaginatorMockRecorder is the mock recorder for MockDescribeDBClustersPaginator.
+type MockDescribeDBClustersPaginatorMockRecorder struct {
+	mock *MockDescribeDBClustersPaginator
+}
+
+// NewMockDescribeDBClustersPaginator creates a new mock instance.
+func NewMockDescribeDBClustersPaginator(ctrl *gomock.Controller) *MockDescribeDBClustersPaginator {
+	mock := &MockDescribeDBClustersPaginator{ctrl: ctrl}
+	mock.recorder = &MockDescribeDBClustersPaginatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDescribeDBClustersPaginator) EXPECT() *MockDescribeDBClustersPaginatorMockRecorder {
+	return m.recorder
+}
+
+// NewDescribeDBClustersPaginator mocks base method.
+func (m *MockDescribeDBClustersPaginator) NewDescribeDBClustersPaginator(params *rds.DescribeDBClustersInput, optFn ...func(*rds.Options)) awsapis.DescribeDBClustersPager {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{params}
+	for _, a := range optFn {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewDescribeDBClustersPaginator", varargs...)
+	ret0, _ := ret[0].(awsapis.DescribeDBClustersPager)
+	return ret0
+}
+
+// NewDescribeDBClustersPaginator indicates an expected call of NewDescribeDBClustersPaginator.
+func (mr *MockDescribeDBClustersPaginatorMockRecorder) NewDescribeDBClustersPaginator(params interface{}, optFn ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{params}, optFn...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewDescribeDBClustersPaginator", reflect.TypeOf((*MockDescribeDBClustersPaginator)(nil).NewDescribeDBClustersPaginator), varargs...)
+}
+
+// MockDescribeDBClustersPager is a mock of DescribeDBClustersPager interface.
+type MockDescribeDBClustersPager struct {
+	ctrl     *gomock.Controller
+	recorder *MockDescribeDBClustersPagerMockRecorder
+}
+
+// MockDescribeDBClustersPagerMockRecorder is the mock recorder for MockDescribeDBClustersPager.
+type MockDescribeDBClustersPagerMockRecorder struct {
+	mock *MockDescribeDBClustersPager
+}
+
+// NewMockDescribeDBClustersPager creates a new mock instance.
+func NewMockDescribeDBClustersPager(ctrl *gomock.Controller) *MockDescribeDBClustersPager {
+	mock := &MockDescribeDBClustersPager{ctrl: ctrl}
+	mock.recorder = &MockDescribeDBClustersPagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDescribeDBClustersPager) EXPECT() *MockDescribeDBClustersPagerMockRecorder {
+	return m.recorder
+}
+
+// HasMorePages mocks base method.
+func (m *MockDescribeDBClustersPager) HasMorePages() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasMorePages")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasMorePages indicates an expected call of HasMorePages.
+func (mr *MockDescribeDBClustersPagerMockRecorder) HasMorePages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasMorePages", reflect.TypeOf((*MockDescribeDBClustersPager)(nil).HasMorePages))
+}
+
+// NextPage mocks base method.
+func (m *MockDescribeDBClustersPager) NextPage(arg0 context.Context, arg1 ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NextPage", varargs...)
+	ret0, _ := ret[0].(*rds.DescribeDBClustersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPage indicates an expected call of NextPage.
+func (mr *MockDescribeDBClustersPagerMockRecorder) NextPage(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPage", reflect.TypeOf((*MockDescribeDBClustersPager)(nil).NextPage), varargs...)
+}
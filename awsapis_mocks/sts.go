@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: awsapis/sts.go
+
+// Package awsapis_mocks is a generated GoMock package.
+package awsapis_mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	sts "github.com/aws/aws-sdk-go-v2/service/sts"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStsApi is a mock of StsApi interface.
+type MockStsApi struct {
+	ctrl     *gomock.Controller
+	recorder *MockStsApiMockRecorder
+}
+
+// MockStsApiMockRecorder is the mock recorder for MockStsApi.
+type MockStsApiMockRecorder struct {
+	mock *MockStsApi
+}
+
+// NewMockStsApi creates a new mock instance.
+func NewMockStsApi(ctrl *gomock.Controller) *MockStsApi {
+	mock := &MockStsApi{ctrl: ctrl}
+	mock.recorder = &MockStsApiMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStsApi) EXPECT() *MockStsApiMockRecorder {
+	return m.recorder
+}
+
+// GetCallerIdentity mocks base method.
+func (m *MockStsApi) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetCallerIdentity", varargs...)
+	ret0, _ := ret[0].(*sts.GetCallerIdentityOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCallerIdentity indicates an expected call of GetCallerIdentity.
+func (mr *MockStsApiMockRecorder) GetCallerIdentity(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCallerIdentity", reflect.TypeOf((*MockStsApi)(nil).GetCallerIdentity), varargs...)
+}
+
+// MockStsCallerIdentityGetter is a mock of StsCallerIdentityGetter interface.
+type MockStsCallerIdentityGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockStsCallerIdentityGetterMockRecorder
+}
+
+// MockStsCallerIdentityGetterMockRecorder is the mock recorder for MockStsCallerIdentityGetter.
+type MockStsCallerIdentityGetterMockRecorder struct {
+	mock *MockStsCallerIdentityGetter
+}
+
+// NewMockStsCallerIdentityGetter creates a new mock instance.
+func NewMockStsCallerIdentityGetter(ctrl *gomock.Controller) *MockStsCallerIdentityGetter {
+	mock := &MockStsCallerIdentityGetter{ctrl: ctrl}
+	mock.recorder = &MockStsCallerIdentityGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStsCallerIdentityGetter) EXPECT() *MockStsCallerIdentityGetterMockRecorder {
+	return m.recorder
+}
+
+// GetCallerIdentity mocks base method.
+func (m *MockStsCallerIdentityGetter) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetCallerIdentity", varargs...)
+	ret0, _ := ret[0].(*sts.GetCallerIdentityOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCallerIdentity indicates an expected call of GetCallerIdentity.
+func (mr *MockStsCallerIdentityGetterMockRecorder) GetCallerIdentity(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCallerIdentity", reflect.TypeOf((*MockStsCallerIdentityGetter)(nil).GetCallerIdentity), varargs...)
+}
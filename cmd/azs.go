@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	"github.com/mcastellin/aws-fail-az/awsapis"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/service/awsutils"
+	"golang.org/x/exp/slices"
+)
+
+// Resolves the names of the availability zones to fail for the resources. Listed AZs can be
+// AZ names or AZ IDs. Random selections pick AZs among the ones the resources use, with a
+// generated seed if none was configured.
+// Returns the AZs to fail and the selector with the seed used, to reproduce the selection
+func selectAzs(ctx context.Context, api awsapis.Ec2Api, selector domain.AzsSelector,
+	resources []domain.ConsistentStateResource) ([]string, domain.AzsSelector, error) {
+
+	if !selector.IsRandom() {
+		azs, err := awsutils.ResolveAzNames(ctx, api, selector.List)
+		if err != nil {
+			return nil, selector, err
+		}
+		return azs, selector, nil
+	}
+
+	candidates, err := resourcesAzs(ctx, api, resources)
+	if err != nil {
+		return nil, selector, err
+	}
+	if selector.Random >= len(candidates) {
+		return nil, selector, fmt.Errorf("Cannot select %d random AZs: target resources only use AZs %s",
+			selector.Random, candidates)
+	}
+
+	if selector.Seed == nil {
+		seed := time.Now().UnixNano()
+		selector.Seed = &seed
+	}
+	azs := randomAzs(candidates, selector.Random, *selector.Seed)
+	slog.Info("Selected random availability zones", "azs", azs, "candidates", candidates, "seed", *selector.Seed)
+	return azs, selector, nil
+}
+
+// Returns all availability zones the resources use except the `surviving` ones, which
+// can be AZ names or AZ IDs
+func azsExcept(ctx context.Context, api awsapis.Ec2Api, surviving []string,
+	resources []domain.ConsistentStateResource) ([]string, error) {
+
+	survivingNames, err := awsutils.ResolveAzNames(ctx, api, surviving)
+	if err != nil {
+		return nil, err
+	}
+	used, err := resourcesAzs(ctx, api, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	azs := []string{}
+	for _, az := range used {
+		if !slices.Contains(survivingNames, az) {
+			azs = append(azs, az)
+		}
+	}
+	if len(azs) == 0 {
+		return nil, fmt.Errorf("No AZs to fail: target resources only use surviving AZs %s", survivingNames)
+	}
+	slog.Info("Failing all availability zones except surviving ones", "azs", azs, "survivingAzs", survivingNames)
+	return azs, nil
+}
+
+// Returns the sorted list of availability zones the resources currently use
+func resourcesAzs(ctx context.Context, api awsapis.Ec2Api, resources []domain.ConsistentStateResource) ([]string, error) {
+	subnets := []string{}
+	for _, resource := range resources {
+		plan, err := resource.Plan(ctx, []string{})
+		if err != nil {
+			return nil, fmt.Errorf("Could not find the AZs used by %s %s: %w",
+				resource.ResourceType(), resource.ResourceKey(), err)
+		}
+		for _, subnet := range plan.CurrentSubnets {
+			if !slices.Contains(subnets, subnet) {
+				subnets = append(subnets, subnet)
+			}
+		}
+	}
+	if len(subnets) == 0 {
+		return []string{}, nil
+	}
+	return awsutils.SubnetsAzs(ctx, api, subnets)
+}
+
+// Returns `count` AZs picked from `azs` by a random generator initialized with `seed`
+func randomAzs(azs []string, count int, seed int64) []string {
+	candidates := slices.Clone(azs)
+	slices.Sort(candidates)
+
+	random := rand.New(rand.NewSource(seed))
+	random.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	selected := candidates[:count]
+	slices.Sort(selected)
+	return selected
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/mcastellin/aws-fail-az/awsapis_mocks"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRandomAzsShouldBeReproducibleWithSeed(t *testing.T) {
+	azs := []string{"us-east-1a", "us-east-1b", "us-east-1c", "us-east-1d"}
+
+	selected := randomAzs(azs, 2, 42)
+
+	assert.Len(t, selected, 2)
+	assert.Subset(t, azs, selected)
+	assert.Equal(t, selected, randomAzs([]string{"us-east-1d", "us-east-1c", "us-east-1b", "us-east-1a"}, 2, 42))
+}
+
+func TestSelectAzsShouldResolveConfiguredAzIds(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+	mockApi := awsapis_mocks.NewMockEc2Api(ctrl)
+
+	mockApi.EXPECT().DescribeAvailabilityZones(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(&ec2.DescribeAvailabilityZonesOutput{
+			AvailabilityZones: []types.AvailabilityZone{
+				{ZoneName: aws.String("us-east-1a"), ZoneId: aws.String("use1-az4")},
+			},
+		}, nil)
+
+	selector := domain.AzsSelector{List: []string{"use1-az4"}}
+	azs, selected, err := selectAzs(context.TODO(), mockApi, selector, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"us-east-1a"}, azs)
+	assert.Equal(t, selector, selected)
+}
+
+func TestSelectAzsShouldPickAmongTargetAzs(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+	mockApi := awsapis_mocks.NewMockEc2Api(ctrl)
+
+	mockApi.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []types.Subnet{
+				{SubnetId: aws.String("s-1"), AvailabilityZone: aws.String("us-east-1a")},
+				{SubnetId: aws.String("s-2"), AvailabilityZone: aws.String("us-east-1b")},
+			},
+		}, nil)
+
+	resources := []*fakeResource{
+		{key: "first", subnets: []string{"s-1", "s-2"}},
+		{key: "second", subnets: []string{"s-2"}},
+	}
+	azs, selected, err := selectAzs(context.TODO(), mockApi, domain.AzsSelector{Random: 1}, toResources(resources))
+
+	assert.Nil(t, err)
+	assert.Len(t, azs, 1)
+	assert.Subset(t, []string{"us-east-1a", "us-east-1b"}, azs)
+	assert.NotNil(t, selected.Seed)
+	assert.Equal(t, azs, randomAzs([]string{"us-east-1a", "us-east-1b"}, 1, *selected.Seed))
+}
+
+func TestSelectAzsShouldRefuseToFailAllTargetAzs(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+	mockApi := awsapis_mocks.NewMockEc2Api(ctrl)
+
+	mockApi.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []types.Subnet{
+				{SubnetId: aws.String("s-1"), AvailabilityZone: aws.String("us-east-1a")},
+			},
+		}, nil)
+
+	resources := []*fakeResource{{key: "first", subnets: []string{"s-1"}}}
+	_, _, err := selectAzs(context.TODO(), mockApi, domain.AzsSelector{Random: 1}, toResources(resources))
+
+	assert.NotNil(t, err)
+}
+
+func TestAzsExceptShouldFailAllOtherTargetAzs(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+	mockApi := awsapis_mocks.NewMockEc2Api(ctrl)
+
+	mockApi.EXPECT().DescribeAvailabilityZones(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(&ec2.DescribeAvailabilityZonesOutput{
+			AvailabilityZones: []types.AvailabilityZone{
+				{ZoneName: aws.String("us-east-1a"), ZoneId: aws.String("use1-az4")},
+				{ZoneName: aws.String("us-east-1b"), ZoneId: aws.String("use1-az6")},
+				{ZoneName: aws.String("us-east-1c"), ZoneId: aws.String("use1-az1")},
+			},
+		}, nil)
+	mockApi.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []types.Subnet{
+				{SubnetId: aws.String("s-1"), AvailabilityZone: aws.String("us-east-1a")},
+				{SubnetId: aws.String("s-2"), AvailabilityZone: aws.String("us-east-1b")},
+				{SubnetId: aws.String("s-3"), AvailabilityZone: aws.String("us-east-1c")},
+			},
+		}, nil)
+
+	resources := []*fakeResource{
+		{key: "first", subnets: []string{"s-1", "s-3"}},
+		{key: "second", subnets: []string{"s-2", "s-3"}},
+	}
+	azs, err := azsExcept(context.TODO(), mockApi, []string{"use1-az1"}, toResources(resources))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"us-east-1a", "us-east-1b"}, azs)
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/mcastellin/aws-fail-az/awsapis"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
+	"github.com/mcastellin/aws-fail-az/state"
+)
+
+// Returns a new unique experiment ID to use as namespace
+func newExperimentId() string {
+	return fmt.Sprintf("exp-%s-%06x", time.Now().UTC().Format("20060102-150405"), rand.Intn(1<<24))
+}
+
+// Returns the ARN of the AWS identity running the command
+func callerIdentity(ctx context.Context, api awsapis.StsApi) (string, error) {
+	output, err := api.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.Arn), nil
+}
+
+// Records the progress of an experiment in the state table.
+// All methods can be called on a nil tracker, in which case nothing is recorded
+type experimentTracker struct {
+	StateManager state.StateManager
+	Experiment   state.Experiment
+}
+
+// Creates the record of a new experiment failing `azs` started at `startTime` and saves it in the Fail phase
+func newExperimentTracker(ctx context.Context, provider awsapis.AWSProvider, stateManager state.StateManager,
+	config domain.FaultConfiguration, azs []string, startTime time.Time) *experimentTracker {
+
+	identity, err := callerIdentity(ctx, provider.NewStsApi())
+	if err != nil {
+		slog.Warn("Could not get the caller identity for the experiment record", logging.Err(err))
+		identity = "unknown"
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		slog.Warn("Could not add the configuration to the experiment record", logging.Err(err))
+	}
+
+	tracker := &experimentTracker{
+		StateManager: stateManager,
+		Experiment: state.Experiment{
+			Azs:            azs,
+			Config:         configJSON,
+			StartTime:      startTime,
+			CallerIdentity: identity,
+		},
+	}
+	tracker.SetPhase(ctx, state.ExperimentPhaseFail)
+	return tracker
+}
+
+// Returns a tracker for the experiment recorded in the namespace, or nil if the
+// namespace is not set or has no experiment record
+func loadExperimentTracker(ctx context.Context, stateManager state.StateManager, namespace string) *experimentTracker {
+	if namespace == "" {
+		return nil
+	}
+	experiments, err := stateManager.QueryExperiments(ctx, namespace)
+	if err != nil {
+		slog.Warn("Could not read the experiment record", logging.Err(err))
+		return nil
+	}
+	if len(experiments) == 0 {
+		return nil
+	}
+	return &experimentTracker{StateManager: stateManager, Experiment: experiments[0]}
+}
+
+// Records the experiment is active again after being resumed in the Fail phase
+func (t *experimentTracker) Resume(ctx context.Context) {
+	if t == nil {
+		return
+	}
+	t.Experiment.EndTime = nil
+	t.Experiment.Outcome = ""
+	t.Experiment.Error = ""
+	t.SetPhase(ctx, state.ExperimentPhaseFail)
+}
+
+// Records the experiment has moved to a new phase
+func (t *experimentTracker) SetPhase(ctx context.Context, phase string) {
+	if t == nil {
+		return
+	}
+	t.Experiment.Phase = phase
+	t.save(ctx)
+}
+
+// Records the experiment has completed once no resource state remains in the namespace,
+// meaning every failed resource has been restored. Otherwise the experiment stays active
+// in its current phase so that it can be recovered
+func (t *experimentTracker) FinishIfRestored(ctx context.Context, err error) {
+	if t == nil {
+		return
+	}
+	states, queryErr := t.StateManager.QueryStates(ctx, &state.QueryStatesInput{})
+	if queryErr != nil {
+		slog.Warn("Could not read resource states for the experiment record", logging.Err(queryErr))
+		return
+	}
+	if len(states) > 0 {
+		slog.Warn("Experiment is still active until resources are recovered", "phase", t.Experiment.Phase,
+			"states", len(states))
+		return
+	}
+	t.Finish(ctx, err)
+}
+
+// Records the experiment has completed. An experiment that failed in a previous
+// command keeps its failed outcome
+func (t *experimentTracker) Finish(ctx context.Context, err error) {
+	if t == nil {
+		return
+	}
+	endTime := time.Now()
+	t.Experiment.EndTime = &endTime
+	t.Experiment.Phase = state.ExperimentPhaseDone
+	if err != nil {
+		t.Experiment.Outcome = state.ExperimentOutcomeFailed
+		t.Experiment.Error = err.Error()
+	} else if t.Experiment.Outcome == "" {
+		t.Experiment.Outcome = state.ExperimentOutcomeSucceeded
+	}
+	t.save(ctx)
+}
+
+// Saves the experiment record. Failing to save the record does not stop the experiment
+func (t *experimentTracker) save(ctx context.Context) {
+	err := t.StateManager.SaveExperiment(context.WithoutCancel(ctx), t.Experiment)
+	if err != nil {
+		slog.Warn("Could not save the experiment record", logging.KeyPhase, t.Experiment.Phase, logging.Err(err))
+	}
+}
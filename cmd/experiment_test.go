@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/mcastellin/aws-fail-az/awsapis_mocks"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/state"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExperimentTrackerShouldRecordExperimentLifecycle(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockSts := awsapis_mocks.NewMockStsApi(ctrl)
+	mockProvider.EXPECT().NewStsApi().Return(mockSts)
+	mockSts.EXPECT().GetCallerIdentity(gomock.Any(), gomock.Any()).
+		Return(&sts.GetCallerIdentityOutput{Arn: aws.String("arn:aws:iam::123456789012:user/operator")}, nil)
+
+	stateManager := newFakeStateManager()
+	config := domain.FaultConfiguration{Azs: domain.AzsSelector{Random: 1}}
+
+	tracker := newExperimentTracker(context.TODO(), mockProvider, stateManager, config, []string{"us-east-1a"}, time.Now())
+	tracker.SetPhase(context.TODO(), state.ExperimentPhaseHold)
+	tracker.Finish(context.TODO(), nil)
+
+	assert.Len(t, stateManager.experiments, 3)
+	started := stateManager.experiments[0]
+	assert.Equal(t, state.ExperimentPhaseFail, started.Phase)
+	assert.Equal(t, "arn:aws:iam::123456789012:user/operator", started.CallerIdentity)
+	assert.Equal(t, []string{"us-east-1a"}, started.Azs)
+	assert.Contains(t, string(started.Config), `"azs":{"random":1}`)
+	assert.True(t, started.IsActive())
+
+	finished := stateManager.experiments[2]
+	assert.Equal(t, state.ExperimentPhaseDone, finished.Phase)
+	assert.Equal(t, state.ExperimentOutcomeSucceeded, finished.Outcome)
+	assert.NotNil(t, finished.EndTime)
+}
+
+func TestExperimentTrackerShouldKeepFailedOutcome(t *testing.T) {
+	stateManager := newFakeStateManager()
+	stateManager.SaveExperiment(context.TODO(), state.Experiment{Namespace: "exp-1", Phase: state.ExperimentPhaseHold})
+
+	tracker := loadExperimentTracker(context.TODO(), stateManager, "exp-1")
+	tracker.Finish(context.TODO(), fmt.Errorf("verification failed"))
+	tracker.Finish(context.TODO(), nil)
+
+	assert.Equal(t, state.ExperimentOutcomeFailed, tracker.Experiment.Outcome)
+	assert.Equal(t, "verification failed", tracker.Experiment.Error)
+
+	tracker.Resume(context.TODO())
+	assert.True(t, tracker.Experiment.IsActive())
+	assert.Equal(t, state.ExperimentPhaseFail, tracker.Experiment.Phase)
+
+	assert.Nil(t, loadExperimentTracker(context.TODO(), stateManager, ""))
+	var nilTracker *experimentTracker
+	nilTracker.SetPhase(context.TODO(), state.ExperimentPhaseRestore)
+	nilTracker.Finish(context.TODO(), nil)
+	nilTracker.FinishIfRestored(context.TODO(), nil)
+}
+
+func TestExperimentTrackerShouldStayActiveWhenFailureIsNotRolledBack(t *testing.T) {
+	stateManager := newFakeStateManager()
+	tracker := &experimentTracker{StateManager: stateManager}
+	tracker.SetPhase(context.TODO(), state.ExperimentPhaseFail)
+	resources := []*fakeResource{
+		{key: "first"},
+		{key: "second", failErr: fmt.Errorf("fail error")},
+	}
+
+	err := failResources(context.TODO(), stateManager, toResources(resources),
+		failOptions{Azs: []string{"us-east-1a"}, Rollback: false})
+	tracker.FinishIfRestored(context.TODO(), err)
+
+	assert.NotNil(t, err)
+	assert.Len(t, stateManager.states, 2)
+	assert.True(t, tracker.Experiment.IsActive())
+	assert.Equal(t, state.ExperimentPhaseFail, tracker.Experiment.Phase)
+	assert.Nil(t, tracker.Experiment.EndTime)
+}
+
+func TestExperimentTrackerShouldFinishWhenFailureIsRolledBack(t *testing.T) {
+	stateManager := newFakeStateManager()
+	tracker := &experimentTracker{StateManager: stateManager}
+	tracker.SetPhase(context.TODO(), state.ExperimentPhaseFail)
+	resources := []*fakeResource{
+		{key: "first"},
+		{key: "second", failErr: fmt.Errorf("fail error")},
+	}
+
+	err := failResources(context.TODO(), stateManager, toResources(resources),
+		failOptions{Azs: []string{"us-east-1a"}, Rollback: true})
+	tracker.FinishIfRestored(context.TODO(), err)
+
+	assert.NotNil(t, err)
+	assert.Len(t, stateManager.states, 0)
+	assert.Equal(t, state.ExperimentPhaseDone, tracker.Experiment.Phase)
+	assert.Equal(t, state.ExperimentOutcomeFailed, tracker.Experiment.Outcome)
+}
+
+func TestPrintExperimentsShouldListActiveExperimentsFirst(t *testing.T) {
+	now := time.Now()
+	experiments := []state.Experiment{
+		{Namespace: "exp-old", StartTime: now.Add(-2 * time.Hour), Outcome: state.ExperimentOutcomeSucceeded},
+		{Namespace: "exp-new", StartTime: now.Add(-time.Hour), Outcome: state.ExperimentOutcomeFailed},
+		{Namespace: "exp-active", StartTime: now.Add(-3 * time.Hour), Phase: state.ExperimentPhaseHold,
+			Azs: []string{"us-east-1a", "us-east-1b"}},
+	}
+
+	var output bytes.Buffer
+	err := printExperiments(&output, experiments)
+
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.Contains(t, lines[1], "exp-active")
+	assert.Contains(t, lines[1], "active")
+	assert.Contains(t, lines[1], "us-east-1a,us-east-1b")
+	assert.Contains(t, lines[2], "exp-new")
+	assert.Contains(t, lines[2], "failed")
+	assert.Contains(t, lines[3], "exp-old")
+}
+
+func TestFailPlanShouldNotGenerateNamespace(t *testing.T) {
+	cmd := &FailCommand{Plan: true, ConfigFile: "does-not-exist.json"}
+	err := cmd.Run(context.TODO())
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "", cmd.Namespace)
+}
@@ -3,17 +3,21 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"os"
 	"sync"
 	"time"
 
 	"github.com/mcastellin/aws-fail-az/awsapis"
 	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
+	"github.com/mcastellin/aws-fail-az/report"
 	"github.com/mcastellin/aws-fail-az/service"
 	"github.com/mcastellin/aws-fail-az/state"
+	"golang.org/x/exp/slices"
 )
 
 type FailCommand struct {
@@ -21,101 +25,584 @@ type FailCommand struct {
 	Namespace     string
 	ReadFromStdin bool
 	ConfigFile    string
+	Plan          bool
+	Duration      time.Duration
+	NoRollback    bool
+	Parallelism   int
+	Synchronized  bool
+	// Keep the states saved by an interrupted run and fail the remaining resources
+	Resume bool
+	// Write a report of the experiment to this file when set
+	ReportFile string
+
+	// The maximum time to wait for resources to reach a stable state before failure
+	StabilityTimeout time.Duration
 }
 
-func (cmd *FailCommand) Run() error {
+// The default time to wait for resources to reach a stable state before failure
+const DEFAULT_STABILITY_TIMEOUT = 15 * time.Minute
 
-	var configContent []byte
-	var err error
-	if cmd.ReadFromStdin {
-		configContent, err = io.ReadAll(os.Stdin)
+// Backoff delays between consecutive resource state checks
+var (
+	checkInitialDelay = 5 * time.Second
+	checkMaxDelay     = 1 * time.Minute
+)
+
+func (cmd *FailCommand) Run(ctx context.Context) error {
+	if cmd.Resume && cmd.Namespace == "" {
+		return fmt.Errorf("The namespace of the experiment to resume must be set with --ns")
+	}
+	// Plans do not save any state, so they do not need a namespace
+	if cmd.Namespace == "" && !cmd.Plan {
+		cmd.Namespace = newExperimentId()
+		slog.SetDefault(slog.Default().With(logging.KeyNamespace, cmd.Namespace))
+		slog.Info("Generated a new experiment namespace. Use it with --ns to recover or resume this experiment")
+	}
+
+	var recorder *report.Recorder
+	if cmd.ReportFile != "" {
+		recorder = report.NewRecorder("fail", cmd.Namespace)
+	}
+
+	err := cmd.run(ctx, recorder)
+	return errors.Join(err, writeReport(recorder, err, cmd.ReportFile))
+}
+
+func (cmd *FailCommand) run(ctx context.Context, recorder *report.Recorder) (err error) {
+	startTime := time.Now()
+
+	faultConfig, err := readFaultConfiguration(cmd.ReadFromStdin, cmd.ConfigFile)
+	if err != nil {
+		return err
+	}
+	// Alarms are only watched while the command is running, so an experiment that
+	// outlives the command would not be stopped when a stop condition is met
+	if len(faultConfig.StopConditions) > 0 && cmd.Duration == 0 && !cmd.Plan {
+		return fmt.Errorf("ERROR: The --duration flag must be set when stop conditions are configured")
+	}
+	// Probes are only sampled while the command is running, so an experiment that outlives
+	// the command would not be monitored during the AZ failure and after recovery
+	if len(faultConfig.Probes) > 0 && cmd.Duration == 0 && !cmd.Plan {
+		return fmt.Errorf("ERROR: The --duration flag must be set when probes are configured")
+	}
+
+	stages := []experimentStage{}
+	faultTypes := service.InitServiceFaults()
+	for _, stageConfig := range faultConfig.ExperimentStages() {
+		stage := experimentStage{FaultStage: stageConfig}
+		for _, target := range stageConfig.Targets {
+			targetConfigs, err := faultTypes.NewResourceForType(ctx, target, cmd.Provider)
+			if err != nil {
+				return err
+			}
+			stage.Resources = append(stage.Resources, targetConfigs...)
+		}
+		stages = append(stages, stage)
+	}
+
+	var stateManager state.StateManager
+	var tracker *experimentTracker
+	var resumed map[domain.ConsistentStateResource]bool
+	if cmd.Resume {
+		stateManager, err = cmd.initStateManager(ctx)
+		if err != nil {
+			return err
+		}
+		resumed, err = resumeStages(ctx, stateManager, stages, func(s state.ResourceState) (domain.ConsistentStateResource, error) {
+			return faultTypes.NewResourceFromState(s, cmd.Provider)
+		})
+		if err != nil {
+			return err
+		}
+		tracker = loadExperimentTracker(ctx, stateManager, cmd.Namespace)
+	}
+
+	allServices := make([]domain.ConsistentStateResource, 0)
+	for _, stage := range stages {
+		allServices = append(allServices, stage.Resources...)
+	}
+
+	// A resumed experiment fails the AZs selected by the interrupted run, as the AZs
+	// used by resources already failed have changed
+	var azs []string
+	if tracker != nil {
+		azs = tracker.Experiment.Azs
+	} else if len(faultConfig.SurvivingAzs) > 0 {
+		azs, err = azsExcept(ctx, cmd.Provider.NewEc2Api(), faultConfig.SurvivingAzs, allServices)
 		if err != nil {
 			return err
 		}
 	} else {
-		configContent, err = os.ReadFile(cmd.ConfigFile)
+		azs, faultConfig.Azs, err = selectAzs(ctx, cmd.Provider.NewEc2Api(), faultConfig.Azs, allServices)
 		if err != nil {
 			return err
 		}
 	}
+	recorder.SetAzs(azs)
+	slog.Info("Failing availability zones", "azs", azs)
 
-	var faultConfig domain.FaultConfiguration
-	err = json.Unmarshal(configContent, &faultConfig)
-	if err != nil {
-		return err
+	hooks := &lifecycleHooks{
+		Config:    faultConfig.Hooks,
+		Namespace: cmd.Namespace,
+		Azs:       azs,
+		Resources: hookResourcesFromResources(allServices),
+	}
+	// Plans do not change any resource, so no hook is run for them
+	if !cmd.Plan {
+		if err := hooks.Run(ctx, HookPhasePreCheck); err != nil {
+			return err
+		}
 	}
 
-	log.Printf("Failing availability zones %s", faultConfig.Azs)
+	var prober *probeRunner
+	if len(faultConfig.Probes) > 0 {
+		prober, err = newProbeRunner(faultConfig.Probes)
+		if err != nil {
+			return fmt.Errorf("ERROR: %w", err)
+		}
+		// Probe results are added to the report however the experiment ends
+		defer func() { recorder.SetProbes(prober.Report()) }()
+		slog.Info("Sampling probes before AZ failure")
+		if err := prober.SampleOnce(ctx, ProbePhaseBefore); err != nil {
+			return fmt.Errorf("ERROR: Probes failed before AZ failure: %w", err)
+		}
+	}
 
-	stateManager, err := state.NewStateManager(cmd.Provider, cmd.Namespace)
+	slog.Info("Checking resources state is stable before AZ failure")
+	stabilityTimeout := cmd.StabilityTimeout
+	if stabilityTimeout == 0 {
+		stabilityTimeout = DEFAULT_STABILITY_TIMEOUT
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, stabilityTimeout)
+	defer cancel()
+
+	// Resources resumed from a previous run may already be degraded by the AZ failure
+	// and would not pass the checks
+	checkedServices := []domain.ConsistentStateResource{}
+	for _, svc := range allServices {
+		if !resumed[svc] {
+			checkedServices = append(checkedServices, svc)
+		}
+	}
+	err = checkResourceStates(checkCtx, checkedServices, recorder)
 	if err != nil {
-		log.Print("Failed to create AWS state manager")
 		return err
 	}
 
-	if err := stateManager.Initialize(); err != nil {
-		return err
+	var watcher *stopConditionsWatcher
+	if len(faultConfig.StopConditions) > 0 {
+		watcher = newStopConditionsWatcher(cmd.Provider.NewCloudWatchApi(), faultConfig.StopConditions)
+		if err := watcher.Check(ctx); err != nil {
+			return fmt.Errorf("ERROR: Stop conditions check failed before AZ failure: %w", err)
+		}
 	}
 
-	allServices := make([]domain.ConsistentStateResource, 0)
+	if cmd.Plan {
+		return planStages(ctx, stages, azs)
+	}
 
-	faultTypes := service.InitServiceFaults()
-	for _, target := range faultConfig.Targets {
-		targetConfigs, err := faultTypes.NewResourceForType(target, cmd.Provider)
+	if stateManager == nil {
+		stateManager, err = cmd.initStateManager(ctx)
 		if err != nil {
 			return err
 		}
-		allServices = append(allServices, targetConfigs...)
+	}
 
+	// The experiment is recorded as completed once all resources have been restored, by
+	// rollback or recovery, or none was failed. Otherwise it stays active until resources are recovered
+	if tracker != nil {
+		tracker.Resume(ctx)
+	} else {
+		tracker = newExperimentTracker(ctx, cmd.Provider, stateManager, faultConfig, azs, startTime)
 	}
+	defer func() { tracker.FinishIfRestored(context.WithoutCancel(ctx), err) }()
 
-	log.Println("INFO: Checking resources state is stable before AZ failure.")
-	ctx, cancel := context.WithDeadline(context.TODO(), time.Now().Add(15*time.Minute))
-	defer cancel()
+	if err := hooks.Run(ctx, HookPhasePreFail); err != nil {
+		return err
+	}
+
+	opts := failOptions{
+		Azs:      azs,
+		Rollback: !cmd.NoRollback,
+		Retry:    retryPolicyOrDefault(faultConfig.Retry),
+
+		Parallelism:   cmd.Parallelism,
+		Synchronized:  cmd.Synchronized,
+		StageRollback: faultConfig.RollbackOnStageFailure,
+		Resumed:       resumed,
+		Report:        recorder,
+	}
+
+	// The experiment context is cancelled as soon as a stop condition is met
+	// or a probe configured to abort the experiment breaks its threshold
+	experimentCtx, stopExperiment := context.WithCancelCause(ctx)
+	defer stopExperiment(nil)
+	if watcher != nil {
+		go watcher.Watch(experimentCtx, stopExperiment)
+	}
+	stopProbes := func() {}
+	if prober != nil {
+		stopProbes = prober.StartMonitor(experimentCtx, stopExperiment)
+	}
+
+	failedAt, err := runStages(experimentCtx, stateManager, stages, opts)
+	if !failedAt.IsZero() {
+		tracker.SetPhase(ctx, state.ExperimentPhaseHold)
+		err = errors.Join(err, hooks.Run(ctx, HookPhasePostFail))
+	}
+	if cmd.Duration > 0 {
+		if failedAt.IsZero() {
+			slog.Error("AZ failure did not complete, recovering resources from state table")
+		} else {
+			holdFailure(experimentCtx, cmd.Duration-time.Since(failedAt))
+		}
+	}
+	stopProbes()
+
+	stopErr := experimentStopCause(experimentCtx)
+	if stopErr != nil && !errors.Is(err, stopErr) {
+		err = errors.Join(err, stopErr)
+	}
+
+	if cmd.Duration > 0 || stopErr != nil {
+		// Make sure the experiment never outlives the session that started it by
+		// restoring resources even when the context was cancelled by an interrupt signal.
+		// For the same reason, a failing preRecover hook does not prevent recovery
+		slog.Info("Recovering resources from state table")
+		tracker.SetPhase(ctx, state.ExperimentPhaseRestore)
+		restoreCtx := context.WithoutCancel(ctx)
+		err = errors.Join(err, hooks.Run(restoreCtx, HookPhasePreRecover))
+		err = errors.Join(err, restoreFromStates(restoreCtx, cmd.Provider, stateManager,
+			&state.QueryStatesInput{}, opts.Retry, recorder))
+		err = errors.Join(err, hooks.Run(restoreCtx, HookPhasePostRecover))
+
+		if prober != nil {
+			slog.Info("Sampling probes after recovery")
+			prober.SampleAfterRecovery(restoreCtx)
+		}
+	}
+
+	if prober != nil {
+		err = errors.Join(err, prober.Evaluate())
+	}
+	return err
+}
+
+// Returns the initialized state manager for the command namespace
+func (cmd *FailCommand) initStateManager(ctx context.Context) (state.StateManager, error) {
+	stateManager, err := state.NewStateManager(cmd.Provider, cmd.Namespace)
+	if err != nil {
+		slog.Error("Failed to create AWS state manager", logging.Err(err))
+		return nil, err
+	}
+	if err := stateManager.Initialize(ctx); err != nil {
+		return nil, err
+	}
+	return stateManager, nil
+}
+
+// Reads and validates the fault configuration from stdin or from the configuration file
+func readFaultConfiguration(readFromStdin bool, configFile string) (domain.FaultConfiguration, error) {
+	var faultConfig domain.FaultConfiguration
+
+	var configContent []byte
+	var err error
+	if readFromStdin {
+		configContent, err = io.ReadAll(os.Stdin)
+	} else {
+		configContent, err = os.ReadFile(configFile)
+	}
+	if err != nil {
+		return faultConfig, err
+	}
 
-	err = checkResourceStates(ctx, allServices)
+	err = json.Unmarshal(configContent, &faultConfig)
 	if err != nil {
+		return faultConfig, err
+	}
+	return faultConfig, faultConfig.Validate()
+}
+
+// Returns the error that stopped the experiment early if a stop condition
+// was met or a probe broke its threshold, nil otherwise
+func experimentStopCause(ctx context.Context) error {
+	cause := context.Cause(ctx)
+	var stopErr stopConditionError
+	var probeErr probeAbortError
+	if errors.As(cause, &stopErr) || errors.As(cause, &probeErr) {
+		return cause
+	}
+	return nil
+}
+
+// Options for failing availability zones on a set of resources
+type failOptions struct {
+	// The availability zones to fail
+	Azs []string
+	// Restore failed resources if any of the operations fails
+	Rollback bool
+	// Retry policy for resource activities failing with temporary errors
+	Retry domain.RetryPolicy
+	// The maximum number of resources saved and failed concurrently
+	Parallelism int
+	// Fail all resources at once after their states have been saved
+	Synchronized bool
+	// Restore resources failed in previous stages when a stage fails
+	StageRollback bool
+	// Resources resumed from the states saved by a previous run, which are not saved again
+	Resumed map[domain.ConsistentStateResource]bool
+	// Records the phases of every resource for the experiment report
+	Report *report.Recorder
+}
+
+// Saves the state of all resources, except those resumed from a previous run, and fails
+// the availability zones in `opts.Azs`. When rollback is enabled and any of the operations
+// fails, every resource already failed in this run and every resumed resource is restored
+// and the states of the saved and resumed resources are removed
+func failResources(ctx context.Context, stateManager state.StateManager, resources []domain.ConsistentStateResource,
+	opts failOptions) error {
+
+	parallelism := max(opts.Parallelism, 1)
+
+	slog.Info("Saving resources' states in state table")
+	_, saved, err := forEachResource(ctx, report.PhaseSave, resources, parallelism, func(svc domain.ConsistentStateResource) error {
+		if opts.Resumed[svc] {
+			return nil
+		}
+		done := opts.Report.StartPhase(svc.ResourceType(), svc.ResourceKey(), report.PhaseSave)
+		err := retryActivity(ctx, opts.Retry, activityLogger(svc, report.PhaseSave), func() error {
+			return svc.Save(ctx, stateManager)
+		})
+		done(err)
+		if err == nil {
+			recordPlan(ctx, svc, opts)
+		}
 		return err
+	})
+
+	// Resources are rolled back even when Fail returns an error
+	// as the failure may have been partially applied
+	failed := []domain.ConsistentStateResource{}
+	if err == nil {
+		applyFn := func(svc domain.ConsistentStateResource, fail func(context.Context) error) error {
+			done := opts.Report.StartPhase(svc.ResourceType(), svc.ResourceKey(), report.PhaseFail)
+			err := retryActivity(ctx, opts.Retry, activityLogger(svc, report.PhaseFail), func() error {
+				return fail(ctx)
+			})
+			done(err)
+			if err == nil {
+				recordSubnetsAfter(ctx, svc, opts)
+			}
+			return err
+		}
+
+		if opts.Synchronized {
+			slog.Info("Failing configured AZs on all resources at once", "resources", len(resources))
+			failed, err = forEachResourceSynchronized(ctx, report.PhaseFail, resources,
+				func(svc domain.ConsistentStateResource) (func() error, error) {
+					fail, err := prepareFailure(ctx, svc, opts)
+					if err != nil {
+						return nil, err
+					}
+					return func() error { return applyFn(svc, fail) }, nil
+				})
+		} else {
+			slog.Info("Failing configured AZs")
+			failed, _, err = forEachResource(ctx, report.PhaseFail, resources, parallelism, func(svc domain.ConsistentStateResource) error {
+				return applyFn(svc, func(ctx context.Context) error { return svc.Fail(ctx, opts.Azs) })
+			})
+		}
 	}
 
-	log.Println("INFO: Saving resources' states in state table.")
-	for _, svc := range allServices {
-		err = svc.Save(stateManager)
+	if err == nil || !opts.Rollback {
+		return err
+	}
+
+	// Resumed resources may have been failed by the interrupted run even if they were
+	// not failed by this one, so they are restored before their states are removed
+	for _, svc := range resources {
+		if opts.Resumed[svc] && !slices.Contains(failed, svc) {
+			failed = append(failed, svc)
+		}
+	}
+
+	// Rollback must complete even if the failure was interrupted by cancelling the context
+	slog.Error("AZ failure did not complete, rolling back", logging.Err(err))
+	rollbackCtx := context.WithoutCancel(ctx)
+	return errors.Join(err, rollbackResources(rollbackCtx, stateManager, saved, failed, opts.Retry, opts.Report))
+}
+
+// Returns the function that fails the resource. Resources implementing domain.FailurePreparer
+// describe their failure here, so that the returned function only issues the calls modifying them
+func prepareFailure(ctx context.Context, svc domain.ConsistentStateResource, opts failOptions) (func(context.Context) error, error) {
+	preparer, ok := svc.(domain.FailurePreparer)
+	if !ok {
+		return func(ctx context.Context) error { return svc.Fail(ctx, opts.Azs) }, nil
+	}
+
+	var fail func(context.Context) error
+	err := retryActivity(ctx, opts.Retry, activityLogger(svc, report.PhaseFail), func() error {
+		var err error
+		fail, err = preparer.PrepareFail(ctx, opts.Azs)
+		return err
+	})
+	return fail, err
+}
+
+// Records the subnets of the resource before AZ failure and the changes planned for it in the
+// experiment report. Plans are recorded while saving states so that they do not delay failure
+func recordPlan(ctx context.Context, svc domain.ConsistentStateResource, opts failOptions) {
+	if opts.Report == nil {
+		return
+	}
+	plan, err := svc.Plan(ctx, opts.Azs)
+	if err != nil {
+		logging.ForResource(svc.ResourceType(), svc.ResourceKey()).Warn(
+			"Could not record planned changes in report", logging.Err(err))
+		return
+	}
+	opts.Report.SetPlan(plan)
+}
+
+// Records the subnets of the resource observed once AZ failure has been applied in the experiment report
+func recordSubnetsAfter(ctx context.Context, svc domain.ConsistentStateResource, opts failOptions) {
+	if opts.Report == nil {
+		return
+	}
+	plan, err := svc.Plan(ctx, opts.Azs)
+	if err != nil {
+		logging.ForResource(svc.ResourceType(), svc.ResourceKey()).Warn(
+			"Could not record subnets after failure in report", logging.Err(err))
+		return
+	}
+	opts.Report.SetSubnetsAfter(svc.ResourceType(), svc.ResourceKey(), plan.CurrentSubnets)
+}
+
+// Restores all resources in `failed` and removes the states of the resources in `saved`.
+// Resources that could not be restored keep their state so they can be recovered later
+func rollbackResources(ctx context.Context, stateManager state.StateManager, saved []domain.ConsistentStateResource,
+	failed []domain.ConsistentStateResource, retry domain.RetryPolicy, recorder *report.Recorder) error {
+
+	errs := []error{}
+	notRestored := map[domain.ConsistentStateResource]bool{}
+	rolledBack := []string{}
+
+	for _, svc := range failed {
+		done := recorder.StartPhase(svc.ResourceType(), svc.ResourceKey(), report.PhaseRestore)
+		err := retryActivity(ctx, retry, activityLogger(svc, report.PhaseRestore), func() error {
+			return svc.Restore(ctx)
+		})
+		done(err)
 		if err != nil {
-			return err
+			errs = append(errs, fmt.Errorf("rollback failed for %s %s: %w", svc.ResourceType(), svc.ResourceKey(), err))
+			notRestored[svc] = true
+			continue
 		}
+		rolledBack = append(rolledBack, fmt.Sprintf("%s %s", svc.ResourceType(), svc.ResourceKey()))
 	}
 
-	log.Println("INFO: Failing configured AZs.")
-	for _, svc := range allServices {
-		err = svc.Fail(faultConfig.Azs)
+	for _, svc := range saved {
+		if notRestored[svc] {
+			continue
+		}
+		resourceState, err := stateManager.GetState(ctx, svc.ResourceType(), svc.ResourceKey())
+		if err == nil {
+			err = stateManager.RemoveState(ctx, *resourceState)
+		}
 		if err != nil {
-			return err
+			errs = append(errs, fmt.Errorf("removing state for %s %s: %w", svc.ResourceType(), svc.ResourceKey(), err))
+		}
+	}
+
+	slog.Info("Rolled back failed resources", "rolledBack", len(rolledBack), "failed", len(failed),
+		"resources", rolledBack)
+	if len(notRestored) > 0 {
+		slog.Error("Resources could not be rolled back, their states were kept for recovery",
+			"resources", len(notRestored))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Blocks until the experiment duration has elapsed or the context is cancelled
+func holdFailure(ctx context.Context, duration time.Duration) {
+	if duration <= 0 {
+		return
+	}
+	slog.Info("Holding AZ failure, send an interrupt signal to recover early", "duration", duration)
+
+	select {
+	case <-time.After(duration):
+		slog.Info("Experiment duration elapsed")
+	case <-ctx.Done():
+		slog.Info("Experiment interrupted, recovering early", "cause", context.Cause(ctx))
+	}
+}
+
+// Prints the changes that would be applied to every resource when failing the
+// availability zones in `azs` without modifying the resources
+func planResources(ctx context.Context, resources []domain.ConsistentStateResource, azs []string) error {
+	fmt.Printf("Plan: failing availability zones %s\n", azs)
+
+	failedCount := 0
+	for _, resource := range resources {
+		fmt.Printf("\n%s %s\n", resource.ResourceType(), resource.ResourceKey())
+
+		plan, err := resource.Plan(ctx, azs)
+		if err != nil {
+			fmt.Printf("  error: %v\n", err)
+			failedCount++
+			continue
+		}
+		fmt.Printf("  current subnets: %s\n", plan.CurrentSubnets)
+		fmt.Printf("  new subnets:     %s\n", plan.NewSubnets)
+		if len(plan.Terminations) > 0 {
+			fmt.Printf("  stop/terminate:  %s\n", plan.Terminations)
 		}
 	}
 
+	if failedCount > 0 {
+		return fmt.Errorf("ERROR: %d of %d resources could not be planned", failedCount, len(resources))
+	}
 	return nil
 }
 
-func checkResourceStates(ctx context.Context, resources []domain.ConsistentStateResource) error {
-	checkResults := make(chan bool, len(resources))
+// Prints the changes that would be applied to the resources of every stage
+func planStages(ctx context.Context, stages []experimentStage, azs []string) error {
+	if len(stages) == 1 {
+		return planResources(ctx, stages[0].Resources, azs)
+	}
+
+	errs := []error{}
+	for idx, stage := range stages {
+		fmt.Printf("\n=== %s\n", stage.describe(idx, len(stages)))
+		if err := planResources(ctx, stage.Resources, azs); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Polls the state of all resources until they are stable or the context deadline expires.
+// An InterruptExecutionError from any resource stops the checks on all resources
+func checkResourceStates(ctx context.Context, resources []domain.ConsistentStateResource, recorder *report.Recorder) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	checkResults := make(chan error, len(resources))
 
 	wg := new(sync.WaitGroup)
 	for _, resource := range resources {
 		wg.Add(1)
 		go func(resource domain.ConsistentStateResource) {
 			defer wg.Done()
-			select {
-			case <-ctx.Done():
-				checkResults <- false
-			default:
-				isValid, err := resource.Check()
-				if err != nil {
-					log.Println(err)
-					isValid = false
-				}
-				checkResults <- isValid
+			done := recorder.StartPhase(resource.ResourceType(), resource.ResourceKey(), report.PhaseCheck)
+			err := waitUntilStable(ctx, resource)
+			done(err)
+			if isInterrupt(err) {
+				cancel(err)
 			}
+			checkResults <- err
 		}(resource)
 	}
 
@@ -123,13 +610,62 @@ func checkResourceStates(ctx context.Context, resources []domain.ConsistentState
 	close(checkResults)
 
 	validCount := 0
-	for isValid := range checkResults {
-		if isValid {
+	for err := range checkResults {
+		if err == nil {
 			validCount++
 		}
 	}
+	if interruptErr := context.Cause(ctx); isInterrupt(interruptErr) {
+		return fmt.Errorf("ERROR: Resource state checks interrupted: %w", interruptErr)
+	}
 	if validCount < len(resources) {
 		return fmt.Errorf("ERROR: One or more resources failed state checks")
 	}
 	return nil
 }
+
+// Checks the resource state with exponential backoff until the resource is stable.
+// Returns an error if the resource is not stable before the context deadline expires
+func waitUntilStable(ctx context.Context, resource domain.ConsistentStateResource) error {
+	return waitUntil(ctx, resource, report.PhaseCheck, "stable state", func() (bool, error) {
+		return resource.Check(ctx)
+	})
+}
+
+// Polls `checkFn` with exponential backoff until it succeeds.
+// Returns an error if the context deadline expires before the condition is met, or
+// as soon as `checkFn` fails with an error that cannot be resolved by waiting
+func waitUntil(ctx context.Context, resource domain.ConsistentStateResource, phase string,
+	condition string, checkFn func() (bool, error)) error {
+
+	logger := activityLogger(resource, phase).With("condition", condition)
+	delay := checkInitialDelay
+	for {
+		isValid, err := checkFn()
+		if isValid && err == nil {
+			return nil
+		}
+
+		reason := fmt.Sprintf("resource has not reached %s", condition)
+		if err != nil {
+			reason = err.Error()
+		}
+
+		var activityErr domain.ActivityFailedError
+		if isInterrupt(err) || (errors.As(err, &activityErr) && !activityErr.IsTemporary()) {
+			logger.Error("Stopped waiting for condition", "reason", reason)
+			return err
+		}
+
+		logger.Info("Waiting for condition", "nextCheckIn", delay, "reason", reason)
+
+		select {
+		case <-ctx.Done():
+			logger.Error("Resource did not reach condition in time", "reason", reason)
+			return fmt.Errorf("%s %s did not reach %s in time: %s",
+				resource.ResourceType(), resource.ResourceKey(), condition, reason)
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, checkMaxDelay)
+	}
+}
@@ -0,0 +1,459 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/report"
+	"github.com/mcastellin/aws-fail-az/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailResourcesShouldRollbackOnError(t *testing.T) {
+	stateManager := newFakeStateManager()
+	resources := []*fakeResource{
+		{key: "first"},
+		{key: "second", failErr: fmt.Errorf("fail error")},
+		{key: "third"},
+	}
+
+	err := failResources(context.TODO(), stateManager, toResources(resources), failOptions{Azs: []string{"us-east-1a"}, Rollback: true})
+
+	assert.NotNil(t, err)
+	assert.True(t, resources[0].restored)
+	assert.True(t, resources[1].restored)
+	assert.False(t, resources[2].failed)
+	assert.False(t, resources[2].restored)
+	assert.Len(t, stateManager.states, 0)
+}
+
+func TestFailResourcesShouldKeepStatesWithoutRollback(t *testing.T) {
+	stateManager := newFakeStateManager()
+	resources := []*fakeResource{
+		{key: "first"},
+		{key: "second", failErr: fmt.Errorf("fail error")},
+		{key: "third"},
+	}
+
+	err := failResources(context.TODO(), stateManager, toResources(resources), failOptions{Azs: []string{"us-east-1a"}})
+
+	assert.NotNil(t, err)
+	assert.False(t, resources[0].restored)
+	assert.False(t, resources[1].restored)
+	assert.Len(t, stateManager.states, 3)
+}
+
+func TestFailResourcesShouldKeepStateWhenRollbackFails(t *testing.T) {
+	stateManager := newFakeStateManager()
+	resources := []*fakeResource{
+		{key: "first", restoreErr: fmt.Errorf("restore error")},
+		{key: "second", failErr: fmt.Errorf("fail error")},
+	}
+
+	err := failResources(context.TODO(), stateManager, toResources(resources), failOptions{Azs: []string{"us-east-1a"}, Rollback: true})
+
+	assert.NotNil(t, err)
+	assert.Len(t, stateManager.states, 1)
+	assert.Contains(t, stateManager.states, "fake/first")
+}
+
+func TestFailResourcesShouldRollbackWhenContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stateManager := newFakeStateManager()
+	resources := []*fakeResource{
+		{key: "first"},
+		{key: "second", failFn: func(context.Context) error {
+			cancel()
+			return context.Canceled
+		}},
+	}
+
+	err := failResources(ctx, stateManager, toResources(resources), failOptions{Azs: []string{"us-east-1a"}, Rollback: true})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.True(t, resources[0].restored)
+	assert.True(t, resources[1].restored)
+	assert.Len(t, stateManager.states, 0)
+}
+
+func TestFailResourcesShouldReportObservedSubnets(t *testing.T) {
+	stateManager := newFakeStateManager()
+	resource := &fakeResource{key: "first", subnets: []string{"s-1", "s-2"}}
+	resource.failFn = func(context.Context) error {
+		resource.subnets = []string{"s-2"}
+		return nil
+	}
+	recorder := report.NewRecorder("fail", "test")
+
+	err := failResources(context.TODO(), stateManager, toResources([]*fakeResource{resource}),
+		failOptions{Azs: []string{"us-east-1a"}, Report: recorder})
+
+	assert.Nil(t, err)
+	resourceReport := recorder.Finish(nil).Resources[0]
+	assert.Equal(t, []string{"s-1", "s-2"}, resourceReport.SubnetsBefore)
+	assert.Equal(t, []string{"s-2"}, resourceReport.SubnetsAfter)
+}
+
+func TestCheckResourceStatesShouldPollUntilStable(t *testing.T) {
+	checkInitialDelay, checkMaxDelay = time.Millisecond, time.Millisecond
+
+	resource := &fakeResource{key: "first", unstableChecks: 3}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := checkResourceStates(ctx, toResources([]*fakeResource{resource}), nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 4, resource.checks)
+}
+
+func TestCheckResourceStatesShouldFailAfterTimeout(t *testing.T) {
+	checkInitialDelay, checkMaxDelay = time.Millisecond, time.Millisecond
+
+	resources := []*fakeResource{{key: "first"}, {key: "second", unstableChecks: 1_000_000}}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := checkResourceStates(ctx, toResources(resources), nil)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, resources[0].checks)
+}
+
+func TestCheckResourceStatesShouldStopOnPermanentError(t *testing.T) {
+	checkInitialDelay, checkMaxDelay = time.Millisecond, time.Millisecond
+
+	checkErr := domain.ActivityFailedError{Wrap: fmt.Errorf("resource not found"), Temporary: false}
+	resource := &fakeResource{key: "first", unstableChecks: 1_000_000, checkErr: checkErr}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := checkResourceStates(ctx, toResources([]*fakeResource{resource}), nil)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, resource.checks)
+}
+
+func TestCheckResourceStatesShouldInterruptAllChecks(t *testing.T) {
+	checkInitialDelay, checkMaxDelay = time.Millisecond, time.Millisecond
+
+	interruptErr := domain.InterruptExecutionError{Wrap: fmt.Errorf("access denied")}
+	resources := []*fakeResource{
+		{key: "first", unstableChecks: 1_000_000},
+		{key: "second", unstableChecks: 1_000_000, checkErr: interruptErr},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := checkResourceStates(ctx, toResources(resources), nil)
+
+	assert.ErrorIs(t, err, interruptErr)
+	assert.Nil(t, ctx.Err())
+}
+
+func TestFailResourcesShouldRetryTemporaryErrors(t *testing.T) {
+	stateManager := newFakeStateManager()
+	attempts := 0
+	resources := []*fakeResource{{key: "first", failFn: func(ctx context.Context) error {
+		attempts++
+		if attempts == 1 {
+			return domain.ActivityFailedError{Wrap: fmt.Errorf("throttled"), Temporary: true}
+		}
+		return nil
+	}}}
+
+	err := failResources(context.TODO(), stateManager, toResources(resources),
+		failOptions{Azs: []string{"us-east-1a"}, Rollback: true, Retry: testRetryPolicy})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.False(t, resources[0].restored)
+	assert.Len(t, stateManager.states, 1)
+}
+
+func TestFailResourcesShouldLimitParallelism(t *testing.T) {
+	stateManager := newFakeStateManager()
+	var running, maxRunning atomic.Int32
+	failFn := func(ctx context.Context) error {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			observed := maxRunning.Load()
+			if current <= observed || maxRunning.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}
+	resources := []*fakeResource{}
+	for idx := 0; idx < 6; idx++ {
+		resources = append(resources, &fakeResource{key: fmt.Sprintf("resource-%d", idx), failFn: failFn})
+	}
+
+	err := failResources(context.TODO(), stateManager, toResources(resources),
+		failOptions{Azs: []string{"us-east-1a"}, Parallelism: 2})
+
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), maxRunning.Load())
+	assert.Len(t, stateManager.states, 6)
+	for _, resource := range resources {
+		assert.True(t, resource.failed)
+	}
+}
+
+func TestFailResourcesShouldFailAllResourcesAtOnceWhenSynchronized(t *testing.T) {
+	stateManager := newFakeStateManager()
+	resourceCount := 5
+	allStarted := new(sync.WaitGroup)
+	allStarted.Add(resourceCount)
+	failFn := func(ctx context.Context) error {
+		// Blocks until Fail was called on every resource
+		allStarted.Done()
+		allStarted.Wait()
+		return nil
+	}
+	resources := []*fakeResource{}
+	for idx := 0; idx < resourceCount; idx++ {
+		resources = append(resources, &fakeResource{key: fmt.Sprintf("resource-%d", idx), failFn: failFn})
+	}
+
+	err := failResources(context.TODO(), stateManager, toResources(resources),
+		failOptions{Azs: []string{"us-east-1a"}, Synchronized: true})
+
+	assert.Nil(t, err)
+	assert.Len(t, stateManager.states, resourceCount)
+}
+
+func TestFailResourcesShouldRollbackAllResourcesWhenSynchronized(t *testing.T) {
+	stateManager := newFakeStateManager()
+	resources := []*fakeResource{
+		{key: "first"},
+		{key: "second", failErr: fmt.Errorf("failed to update resource")},
+		{key: "third"},
+	}
+
+	err := failResources(context.TODO(), stateManager, toResources(resources),
+		failOptions{Azs: []string{"us-east-1a"}, Rollback: true, Synchronized: true})
+
+	assert.NotNil(t, err)
+	for _, resource := range resources {
+		assert.True(t, resource.failed)
+		assert.True(t, resource.restored)
+	}
+	assert.Len(t, stateManager.states, 0)
+}
+
+func TestFailResourcesShouldPrepareAllResourcesBeforeFailingWhenSynchronized(t *testing.T) {
+	stateManager := newFakeStateManager()
+	resourceCount := 5
+
+	var prepared atomic.Int32
+	resources := []domain.ConsistentStateResource{}
+	applied := make([]int32, resourceCount)
+	for idx := 0; idx < resourceCount; idx++ {
+		idx := idx
+		resources = append(resources, &preparedResource{
+			fakeResource: fakeResource{key: fmt.Sprintf("resource-%d", idx)},
+			prepareFn: func() (func(context.Context) error, error) {
+				prepared.Add(1)
+				return func(ctx context.Context) error {
+					applied[idx] = prepared.Load()
+					return nil
+				}, nil
+			},
+		})
+	}
+
+	err := failResources(context.TODO(), stateManager, resources,
+		failOptions{Azs: []string{"us-east-1a"}, Synchronized: true})
+
+	assert.Nil(t, err)
+	for idx, resource := range resources {
+		assert.Equal(t, int32(resourceCount), applied[idx])
+		assert.False(t, resource.(*preparedResource).failed)
+	}
+}
+
+func TestFailResourcesShouldNotFailAnyResourceWhenPreparationFails(t *testing.T) {
+	stateManager := newFakeStateManager()
+	first := &fakeResource{key: "first"}
+	second := &preparedResource{
+		fakeResource: fakeResource{key: "second"},
+		prepareFn: func() (func(context.Context) error, error) {
+			return nil, fmt.Errorf("failed to describe resource")
+		},
+	}
+
+	err := failResources(context.TODO(), stateManager, []domain.ConsistentStateResource{first, second},
+		failOptions{Azs: []string{"us-east-1a"}, Rollback: true, Synchronized: true})
+
+	assert.NotNil(t, err)
+	assert.False(t, first.failed)
+	assert.False(t, second.failed)
+	assert.Len(t, stateManager.states, 0)
+}
+
+func TestFailResourcesShouldRecordReport(t *testing.T) {
+	stateManager := newFakeStateManager()
+	resources := []*fakeResource{
+		{key: "first"},
+		{key: "second", failErr: fmt.Errorf("failed to update resource")},
+	}
+	recorder := report.NewRecorder("fail", "")
+
+	err := failResources(context.TODO(), stateManager, toResources(resources),
+		failOptions{Azs: []string{"us-east-1a"}, Rollback: true, Report: recorder})
+	result := recorder.Finish(err)
+
+	assert.Len(t, result.Resources, 2)
+	phases := []string{}
+	for _, phase := range result.Resources[1].Phases {
+		phases = append(phases, phase.Phase)
+	}
+	assert.Equal(t, []string{report.PhaseSave, report.PhaseFail, report.PhaseRestore}, phases)
+	assert.Equal(t, "failed to update resource", result.Resources[1].Phases[1].Error)
+}
+
+func toResources(fakes []*fakeResource) []domain.ConsistentStateResource {
+	resources := make([]domain.ConsistentStateResource, len(fakes))
+	for idx := range fakes {
+		resources[idx] = fakes[idx]
+	}
+	return resources
+}
+
+// A fake resource that records the operations applied to it
+type fakeResource struct {
+	key            string
+	failErr        error
+	failFn         func(context.Context) error
+	restoreErr     error
+	unstableChecks int
+	checkErr       error
+	subnets        []string
+
+	checks   int
+	failed   bool
+	restored bool
+}
+
+func (r *fakeResource) ResourceType() string { return "fake" }
+func (r *fakeResource) ResourceKey() string  { return r.key }
+
+func (r *fakeResource) Check(ctx context.Context) (bool, error) {
+	r.checks++
+	if r.checks <= r.unstableChecks {
+		if r.checkErr != nil {
+			return false, r.checkErr
+		}
+		return false, fmt.Errorf("resource is not stable")
+	}
+	return true, nil
+}
+
+func (r *fakeResource) Save(ctx context.Context, stateManager state.StateManager) error {
+	return stateManager.Save(ctx, r.ResourceType(), r.ResourceKey(), []byte("{}"))
+}
+
+func (r *fakeResource) Fail(ctx context.Context, azs []string) error {
+	r.failed = true
+	if r.failFn != nil {
+		return r.failFn(ctx)
+	}
+	return r.failErr
+}
+
+func (r *fakeResource) Restore(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	r.restored = r.restoreErr == nil
+	return r.restoreErr
+}
+
+func (r *fakeResource) Plan(ctx context.Context, azs []string) (*domain.FailurePlan, error) {
+	return &domain.FailurePlan{ResourceType: r.ResourceType(), ResourceKey: r.key, CurrentSubnets: r.subnets}, nil
+}
+
+// A fake resource that prepares its failure before synchronized failures are released
+type preparedResource struct {
+	fakeResource
+	prepareFn func() (func(context.Context) error, error)
+}
+
+func (r *preparedResource) PrepareFail(ctx context.Context, azs []string) (func(context.Context) error, error) {
+	return r.prepareFn()
+}
+
+// An in-memory state manager
+type fakeStateManager struct {
+	mu          sync.Mutex
+	states      map[string]state.ResourceState
+	experiments []state.Experiment
+}
+
+func newFakeStateManager() *fakeStateManager {
+	return &fakeStateManager{states: map[string]state.ResourceState{}}
+}
+
+func (m *fakeStateManager) Initialize(ctx context.Context) error { return nil }
+
+func (m *fakeStateManager) Save(ctx context.Context, resourceType string, resourceKey string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := fmt.Sprintf("%s/%s", resourceType, resourceKey)
+	if _, ok := m.states[key]; ok {
+		return fmt.Errorf("State key already exist for resource %s", key)
+	}
+	m.states[key] = state.ResourceState{Key: key, ResourceType: resourceType, ResourceKey: resourceKey, State: data}
+	return nil
+}
+
+func (m *fakeStateManager) GetState(ctx context.Context, resourceType string, resourceKey string) (*state.ResourceState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.states[fmt.Sprintf("%s/%s", resourceType, resourceKey)]
+	if !ok {
+		return nil, fmt.Errorf("Unknown state key")
+	}
+	return &s, nil
+}
+
+func (m *fakeStateManager) QueryStates(ctx context.Context, params *state.QueryStatesInput) ([]state.ResourceState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	states := []state.ResourceState{}
+	for _, s := range m.states {
+		states = append(states, s)
+	}
+	return states, nil
+}
+
+func (m *fakeStateManager) RemoveState(ctx context.Context, stateObj state.ResourceState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.states, stateObj.Key)
+	return nil
+}
+
+func (m *fakeStateManager) SaveExperiment(ctx context.Context, experiment state.Experiment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.experiments = append(m.experiments, experiment)
+	return nil
+}
+
+func (m *fakeStateManager) QueryExperiments(ctx context.Context, namespace string) ([]state.Experiment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.experiments) == 0 {
+		return []state.Experiment{}, nil
+	}
+	return []state.Experiment{m.experiments[len(m.experiments)-1]}, nil
+}
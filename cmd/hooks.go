@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
+	"github.com/mcastellin/aws-fail-az/state"
+)
+
+// The default time for a hook command to complete
+const DEFAULT_HOOK_TIMEOUT = 5 * time.Minute
+
+// Phases of the experiment lifecycle in which hooks are run
+const (
+	HookPhasePreCheck    = "preCheck"
+	HookPhasePreFail     = "preFail"
+	HookPhasePostFail    = "postFail"
+	HookPhasePreRecover  = "preRecover"
+	HookPhasePostRecover = "postRecover"
+)
+
+// A resource targeted by the experiment, as described to hook commands
+type hookResource struct {
+	Type string `json:"type"`
+	Key  string `json:"key"`
+}
+
+// Runs the commands configured for the phases of the experiment lifecycle
+type lifecycleHooks struct {
+	Config    *domain.HooksConfiguration
+	Namespace string
+	Azs       []string
+	Resources []hookResource
+}
+
+// Returns the hooks configured for the lifecycle phase
+func (l *lifecycleHooks) hooks(phase string) []domain.Hook {
+	if l.Config == nil {
+		return nil
+	}
+	switch phase {
+	case HookPhasePreCheck:
+		return l.Config.PreCheck
+	case HookPhasePreFail:
+		return l.Config.PreFail
+	case HookPhasePostFail:
+		return l.Config.PostFail
+	case HookPhasePreRecover:
+		return l.Config.PreRecover
+	case HookPhasePostRecover:
+		return l.Config.PostRecover
+	}
+	return nil
+}
+
+// Returns the environment variables describing the experiment to hook commands
+func (l *lifecycleHooks) environ(phase string) []string {
+	resources, _ := json.Marshal(l.Resources)
+	return append(os.Environ(),
+		"AWS_FAIL_AZ_PHASE="+phase,
+		"AWS_FAIL_AZ_NAMESPACE="+l.Namespace,
+		"AWS_FAIL_AZ_AZS="+strings.Join(l.Azs, ","),
+		"AWS_FAIL_AZ_RESOURCES="+string(resources),
+	)
+}
+
+// Runs the hooks of the lifecycle phase in order and logs their output.
+// Returns an error as soon as one of the hooks fails
+func (l *lifecycleHooks) Run(ctx context.Context, phase string) error {
+	for _, hook := range l.hooks(phase) {
+		timeout := time.Duration(hook.Timeout)
+		if timeout == 0 {
+			timeout = DEFAULT_HOOK_TIMEOUT
+		}
+		hookCtx, cancel := context.WithTimeout(ctx, timeout)
+
+		logger := slog.With(logging.KeyPhase, phase, "hook", hook.Command)
+		logger.Info("Running hook")
+		hookCmd := exec.CommandContext(hookCtx, "sh", "-c", hook.Command)
+		hookCmd.Env = l.environ(phase)
+		output, err := hookCmd.CombinedOutput()
+		cancel()
+
+		for _, line := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+			if line != "" {
+				logger.Info("Hook output", "output", line)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s hook '%s' failed: %w", phase, hook.Command, err)
+		}
+	}
+	return nil
+}
+
+// Returns the description of the resources for hook commands
+func hookResourcesFromResources(resources []domain.ConsistentStateResource) []hookResource {
+	result := []hookResource{}
+	for _, resource := range resources {
+		result = append(result, hookResource{Type: resource.ResourceType(), Key: resource.ResourceKey()})
+	}
+	return result
+}
+
+// Returns the description of the resources saved in the states for hook commands
+func hookResourcesFromStates(states []state.ResourceState) []hookResource {
+	result := []hookResource{}
+	for _, s := range states {
+		result = append(result, hookResource{Type: s.ResourceType, Key: s.ResourceKey})
+	}
+	return result
+}
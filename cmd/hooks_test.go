@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	asgtypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/mcastellin/aws-fail-az/awsapis_mocks"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLifecycleHooksShouldDescribeExperimentInEnvironment(t *testing.T) {
+	var output bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&output, nil)))
+
+	hooks := &lifecycleHooks{
+		Config: &domain.HooksConfiguration{
+			PreFail: []domain.Hook{{Command: `echo "$AWS_FAIL_AZ_PHASE $AWS_FAIL_AZ_NAMESPACE $AWS_FAIL_AZ_AZS $AWS_FAIL_AZ_RESOURCES"`}},
+		},
+		Namespace: "test-ns",
+		Azs:       []string{"us-east-1a", "us-east-1b"},
+		Resources: []hookResource{{Type: "ecs-service", Key: "cluster-service"}},
+	}
+
+	err := hooks.Run(context.TODO(), HookPhasePreFail)
+
+	assert.Nil(t, err)
+	assert.Contains(t, output.String(), `phase=preFail`)
+	assert.Contains(t, output.String(),
+		`output="preFail test-ns us-east-1a,us-east-1b [{\"type\":\"ecs-service\",\"key\":\"cluster-service\"}]"`)
+}
+
+func TestLifecycleHooksShouldStopAtFirstFailure(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "marker")
+	hooks := &lifecycleHooks{
+		Config: &domain.HooksConfiguration{
+			PreCheck: []domain.Hook{
+				{Command: "echo 'silencing alerts failed' && exit 3"},
+				{Command: "touch " + marker},
+			},
+		},
+	}
+
+	err := hooks.Run(context.TODO(), HookPhasePreCheck)
+
+	assert.ErrorContains(t, err, "preCheck hook")
+	assert.NoFileExists(t, marker)
+}
+
+func TestLifecycleHooksShouldOnlyRunHooksForPhase(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "marker")
+	hooks := &lifecycleHooks{
+		Config: &domain.HooksConfiguration{PostRecover: []domain.Hook{{Command: "touch " + marker}}},
+	}
+
+	assert.Nil(t, hooks.Run(context.TODO(), HookPhasePreRecover))
+	assert.NoFileExists(t, marker)
+	assert.Nil(t, hooks.Run(context.TODO(), HookPhasePostRecover))
+	assert.FileExists(t, marker)
+
+	assert.Nil(t, (&lifecycleHooks{}).Run(context.TODO(), HookPhasePreFail))
+}
+
+func TestFailPlanShouldNotRunHooks(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockEc2Api := awsapis_mocks.NewMockEc2Api(ctrl)
+	mockEc2Api.EXPECT().DescribeAvailabilityZones(gomock.Any(), gomock.Any()).AnyTimes().
+		Return(&ec2.DescribeAvailabilityZonesOutput{AvailabilityZones: []ec2types.AvailabilityZone{
+			{ZoneName: aws.String("us-east-1a"), ZoneId: aws.String("use1-az1")},
+		}}, nil)
+	mockEc2Api.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any()).AnyTimes().
+		Return(&ec2.DescribeSubnetsOutput{Subnets: []ec2types.Subnet{
+			{SubnetId: aws.String("subnet-a"), AvailabilityZone: aws.String("us-east-1a")},
+			{SubnetId: aws.String("subnet-b"), AvailabilityZone: aws.String("us-east-1b")},
+		}}, nil)
+
+	mockAsgApi := awsapis_mocks.NewMockAutoScalingApi(ctrl)
+	mockAsgApi.EXPECT().DescribeAutoScalingGroups(gomock.Any(), gomock.Any()).AnyTimes().
+		Return(&autoscaling.DescribeAutoScalingGroupsOutput{AutoScalingGroups: []asgtypes.AutoScalingGroup{{
+			AutoScalingGroupName: aws.String("test-asg"),
+			DesiredCapacity:      aws.Int32(0),
+			VPCZoneIdentifier:    aws.String("subnet-a,subnet-b"),
+		}}}, nil)
+
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewEc2Api().AnyTimes().Return(mockEc2Api)
+	mockProvider.EXPECT().NewAutoScalingApi().AnyTimes().Return(mockAsgApi)
+
+	marker := filepath.Join(t.TempDir(), "marker")
+	hook := fmt.Sprintf(`[{"command": "touch %s"}]`, marker)
+	config := fmt.Sprintf(`{
+		"azs": ["us-east-1a"],
+		"targets": [{"type": "auto-scaling-group", "filter": "name=test-asg"}],
+		"hooks": {"preCheck": %s, "preFail": %s, "postFail": %s, "preRecover": %s, "postRecover": %s}
+	}`, hook, hook, hook, hook, hook)
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	assert.Nil(t, os.WriteFile(configFile, []byte(config), 0600))
+
+	cmd := &FailCommand{Provider: mockProvider, Plan: true, ConfigFile: configFile}
+	err := cmd.Run(context.TODO())
+
+	assert.Nil(t, err)
+	assert.NoFileExists(t, marker)
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
+)
+
+// Runs `activityFn` for the experiment phase on all resources with at most `parallelism` activities running concurrently.
+// No new activity is started once an activity has failed or the context is cancelled.
+// Returns the resources the activity was started for, the resources it succeeded for,
+// and the errors of all failed activities
+func forEachResource(ctx context.Context, phase string, resources []domain.ConsistentStateResource, parallelism int,
+	activityFn func(domain.ConsistentStateResource) error) ([]domain.ConsistentStateResource, []domain.ConsistentStateResource, error) {
+
+	var mu sync.Mutex
+	started := []domain.ConsistentStateResource{}
+	succeeded := []domain.ConsistentStateResource{}
+	startTimes := []time.Time{}
+	errs := []error{}
+
+	semaphore := make(chan struct{}, max(parallelism, 1))
+	wg := new(sync.WaitGroup)
+	for _, resource := range resources {
+		semaphore <- struct{}{}
+
+		mu.Lock()
+		stop := len(errs) > 0
+		mu.Unlock()
+		if stop || ctx.Err() != nil {
+			<-semaphore
+			break
+		}
+
+		mu.Lock()
+		started = append(started, resource)
+		startTimes = append(startTimes, logActivityStart(phase, resource))
+		mu.Unlock()
+
+		wg.Add(1)
+		go func(resource domain.ConsistentStateResource) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			err := activityFn(resource)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			succeeded = append(succeeded, resource)
+		}(resource)
+	}
+	wg.Wait()
+
+	if len(errs) == 0 && ctx.Err() != nil && len(started) < len(resources) {
+		errs = append(errs, domain.InterruptExecutionError{Wrap: context.Cause(ctx)})
+	}
+	logActivitySpread(phase, startTimes)
+	return started, succeeded, errors.Join(errs...)
+}
+
+// Runs the activities returned by `prepareFn` for the experiment phase on all resources concurrently.
+// Every resource is prepared before a barrier that is only released when all of them are ready to start,
+// so that resources are modified as close to each other as possible. No activity is started if
+// any preparation fails or the context is cancelled while waiting for the barrier.
+// Returns the resources the activity was started for and the errors of all failed activities
+func forEachResourceSynchronized(ctx context.Context, phase string, resources []domain.ConsistentStateResource,
+	prepareFn func(domain.ConsistentStateResource) (func() error, error)) ([]domain.ConsistentStateResource, error) {
+
+	barrier := make(chan struct{})
+	abort := false
+	ready := new(sync.WaitGroup)
+	done := new(sync.WaitGroup)
+
+	startTimes := make([]time.Time, len(resources))
+	prepareErrs := make([]error, len(resources))
+	errs := make([]error, len(resources))
+	for idx, resource := range resources {
+		ready.Add(1)
+		done.Add(1)
+		go func(idx int, resource domain.ConsistentStateResource) {
+			defer done.Done()
+			activityFn, err := prepareFn(resource)
+			prepareErrs[idx] = err
+			ready.Done()
+			<-barrier
+			if abort {
+				return
+			}
+
+			startTimes[idx] = logActivityStart(phase, resource)
+			errs[idx] = activityFn()
+		}(idx, resource)
+	}
+
+	ready.Wait()
+	prepareErr := errors.Join(prepareErrs...)
+	// Goroutines are released without starting any activity if a resource could not be
+	// prepared or the context was cancelled while waiting for all of them to be ready
+	abort = prepareErr != nil || ctx.Err() != nil
+	close(barrier)
+	done.Wait()
+
+	if prepareErr != nil {
+		return []domain.ConsistentStateResource{}, prepareErr
+	}
+	if abort {
+		return []domain.ConsistentStateResource{}, domain.InterruptExecutionError{Wrap: context.Cause(ctx)}
+	}
+	logActivitySpread(phase, startTimes)
+	return resources, errors.Join(errs...)
+}
+
+// Logs and returns the start time of the phase activity on the resource
+func logActivityStart(phase string, resource domain.ConsistentStateResource) time.Time {
+	start := time.Now()
+	activityLogger(resource, phase).Info("Activity started", "startedAt", start.Format(time.RFC3339Nano))
+	return start
+}
+
+// Logs the time elapsed between the first and the last activity start
+func logActivitySpread(phase string, startTimes []time.Time) {
+	if len(startTimes) < 2 {
+		return
+	}
+	first, last := startTimes[0], startTimes[0]
+	for _, start := range startTimes[1:] {
+		if start.Before(first) {
+			first = start
+		}
+		if start.After(last) {
+			last = start
+		}
+	}
+	slog.Info("Activity started on all resources", logging.KeyPhase, phase,
+		"resources", len(startTimes), "spread", last.Sub(first))
+}
+
+// Returns a logger for the activity of the experiment phase on the resource
+func activityLogger(resource domain.ConsistentStateResource, phase string) *slog.Logger {
+	return logging.ForResource(resource.ResourceType(), resource.ResourceKey()).With(logging.KeyPhase, phase)
+}
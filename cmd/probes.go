@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
+	"github.com/mcastellin/aws-fail-az/report"
+	"golang.org/x/exp/slices"
+)
+
+// Default values for probes
+const (
+	DEFAULT_PROBE_INTERVAL          = 10 * time.Second
+	DEFAULT_PROBE_TIMEOUT           = 10 * time.Second
+	DEFAULT_PROBE_FAILURE_THRESHOLD = 3
+)
+
+// Phases of the experiment in which probes are sampled
+const (
+	ProbePhaseBefore = "before"
+	ProbePhaseDuring = "during"
+	ProbePhaseAfter  = "after"
+)
+
+// An error to signal the experiment was stopped because a probe broke its threshold
+type probeAbortError struct {
+	Probe  string
+	Reason string
+}
+
+func (e probeAbortError) Error() string {
+	return fmt.Sprintf("probe %s broke its failure threshold: %s", e.Probe, e.Reason)
+}
+
+// The results of the samples of a probe in a phase of the experiment
+type probeResult struct {
+	Probe      string
+	Phase      string
+	Samples    int
+	Failures   int
+	MaxLatency time.Duration
+	LastError  string
+	Broken     bool
+
+	consecutiveFailures int
+}
+
+// Records a sample in the probe results
+func (r *probeResult) record(latency time.Duration, err error) {
+	r.Samples++
+	r.MaxLatency = max(r.MaxLatency, latency)
+	if err == nil {
+		r.consecutiveFailures = 0
+		return
+	}
+	r.Failures++
+	r.consecutiveFailures++
+	r.LastError = err.Error()
+}
+
+// Samples HTTP probes and records their results
+type probeRunner struct {
+	Probes []domain.Probe
+	Client *http.Client
+
+	// Compiled response body patterns by their configured expression
+	bodyPatterns map[string]*regexp.Regexp
+
+	mu      sync.Mutex
+	results []*probeResult
+}
+
+// Returns a runner for the probes. Returns an error if the body pattern of a probe is not
+// a valid regular expression
+func newProbeRunner(probes []domain.Probe) (*probeRunner, error) {
+	bodyPatterns := map[string]*regexp.Regexp{}
+	for _, probe := range probes {
+		if probe.BodyMatches == "" {
+			continue
+		}
+		pattern, err := regexp.Compile(probe.BodyMatches)
+		if err != nil {
+			return nil, fmt.Errorf("probe %s 'bodyMatches' is not a valid regular expression: %w", probeName(probe), err)
+		}
+		bodyPatterns[probe.BodyMatches] = pattern
+	}
+
+	return &probeRunner{
+		Probes:       probes,
+		Client:       &http.Client{},
+		bodyPatterns: bodyPatterns,
+	}, nil
+}
+
+// Returns a copy of the results of all probes sampled so far
+func (r *probeRunner) Results() []probeResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	results := []probeResult{}
+	for _, result := range r.results {
+		results = append(results, *result)
+	}
+	return results
+}
+
+// Returns a new result for the probe in the experiment phase
+func (r *probeRunner) newResult(probe domain.Probe, phase string) *probeResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := &probeResult{Probe: probeName(probe), Phase: phase}
+	r.results = append(r.results, result)
+	return result
+}
+
+// Samples all probes once. Returns an error if any of the samples fails
+func (r *probeRunner) SampleOnce(ctx context.Context, phase string) error {
+	return r.sampleUntilSuccess(ctx, phase, func(domain.Probe) int { return 1 })
+}
+
+// Samples all probes after recovery. Every probe is sampled at its interval until it
+// succeeds or its failure threshold is reached, to give the application time to recover.
+// Returns an error if any of the probes never succeeds
+func (r *probeRunner) SampleAfterRecovery(ctx context.Context) error {
+	return r.sampleUntilSuccess(ctx, ProbePhaseAfter, probeFailureThreshold)
+}
+
+// Samples every probe until it succeeds or the maximum number of attempts is reached
+func (r *probeRunner) sampleUntilSuccess(ctx context.Context, phase string, attemptsFn func(domain.Probe) int) error {
+	errs := []error{}
+	for _, probe := range r.Probes {
+		result := r.newResult(probe, phase)
+
+		var err error
+		for attempt := 1; attempt <= attemptsFn(probe); attempt++ {
+			if attempt > 1 {
+				select {
+				case <-ctx.Done():
+				case <-time.After(probeInterval(probe)):
+				}
+			}
+
+			var latency time.Duration
+			latency, err = r.sample(ctx, probe)
+			r.mu.Lock()
+			result.record(latency, err)
+			r.mu.Unlock()
+			if err == nil || ctx.Err() != nil {
+				break
+			}
+		}
+
+		if err != nil {
+			r.mu.Lock()
+			result.Broken = true
+			r.mu.Unlock()
+			slog.Error("Probe failed", "probe", result.Probe, logging.KeyPhase, phase, logging.Err(err))
+			errs = append(errs, fmt.Errorf("probe %s failed %s AZ failure: %w", result.Probe, phase, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Samples all probes continuously until the returned function is called.
+// Probes configured to abort the experiment stop it when they break their threshold
+func (r *probeRunner) StartMonitor(ctx context.Context, stopExperiment context.CancelCauseFunc) func() {
+	ctx, cancel := context.WithCancel(ctx)
+
+	wg := new(sync.WaitGroup)
+	for _, probe := range r.Probes {
+		wg.Add(1)
+		go func(probe domain.Probe) {
+			defer wg.Done()
+			r.monitor(ctx, probe, stopExperiment)
+		}(probe)
+	}
+
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+// Samples the probe at every interval until the context is cancelled
+func (r *probeRunner) monitor(ctx context.Context, probe domain.Probe, stopExperiment context.CancelCauseFunc) {
+	result := r.newResult(probe, ProbePhaseDuring)
+	interval := probeInterval(probe)
+	threshold := probeFailureThreshold(probe)
+
+	for {
+		latency, err := r.sample(ctx, probe)
+		if ctx.Err() != nil {
+			// Samples interrupted by the end of the monitoring are discarded
+			return
+		}
+
+		r.mu.Lock()
+		result.record(latency, err)
+		broken := !result.Broken && result.consecutiveFailures >= threshold
+		if broken {
+			result.Broken = true
+		}
+		r.mu.Unlock()
+
+		if err != nil {
+			slog.Warn("Probe failed", "probe", result.Probe, logging.KeyPhase, ProbePhaseDuring, logging.Err(err))
+		}
+		if broken {
+			slog.Error("Probe broke its failure threshold", "probe", result.Probe, logging.KeyPhase, ProbePhaseDuring,
+				"consecutiveFailures", threshold)
+			if probe.AbortOnFailure {
+				stopExperiment(probeAbortError{Probe: result.Probe, Reason: err.Error()})
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Sends the probe request and validates the response.
+// Returns the response latency and an error if the response is not the expected one
+func (r *probeRunner) sample(ctx context.Context, probe domain.Probe) (time.Duration, error) {
+	method := probe.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout(probe))
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, probe.Url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return time.Since(start), err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	latency := time.Since(start)
+	if err != nil {
+		return latency, err
+	}
+
+	if len(probe.ExpectedStatus) > 0 && !slices.Contains(probe.ExpectedStatus, resp.StatusCode) {
+		return latency, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	if len(probe.ExpectedStatus) == 0 && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+		return latency, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	if probe.BodyMatches != "" && !r.bodyPatterns[probe.BodyMatches].Match(body) {
+		return latency, fmt.Errorf("response body does not match '%s'", probe.BodyMatches)
+	}
+	if probe.MaxLatency > 0 && latency > time.Duration(probe.MaxLatency) {
+		return latency, fmt.Errorf("latency %s exceeds %s", latency.Round(time.Millisecond), time.Duration(probe.MaxLatency))
+	}
+	return latency, nil
+}
+
+// Returns the results of all probes for the experiment report
+func (r *probeRunner) Report() []report.ProbeReport {
+	probes := []report.ProbeReport{}
+	for _, result := range r.Results() {
+		probes = append(probes, report.ProbeReport{
+			Probe:      result.Probe,
+			Phase:      result.Phase,
+			Samples:    result.Samples,
+			Failures:   result.Failures,
+			MaxLatency: domain.Duration(result.MaxLatency),
+			LastError:  result.LastError,
+			Broken:     result.Broken,
+		})
+	}
+	return probes
+}
+
+// Logs the results of all probes. Returns an error for every probe that failed
+// before or after the AZ failure or broke its threshold during the AZ failure
+func (r *probeRunner) Evaluate() error {
+	errs := []error{}
+	for _, result := range r.Results() {
+		slog.Info("Probe results", "probe", result.Probe, logging.KeyPhase, result.Phase, "samples", result.Samples,
+			"failures", result.Failures, "maxLatency", result.MaxLatency.Round(time.Millisecond))
+		if result.Broken {
+			errs = append(errs, fmt.Errorf("probe %s failed %s AZ failure: %s", result.Probe, result.Phase, result.LastError))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Returns the time between consecutive samples of the probe
+func probeInterval(probe domain.Probe) time.Duration {
+	if probe.Interval == 0 {
+		return DEFAULT_PROBE_INTERVAL
+	}
+	return time.Duration(probe.Interval)
+}
+
+// Returns the maximum time to wait for the probe response. Responses are awaited
+// for at least the maximum latency of the probe so that slow responses can succeed
+func probeTimeout(probe domain.Probe) time.Duration {
+	return max(DEFAULT_PROBE_TIMEOUT, time.Duration(probe.MaxLatency))
+}
+
+// Returns the number of consecutive failed samples that break the probe
+func probeFailureThreshold(probe domain.Probe) int {
+	if probe.FailureThreshold == 0 {
+		return DEFAULT_PROBE_FAILURE_THRESHOLD
+	}
+	return probe.FailureThreshold
+}
+
+// Returns the name of the probe or its URL if no name was configured
+func probeName(probe domain.Probe) string {
+	if probe.Name != "" {
+		return probe.Name
+	}
+	return probe.Url
+}
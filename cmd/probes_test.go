@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbeSampleOnceShouldValidateResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/unavailable" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		fmt.Fprint(w, `{"status":"ok"}`)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		probe    domain.Probe
+		expected bool
+	}{
+		{domain.Probe{Url: server.URL + "/health"}, true},
+		{domain.Probe{Url: server.URL + "/health", BodyMatches: `"status":\s*"ok"`}, true},
+		{domain.Probe{Url: server.URL + "/health", BodyMatches: `"status":\s*"degraded"`}, false},
+		{domain.Probe{Url: server.URL + "/unavailable"}, false},
+		{domain.Probe{Url: server.URL + "/unavailable", ExpectedStatus: []int{503}}, true},
+		{domain.Probe{Url: server.URL + "/health", ExpectedStatus: []int{204}}, false},
+	}
+
+	for _, test := range tests {
+		prober, err := newProbeRunner([]domain.Probe{test.probe})
+		assert.Nil(t, err)
+		err = prober.SampleOnce(context.TODO(), ProbePhaseBefore)
+
+		assert.Equal(t, test.expected, err == nil, "probe %+v", test.probe)
+		assert.Equal(t, !test.expected, prober.Results()[0].Broken)
+	}
+}
+
+func TestProbeMonitorShouldStopExperimentWhenThresholdBroken(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) > 2 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	probe := domain.Probe{
+		Name:             "frontend",
+		Url:              server.URL,
+		Interval:         domain.Duration(time.Millisecond),
+		FailureThreshold: 2,
+		AbortOnFailure:   true,
+	}
+	prober, err := newProbeRunner([]domain.Probe{probe})
+	assert.Nil(t, err)
+
+	ctx, stopExperiment := context.WithCancelCause(context.Background())
+	defer stopExperiment(nil)
+	stopProbes := prober.StartMonitor(ctx, stopExperiment)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("experiment was not stopped by the probe")
+	}
+	stopProbes()
+
+	assert.Equal(t, probeAbortError{Probe: "frontend", Reason: "unexpected status code 502"}, context.Cause(ctx))
+	results := prober.Results()
+	assert.Equal(t, 4, results[0].Samples)
+	assert.Equal(t, 2, results[0].Failures)
+	assert.True(t, results[0].Broken)
+	assert.NotNil(t, prober.Evaluate())
+}
+
+func TestProbeSampleAfterRecoveryShouldWaitForApplication(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	probe := domain.Probe{Url: server.URL, Interval: domain.Duration(time.Millisecond)}
+	prober, err := newProbeRunner([]domain.Probe{probe})
+	assert.Nil(t, err)
+	err = prober.SampleAfterRecovery(context.TODO())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, prober.Results()[0].Samples)
+	assert.Nil(t, prober.Evaluate())
+}
+
+func TestProbeTimeoutShouldAllowMaxLatency(t *testing.T) {
+	assert.Equal(t, DEFAULT_PROBE_TIMEOUT, probeTimeout(domain.Probe{}))
+	assert.Equal(t, DEFAULT_PROBE_TIMEOUT, probeTimeout(domain.Probe{MaxLatency: domain.Duration(time.Second)}))
+	assert.Equal(t, 30*time.Second, probeTimeout(domain.Probe{MaxLatency: domain.Duration(30 * time.Second)}))
+}
+
+func TestNewProbeRunnerShouldFailForInvalidBodyPattern(t *testing.T) {
+	probe := domain.Probe{Name: "frontend", Url: "http://localhost", BodyMatches: `"status":\s*(`}
+
+	_, err := newProbeRunner([]domain.Probe{probe})
+
+	assert.NotNil(t, err)
+}
+
+func TestFailShouldRequireDurationWithProbes(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	config := `{
+		"azs": ["us-east-1a"],
+		"targets": [{"type": "auto-scaling-group", "filter": "name=test-asg"}],
+		"probes": [{"url": "https://app.example.com/health"}]
+	}`
+	assert.Nil(t, os.WriteFile(configFile, []byte(config), 0600))
+
+	cmd := &FailCommand{Namespace: "test", ConfigFile: configFile}
+	err := cmd.Run(context.TODO())
+
+	assert.ErrorContains(t, err, "--duration")
+}
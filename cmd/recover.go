@@ -1,46 +1,187 @@
 package cmd
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/mcastellin/aws-fail-az/awsapis"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
+	"github.com/mcastellin/aws-fail-az/report"
 	"github.com/mcastellin/aws-fail-az/service"
+	"github.com/mcastellin/aws-fail-az/service/awsutils"
 	"github.com/mcastellin/aws-fail-az/state"
 )
 
 type RecoverCommand struct {
-	Provider  awsapis.AWSProvider
-	Namespace string
+	Provider     awsapis.AWSProvider
+	Namespace    string
+	ResourceType string
+	ResourceKey  string
+	DryRun       bool
+	// Optional fault configuration file with the lifecycle hooks to run
+	ConfigFile string
+	// Write a report of the recovery to this file when set
+	ReportFile string
 }
 
-func (cmd *RecoverCommand) Run() error {
+func (cmd *RecoverCommand) Run(ctx context.Context) error {
+	var recorder *report.Recorder
+	if cmd.ReportFile != "" && !cmd.DryRun {
+		recorder = report.NewRecorder("recover", cmd.Namespace)
+	}
+
+	err := cmd.run(ctx, recorder)
+	return errors.Join(err, writeReport(recorder, err, cmd.ReportFile))
+}
+
+func (cmd *RecoverCommand) run(ctx context.Context, recorder *report.Recorder) error {
 	stateManager, err := state.NewStateManager(cmd.Provider, cmd.Namespace)
 	if err != nil {
-		log.Print("Failed to create AWS state manager")
+		slog.Error("Failed to create AWS state manager", logging.Err(err))
+		return err
+	}
+
+	if err := stateManager.Initialize(ctx); err != nil {
 		return err
 	}
 
-	if err := stateManager.Initialize(); err != nil {
+	query := &state.QueryStatesInput{
+		ResourceType: cmd.ResourceType,
+		ResourceKey:  cmd.ResourceKey,
+	}
+
+	if cmd.DryRun {
+		return printRestorePlan(ctx, stateManager, query)
+	}
+
+	// The experiment record is only completed when all resources in the namespace are recovered
+	var tracker *experimentTracker
+	if cmd.ResourceType == "" && cmd.ResourceKey == "" {
+		tracker = loadExperimentTracker(ctx, stateManager, cmd.Namespace)
+	}
+
+	hooks := &lifecycleHooks{Namespace: cmd.Namespace}
+	if cmd.ConfigFile != "" {
+		faultConfig, err := readFaultConfiguration(false, cmd.ConfigFile)
+		if err != nil {
+			return err
+		}
+		states, err := stateManager.QueryStates(ctx, query)
+		if err != nil {
+			return err
+		}
+		// Random and surviving AZs selections are only known from the experiment record
+		var azs []string
+		if tracker != nil {
+			azs = tracker.Experiment.Azs
+		} else if len(faultConfig.Azs.List) > 0 {
+			azs, err = awsutils.ResolveAzNames(ctx, cmd.Provider.NewEc2Api(), faultConfig.Azs.List)
+			if err != nil {
+				return err
+			}
+		}
+		hooks.Config = faultConfig.Hooks
+		hooks.Azs = azs
+		recorder.SetAzs(azs)
+		hooks.Resources = hookResourcesFromStates(states)
+	}
+
+	tracker.SetPhase(ctx, state.ExperimentPhaseRestore)
+
+	err = hooks.Run(ctx, HookPhasePreRecover)
+	if err == nil {
+		err = restoreFromStates(ctx, cmd.Provider, stateManager, query, retryPolicyOrDefault(nil), recorder)
+		err = errors.Join(err, hooks.Run(ctx, HookPhasePostRecover))
+	}
+	tracker.Finish(ctx, err)
+	return err
+}
+
+// Prints the resource states that would be restored without modifying any resource
+func printRestorePlan(ctx context.Context, stateManager state.StateManager, query *state.QueryStatesInput) error {
+	states, err := stateManager.QueryStates(ctx, query)
+	if err != nil {
 		return err
 	}
 
-	states, err := stateManager.QueryStates(&state.QueryStatesInput{})
+	fmt.Printf("Plan: restoring %d resources\n", len(states))
+	for _, s := range states {
+		fmt.Printf("\n%s %s\n", s.ResourceType, s.ResourceKey)
+		fmt.Printf("  saved state: %s\n", string(s.State))
+	}
+	return nil
+}
+
+// The default time to wait for restored resources to match their saved state
+const DEFAULT_RESTORE_TIMEOUT = 15 * time.Minute
+
+// Restores the resources from the states matching the query in the state manager namespace.
+// Once restored, the resource configuration is verified against the saved state and the state
+// is removed. Returns an error summarizing all resources that could not be recovered
+func restoreFromStates(ctx context.Context, provider awsapis.AWSProvider, stateManager state.StateManager,
+	query *state.QueryStatesInput, retry domain.RetryPolicy, recorder *report.Recorder) error {
+
+	states, err := stateManager.QueryStates(ctx, query)
 	if err != nil {
 		return err
 	}
 
 	faultTypes := service.InitServiceFaults()
+	failures := []string{}
 	for _, s := range states {
-		err := faultTypes.RestoreFromState(s, cmd.Provider)
-		if err != nil {
-			log.Println(err)
-		} else {
-			err = stateManager.RemoveState(s)
+		logger := logging.ForResource(s.ResourceType, s.ResourceKey).With(logging.KeyPhase, report.PhaseRestore)
+		done := recorder.StartPhase(s.ResourceType, s.ResourceKey, report.PhaseRestore)
+		err := restoreResource(ctx, faultTypes, provider, s, retry, logger)
+		done(err)
+		if err == nil {
+			err = stateManager.RemoveState(ctx, s)
 			if err != nil {
-				log.Printf("Error removing state from storage: %v", err)
+				err = fmt.Errorf("Error removing state from storage: %v", err)
 			}
 		}
+		if err != nil {
+			logger.Error("Recovery failed", logging.Err(err))
+			failures = append(failures, fmt.Sprintf("%s %s", s.ResourceType, s.ResourceKey))
+		}
 	}
 
+	slog.Info("Recovered resources", "recovered", len(states)-len(failures), "total", len(states))
+	if len(failures) > 0 {
+		return fmt.Errorf("ERROR: recovery failed for %d of %d resources: %s",
+			len(failures), len(states), failures)
+	}
 	return nil
 }
+
+// Restores a single resource from its state and waits until the saved configuration is applied.
+// Only the restore is retried on temporary errors: verification polls the resource without
+// restoring it again, as restores may complete asynchronously
+func restoreResource(ctx context.Context, faultTypes *service.FaultsInitFns, provider awsapis.AWSProvider,
+	s state.ResourceState, retry domain.RetryPolicy, logger *slog.Logger) error {
+
+	resource, err := faultTypes.NewResourceFromState(s, provider)
+	if err != nil {
+		return err
+	}
+
+	err = retryActivity(ctx, retry, logger, func() error {
+		return resource.Restore(ctx)
+	})
+	if err != nil {
+		return err
+	}
+
+	verifier, ok := resource.(domain.RestoreVerifier)
+	if !ok {
+		return nil
+	}
+	verifyCtx, cancel := context.WithTimeout(ctx, DEFAULT_RESTORE_TIMEOUT)
+	defer cancel()
+	return waitUntil(verifyCtx, resource, report.PhaseRestore, "saved state", func() (bool, error) {
+		return verifier.VerifyRestored(verifyCtx)
+	})
+}
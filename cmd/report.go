@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"log/slog"
+
+	"github.com/mcastellin/aws-fail-az/report"
+)
+
+// Completes the report with the command result and writes it to the report file
+func writeReport(recorder *report.Recorder, result error, reportFile string) error {
+	if recorder == nil {
+		return nil
+	}
+	if err := recorder.Finish(result).WriteFile(reportFile); err != nil {
+		return err
+	}
+	slog.Info("Report written", "file", reportFile)
+	return nil
+}
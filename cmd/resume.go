@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
+	"github.com/mcastellin/aws-fail-az/state"
+)
+
+// Prepares the stages of an interrupted experiment to be resumed. Every resource that
+// already has a saved state in the namespace is replaced by a resource created from that
+// state, so that the original configuration is kept and restored instead of the degraded one.
+// Returns the set of resumed resources, whose states must not be saved again
+func resumeStages(ctx context.Context, stateManager state.StateManager, stages []experimentStage,
+	fromState func(state.ResourceState) (domain.ConsistentStateResource, error)) (map[domain.ConsistentStateResource]bool, error) {
+
+	states, err := stateManager.QueryStates(ctx, &state.QueryStatesInput{})
+	if err != nil {
+		return nil, err
+	}
+	savedStates := map[string]state.ResourceState{}
+	for _, s := range states {
+		savedStates[s.ResourceType+"/"+s.ResourceKey] = s
+	}
+
+	resumed := map[domain.ConsistentStateResource]bool{}
+	for _, stage := range stages {
+		for idx, resource := range stage.Resources {
+			s, ok := savedStates[resource.ResourceType()+"/"+resource.ResourceKey()]
+			if !ok {
+				continue
+			}
+			resumedResource, err := fromState(s)
+			if err != nil {
+				return nil, err
+			}
+			logging.ForResource(resource.ResourceType(), resource.ResourceKey()).Info(
+				"Resuming resource, keeping the original state saved by a previous run")
+			stage.Resources[idx] = resumedResource
+			resumed[resumedResource] = true
+		}
+	}
+
+	slog.Info("Resuming experiment", "resumed", len(resumed))
+	return resumed, nil
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResumeStagesShouldReplaceResourcesWithSavedStates(t *testing.T) {
+	stateManager := newFakeStateManager()
+	stateManager.Save(context.TODO(), "fake", "first", []byte(`{"original":true}`))
+	resources := []*fakeResource{{key: "first"}, {key: "second"}}
+	stages := []experimentStage{{Resources: toResources(resources)}}
+
+	var restoredFrom []byte
+	resumed, err := resumeStages(context.TODO(), stateManager, stages,
+		func(s state.ResourceState) (domain.ConsistentStateResource, error) {
+			restoredFrom = s.State
+			return &fakeResource{key: s.ResourceKey}, nil
+		})
+
+	assert.Nil(t, err)
+	assert.Len(t, resumed, 1)
+	assert.NotSame(t, resources[0], stages[0].Resources[0])
+	assert.True(t, resumed[stages[0].Resources[0]])
+	assert.Same(t, resources[1], stages[0].Resources[1])
+	assert.Equal(t, `{"original":true}`, string(restoredFrom))
+}
+
+func TestResumeStagesShouldFailForUnknownStates(t *testing.T) {
+	stateManager := newFakeStateManager()
+	stateManager.Save(context.TODO(), "fake", "first", []byte("{}"))
+	stages := []experimentStage{{Resources: toResources([]*fakeResource{{key: "first"}})}}
+
+	_, err := resumeStages(context.TODO(), stateManager, stages,
+		func(s state.ResourceState) (domain.ConsistentStateResource, error) {
+			return nil, fmt.Errorf("unknown resource")
+		})
+
+	assert.ErrorContains(t, err, "unknown resource")
+}
+
+func TestFailResourcesShouldKeepStatesOfResumedResources(t *testing.T) {
+	stateManager := newFakeStateManager()
+	stateManager.Save(context.TODO(), "fake", "first", []byte(`{"original":true}`))
+	resources := []*fakeResource{{key: "first"}, {key: "second"}}
+	resumed := map[domain.ConsistentStateResource]bool{resources[0]: true}
+
+	err := failResources(context.TODO(), stateManager, toResources(resources),
+		failOptions{Azs: []string{"us-east-1a"}, Resumed: resumed})
+
+	assert.Nil(t, err)
+	assert.True(t, resources[0].failed)
+	assert.True(t, resources[1].failed)
+	assert.Len(t, stateManager.states, 2)
+	assert.Equal(t, `{"original":true}`, string(stateManager.states["fake/first"].State))
+}
+
+func TestFailResourcesShouldRemoveStatesOfResumedResourcesOnRollback(t *testing.T) {
+	stateManager := newFakeStateManager()
+	stateManager.Save(context.TODO(), "fake", "first", []byte("{}"))
+	resources := []*fakeResource{{key: "first"}, {key: "second", failErr: fmt.Errorf("fail error")}}
+	resumed := map[domain.ConsistentStateResource]bool{resources[0]: true}
+
+	err := failResources(context.TODO(), stateManager, toResources(resources),
+		failOptions{Azs: []string{"us-east-1a"}, Rollback: true, Resumed: resumed})
+
+	assert.NotNil(t, err)
+	assert.True(t, resources[0].restored)
+	assert.Len(t, stateManager.states, 0)
+}
+
+func TestFailResourcesShouldRestoreResumedResourcesNotFailedOnRollback(t *testing.T) {
+	stateManager := newFakeStateManager()
+	stateManager.Save(context.TODO(), "fake", "second", []byte(`{"original":true}`))
+	resources := []*fakeResource{{key: "first", failErr: fmt.Errorf("fail error")}, {key: "second"}}
+	resumed := map[domain.ConsistentStateResource]bool{resources[1]: true}
+
+	err := failResources(context.TODO(), stateManager, toResources(resources),
+		failOptions{Azs: []string{"us-east-1a"}, Rollback: true, Resumed: resumed})
+
+	assert.NotNil(t, err)
+	assert.False(t, resources[1].failed)
+	assert.True(t, resources[1].restored)
+	assert.Len(t, stateManager.states, 0)
+}
+
+func TestFailResourcesShouldKeepStatesOfResumedResourcesWhenRestoreFails(t *testing.T) {
+	stateManager := newFakeStateManager()
+	stateManager.Save(context.TODO(), "fake", "second", []byte(`{"original":true}`))
+	resources := []*fakeResource{
+		{key: "first", failErr: fmt.Errorf("fail error")},
+		{key: "second", restoreErr: fmt.Errorf("restore error")},
+	}
+	resumed := map[domain.ConsistentStateResource]bool{resources[1]: true}
+
+	err := failResources(context.TODO(), stateManager, toResources(resources),
+		failOptions{Azs: []string{"us-east-1a"}, Rollback: true, Resumed: resumed})
+
+	assert.NotNil(t, err)
+	assert.Len(t, stateManager.states, 1)
+	assert.Equal(t, `{"original":true}`, string(stateManager.states["fake/second"].State))
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
+)
+
+// Default retry policy values for resource activities
+const (
+	DEFAULT_RETRY_MAX_ATTEMPTS  = 5
+	DEFAULT_RETRY_INITIAL_DELAY = 1 * time.Second
+	DEFAULT_RETRY_MAX_DELAY     = 30 * time.Second
+)
+
+// Returns the retry policy with default values for all unset fields
+func retryPolicyOrDefault(policy *domain.RetryPolicy) domain.RetryPolicy {
+	result := domain.RetryPolicy{}
+	if policy != nil {
+		result = *policy
+	}
+	if result.MaxAttempts == 0 {
+		result.MaxAttempts = DEFAULT_RETRY_MAX_ATTEMPTS
+	}
+	if result.InitialDelay == 0 {
+		result.InitialDelay = domain.Duration(DEFAULT_RETRY_INITIAL_DELAY)
+	}
+	if result.MaxDelay == 0 {
+		result.MaxDelay = max(domain.Duration(DEFAULT_RETRY_MAX_DELAY), result.InitialDelay)
+	}
+	return result
+}
+
+// Runs `activityFn` and retries it with jittered exponential backoff as long as it
+// fails with a temporary ActivityFailedError. Any other error, including
+// InterruptExecutionError, is returned immediately. Retries are logged with `logger`
+func retryActivity(ctx context.Context, policy domain.RetryPolicy, logger *slog.Logger, activityFn func() error) error {
+	delay := time.Duration(policy.InitialDelay)
+	for attempt := 1; ; attempt++ {
+		err := activityFn()
+		if err == nil || !isTemporary(err) || attempt >= policy.MaxAttempts {
+			return err
+		}
+
+		// Equal jitter: wait between half and the full backoff delay
+		wait := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		logger.Warn("Activity failed with a temporary error, retrying",
+			"attempt", attempt, "maxAttempts", policy.MaxAttempts, "retryIn", wait, logging.Err(err))
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, domain.InterruptExecutionError{Wrap: context.Cause(ctx)})
+		case <-time.After(wait):
+		}
+		delay = min(2*delay, time.Duration(policy.MaxDelay))
+	}
+}
+
+// Returns true if the error is a temporary ActivityFailedError
+func isTemporary(err error) bool {
+	var activityErr domain.ActivityFailedError
+	return errors.As(err, &activityErr) && activityErr.IsTemporary()
+}
+
+// Returns true if the error requires the program execution to be interrupted
+func isInterrupt(err error) bool {
+	var interruptErr domain.InterruptExecutionError
+	return errors.As(err, &interruptErr)
+}
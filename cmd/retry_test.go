@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRetryPolicy = domain.RetryPolicy{
+	MaxAttempts:  3,
+	InitialDelay: domain.Duration(time.Millisecond),
+	MaxDelay:     domain.Duration(time.Millisecond),
+}
+
+func TestRetryActivityShouldRetryTemporaryErrors(t *testing.T) {
+	attempts := 0
+	err := retryActivity(context.TODO(), testRetryPolicy, slog.Default(), func() error {
+		attempts++
+		if attempts < 3 {
+			return domain.ActivityFailedError{Wrap: fmt.Errorf("throttled"), Temporary: true}
+		}
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetryActivityShouldStopAfterMaxAttempts(t *testing.T) {
+	attempts := 0
+	err := retryActivity(context.TODO(), testRetryPolicy, slog.Default(), func() error {
+		attempts++
+		return domain.ActivityFailedError{Wrap: fmt.Errorf("throttled"), Temporary: true}
+	})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetryActivityShouldNotRetryPermanentErrors(t *testing.T) {
+	errs := []error{
+		domain.ActivityFailedError{Wrap: fmt.Errorf("invalid parameter"), Temporary: false},
+		domain.InterruptExecutionError{Wrap: fmt.Errorf("access denied")},
+		fmt.Errorf("unclassified error"),
+	}
+
+	for _, activityErr := range errs {
+		attempts := 0
+		err := retryActivity(context.TODO(), testRetryPolicy, slog.Default(), func() error {
+			attempts++
+			return activityErr
+		})
+
+		assert.ErrorIs(t, err, activityErr)
+		assert.Equal(t, 1, attempts)
+	}
+}
+
+func TestRetryActivityShouldInterruptWhenContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := domain.RetryPolicy{MaxAttempts: 10, InitialDelay: domain.Duration(time.Hour), MaxDelay: domain.Duration(time.Hour)}
+
+	attempts := 0
+	err := retryActivity(ctx, policy, slog.Default(), func() error {
+		attempts++
+		cancel()
+		return domain.ActivityFailedError{Wrap: fmt.Errorf("throttled"), Temporary: true}
+	})
+
+	assert.True(t, isInterrupt(err))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestRetryPolicyOrDefaultShouldFillUnsetValues(t *testing.T) {
+	policy := retryPolicyOrDefault(&domain.RetryPolicy{MaxAttempts: 2})
+
+	assert.Equal(t, 2, policy.MaxAttempts)
+	assert.Equal(t, domain.Duration(DEFAULT_RETRY_INITIAL_DELAY), policy.InitialDelay)
+	assert.Equal(t, domain.Duration(DEFAULT_RETRY_MAX_DELAY), policy.MaxDelay)
+}
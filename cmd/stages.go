@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
+	"github.com/mcastellin/aws-fail-az/state"
+)
+
+// A stage of the AZ failure experiment with its target resources
+type experimentStage struct {
+	domain.FaultStage
+	Resources []domain.ConsistentStateResource
+}
+
+// Returns a description of the stage for log messages
+func (s experimentStage) describe(idx int, total int) string {
+	if s.Name == "" {
+		return fmt.Sprintf("stage %d/%d", idx+1, total)
+	}
+	return fmt.Sprintf("stage %d/%d (%s)", idx+1, total, s.Name)
+}
+
+// Fails the resources of every stage in order. The next stage only starts after the
+// previous stage has been verified and its wait time has elapsed. Stops escalating as
+// soon as a stage fails and, if stage rollback is enabled, restores the resources
+// failed in all completed stages.
+// Returns the time the last stage finished failing resources, or a zero time if
+// the experiment stopped before failing the resources of the last stage
+func runStages(ctx context.Context, stateManager state.StateManager, stages []experimentStage,
+	opts failOptions) (time.Time, error) {
+
+	var failedAt time.Time
+	completed := []domain.ConsistentStateResource{}
+	for idx, stage := range stages {
+		description := stage.describe(idx, len(stages))
+		if len(stages) > 1 {
+			slog.Info("Starting stage", "stage", description, "resources", len(stage.Resources))
+		}
+
+		err := failResources(ctx, stateManager, stage.Resources, opts)
+		if err == nil {
+			if idx == len(stages)-1 {
+				failedAt = time.Now()
+			}
+			completed = append(completed, stage.Resources...)
+			if stage.Verify != nil {
+				err = verifySteadyState(ctx, stage.Verify, stage.Resources, opts.Azs, opts.Report)
+			}
+		}
+		if err != nil && len(stages) == 1 {
+			return failedAt, err
+		}
+		if err != nil {
+			err = fmt.Errorf("%s failed: %w", description, err)
+			if !opts.StageRollback || len(completed) == 0 {
+				return failedAt, err
+			}
+
+			slog.Error("Stage failed, rolling back resources failed in previous stages",
+				"stage", description, "resources", len(completed), logging.Err(err))
+			rollbackCtx := context.WithoutCancel(ctx)
+			return failedAt, errors.Join(err, rollbackResources(rollbackCtx, stateManager, completed, completed,
+				opts.Retry, opts.Report))
+		}
+
+		if idx < len(stages)-1 && stage.Wait > 0 {
+			slog.Info("Holding stage before the next stage", "stage", description, "wait", time.Duration(stage.Wait))
+			select {
+			case <-time.After(time.Duration(stage.Wait)):
+			case <-ctx.Done():
+				slog.Info("Experiment interrupted, not starting the remaining stages", "cause", context.Cause(ctx))
+				return failedAt, domain.InterruptExecutionError{Wrap: context.Cause(ctx)}
+			}
+		}
+	}
+	return failedAt, nil
+}
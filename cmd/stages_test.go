@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunStagesShouldFailAllStagesInOrder(t *testing.T) {
+	stateManager := newFakeStateManager()
+	first := &fakeResource{key: "first"}
+	second := &fakeResource{key: "second", failFn: func(ctx context.Context) error {
+		if !first.failed {
+			return fmt.Errorf("second stage started before the first one")
+		}
+		return nil
+	}}
+	stages := []experimentStage{
+		{FaultStage: domain.FaultStage{Name: "backend", Wait: domain.Duration(time.Millisecond)},
+			Resources: toResources([]*fakeResource{first})},
+		{FaultStage: domain.FaultStage{Name: "frontend"}, Resources: toResources([]*fakeResource{second})},
+	}
+
+	failedAt, err := runStages(context.TODO(), stateManager, stages, failOptions{Azs: []string{"us-east-1a"}})
+
+	assert.Nil(t, err)
+	assert.False(t, failedAt.IsZero())
+	assert.True(t, first.failed)
+	assert.True(t, second.failed)
+	assert.Len(t, stateManager.states, 2)
+}
+
+func TestRunStagesShouldStopEscalatingWhenStageFails(t *testing.T) {
+	stateManager := newFakeStateManager()
+	resources := []*fakeResource{
+		{key: "first"},
+		{key: "second", failErr: fmt.Errorf("failed to update resource")},
+		{key: "third"},
+	}
+	stages := []experimentStage{
+		{Resources: toResources(resources[:1])},
+		{Resources: toResources(resources[1:2])},
+		{Resources: toResources(resources[2:])},
+	}
+
+	failedAt, err := runStages(context.TODO(), stateManager, stages,
+		failOptions{Azs: []string{"us-east-1a"}, Rollback: true})
+
+	assert.ErrorContains(t, err, "stage 2/3 failed")
+	assert.True(t, failedAt.IsZero())
+	assert.False(t, resources[0].restored)
+	assert.True(t, resources[1].restored)
+	assert.False(t, resources[2].failed)
+	assert.Len(t, stateManager.states, 1)
+}
+
+func TestRunStagesShouldRollbackPreviousStagesWhenStageFails(t *testing.T) {
+	stateManager := newFakeStateManager()
+	resources := []*fakeResource{
+		{key: "first"},
+		{key: "second", failErr: fmt.Errorf("failed to update resource")},
+	}
+	stages := []experimentStage{
+		{Resources: toResources(resources[:1])},
+		{Resources: toResources(resources[1:])},
+	}
+
+	_, err := runStages(context.TODO(), stateManager, stages,
+		failOptions{Azs: []string{"us-east-1a"}, Rollback: true, StageRollback: true})
+
+	assert.NotNil(t, err)
+	assert.True(t, resources[0].restored)
+	assert.True(t, resources[1].restored)
+	assert.Len(t, stateManager.states, 0)
+}
+
+func TestRunStagesShouldStopWhenInterruptedDuringWait(t *testing.T) {
+	stateManager := newFakeStateManager()
+	resources := []*fakeResource{{key: "first"}, {key: "second"}}
+	stages := []experimentStage{
+		{FaultStage: domain.FaultStage{Wait: domain.Duration(time.Hour)}, Resources: toResources(resources[:1])},
+		{Resources: toResources(resources[1:])},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := runStages(ctx, stateManager, stages, failOptions{Azs: []string{"us-east-1a"}})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, resources[0].failed)
+	assert.False(t, resources[1].failed)
+}
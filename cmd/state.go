@@ -1,13 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"os"
 
 	"github.com/mcastellin/aws-fail-az/awsapis"
+	"github.com/mcastellin/aws-fail-az/logging"
 	"github.com/mcastellin/aws-fail-az/state"
 )
 
@@ -27,7 +29,7 @@ type SaveStateCommand struct {
 	StateData     string
 }
 
-func (cmd *SaveStateCommand) Run() error {
+func (cmd *SaveStateCommand) Run(ctx context.Context) error {
 
 	var statePayload []byte
 	var err error
@@ -46,14 +48,14 @@ func (cmd *SaveStateCommand) Run() error {
 
 	stateManager, err := state.NewStateManager(cmd.Provider, cmd.Namespace)
 	if err != nil {
-		log.Print("Failed to create AWS state manager")
+		slog.Error("Failed to create AWS state manager", logging.Err(err))
 		return err
 	}
-	if err := stateManager.Initialize(); err != nil {
+	if err := stateManager.Initialize(ctx); err != nil {
 		return err
 	}
 
-	err = stateManager.Save(cmd.ResourceType, cmd.ResourceKey, statePayload)
+	err = stateManager.Save(ctx, cmd.ResourceType, cmd.ResourceKey, statePayload)
 	if err != nil {
 		return err
 	}
@@ -68,20 +70,17 @@ type ReadStatesCommand struct {
 	ResourceKey  string
 }
 
-func (cmd *ReadStatesCommand) Run() error {
-	// Discard logging to facilitate output parsing
-	log.SetOutput(io.Discard)
-
+func (cmd *ReadStatesCommand) Run(ctx context.Context) error {
 	stateManager, err := state.NewStateManager(cmd.Provider, cmd.Namespace)
 	if err != nil {
-		log.Print("Failed to create AWS state manager")
+		slog.Error("Failed to create AWS state manager", logging.Err(err))
 		return err
 	}
-	if err := stateManager.Initialize(); err != nil {
+	if err := stateManager.Initialize(ctx); err != nil {
 		return err
 	}
 
-	states, err := stateManager.QueryStates(&state.QueryStatesInput{
+	states, err := stateManager.QueryStates(ctx, &state.QueryStatesInput{
 		ResourceType: cmd.ResourceType,
 		ResourceKey:  cmd.ResourceKey,
 	})
@@ -103,7 +102,7 @@ func (cmd *ReadStatesCommand) Run() error {
 	if len(states) > 0 {
 		stateJSON, err := json.Marshal(stateData)
 		if err != nil {
-			fmt.Println("Error unmarshalling state object. Exiting.")
+			slog.Error("Failed to marshal state objects", logging.Err(err))
 			return err
 		}
 		fmt.Println(string(stateJSON))
@@ -121,27 +120,27 @@ type DeleteStateCommand struct {
 	ResourceKey  string
 }
 
-func (cmd *DeleteStateCommand) Run() error {
+func (cmd *DeleteStateCommand) Run(ctx context.Context) error {
 	stateManager, err := state.NewStateManager(cmd.Provider, cmd.Namespace)
 	if err != nil {
-		log.Print("Failed to create AWS state manager")
+		slog.Error("Failed to create AWS state manager", logging.Err(err))
 		return err
 	}
-	if err := stateManager.Initialize(); err != nil {
+	if err := stateManager.Initialize(ctx); err != nil {
 		return err
 	}
 
-	result, err := stateManager.GetState(cmd.ResourceType, cmd.ResourceKey)
+	result, err := stateManager.GetState(ctx, cmd.ResourceType, cmd.ResourceKey)
 	if err != nil {
 		return err
 	}
 
-	err = stateManager.RemoveState(*result)
+	err = stateManager.RemoveState(ctx, *result)
 	if err != nil {
-		log.Printf("Error removing state object with key %s", result.Key)
+		slog.Error("Failed to remove state object", "stateKey", result.Key, logging.Err(err))
 		return err
 	}
-	log.Printf("State with key %s removed successfully", result.Key)
+	slog.Info("State removed successfully", "stateKey", result.Key)
 
 	return nil
 }
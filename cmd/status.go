@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mcastellin/aws-fail-az/awsapis"
+	"github.com/mcastellin/aws-fail-az/state"
+)
+
+type StatusCommand struct {
+	Provider  awsapis.AWSProvider
+	Namespace string
+}
+
+func (cmd *StatusCommand) Run(ctx context.Context) error {
+	stateManager, err := state.NewStateManager(cmd.Provider, cmd.Namespace)
+	if err != nil {
+		return err
+	}
+	if err := stateManager.Initialize(ctx); err != nil {
+		return err
+	}
+
+	experiments, err := stateManager.QueryExperiments(ctx, cmd.Namespace)
+	if err != nil {
+		return err
+	}
+	if cmd.Namespace != "" && len(experiments) == 0 {
+		return fmt.Errorf("No experiment found in namespace %s", cmd.Namespace)
+	}
+	if err := printExperiments(os.Stdout, experiments); err != nil {
+		return err
+	}
+	if cmd.Namespace != "" && experiments[0].Error != "" {
+		fmt.Printf("\nError: %s\n", experiments[0].Error)
+	}
+	return nil
+}
+
+// Prints a table of the experiments with active experiments first, then the most recent ones
+func printExperiments(w io.Writer, experiments []state.Experiment) error {
+	sort.SliceStable(experiments, func(i, j int) bool {
+		if experiments[i].IsActive() != experiments[j].IsActive() {
+			return experiments[i].IsActive()
+		}
+		return experiments[i].StartTime.After(experiments[j].StartTime)
+	})
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "NAMESPACE\tSTATUS\tPHASE\tSTARTED\tENDED\tAZS\tCALLER")
+	for _, experiment := range experiments {
+		status := experiment.Outcome
+		if experiment.IsActive() {
+			status = "active"
+		}
+		ended := "-"
+		if experiment.EndTime != nil {
+			ended = experiment.EndTime.Format(time.RFC3339)
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", experiment.Namespace, status, experiment.Phase,
+			experiment.StartTime.Format(time.RFC3339), ended, strings.Join(experiment.Azs, ","), experiment.CallerIdentity)
+	}
+	return table.Flush()
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/mcastellin/aws-fail-az/awsapis"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
+)
+
+// The time between consecutive checks of the stop condition alarms
+var stopConditionsCheckInterval = 15 * time.Second
+
+// An error to signal the experiment was stopped because a stop condition was met
+type stopConditionError struct {
+	Alarm  string
+	Reason string
+}
+
+func (e stopConditionError) Error() string {
+	return fmt.Sprintf("stop condition met: alarm %s is in ALARM state: %s", e.Alarm, e.Reason)
+}
+
+// Watches the CloudWatch alarms configured as stop conditions for the experiment
+type stopConditionsWatcher struct {
+	Api        awsapis.CloudWatchApi
+	AlarmNames []string
+}
+
+func newStopConditionsWatcher(api awsapis.CloudWatchApi, conditions []domain.StopCondition) *stopConditionsWatcher {
+	alarmNames := []string{}
+	for _, condition := range conditions {
+		alarmNames = append(alarmNames, alarmName(condition.Alarm))
+	}
+	return &stopConditionsWatcher{Api: api, AlarmNames: alarmNames}
+}
+
+// The state of a metric or composite alarm
+type alarmState struct {
+	Name   *string
+	State  types.StateValue
+	Reason *string
+}
+
+// Returns the alarm name from an alarm name or ARN
+func alarmName(nameOrArn string) string {
+	alarmArn, err := arn.Parse(nameOrArn)
+	if err != nil {
+		return nameOrArn
+	}
+	return strings.TrimPrefix(alarmArn.Resource, "alarm:")
+}
+
+// Checks the state of all alarms. Returns a stopConditionError if any of the
+// alarms is in ALARM state or an error if an alarm does not exist
+func (w *stopConditionsWatcher) Check(ctx context.Context) error {
+	found := map[string]bool{}
+
+	input := &cloudwatch.DescribeAlarmsInput{
+		AlarmNames: w.AlarmNames,
+		AlarmTypes: []types.AlarmType{types.AlarmTypeMetricAlarm, types.AlarmTypeCompositeAlarm},
+	}
+	for {
+		output, err := w.Api.DescribeAlarms(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		alarms := []alarmState{}
+		for _, alarm := range output.MetricAlarms {
+			alarms = append(alarms, alarmState{alarm.AlarmName, alarm.StateValue, alarm.StateReason})
+		}
+		for _, alarm := range output.CompositeAlarms {
+			alarms = append(alarms, alarmState{alarm.AlarmName, alarm.StateValue, alarm.StateReason})
+		}
+
+		for _, alarm := range alarms {
+			found[aws.ToString(alarm.Name)] = true
+			if alarm.State == types.StateValueAlarm {
+				return stopConditionError{Alarm: aws.ToString(alarm.Name), Reason: aws.ToString(alarm.Reason)}
+			}
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	for _, name := range w.AlarmNames {
+		if !found[name] {
+			return fmt.Errorf("stop condition alarm %s not found", name)
+		}
+	}
+	return nil
+}
+
+// Checks the alarms periodically until the context is cancelled. When a stop condition
+// is met, the experiment is stopped by cancelling the context with a stopConditionError
+func (w *stopConditionsWatcher) Watch(ctx context.Context, stopExperiment context.CancelCauseFunc) {
+	slog.Info("Watching stop condition alarms", "alarms", w.AlarmNames)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(stopConditionsCheckInterval):
+		}
+
+		err := w.Check(ctx)
+		if stopErr, ok := err.(stopConditionError); ok {
+			slog.Error("Stop condition met, stopping the experiment", "alarm", stopErr.Alarm, "reason", stopErr.Reason)
+			stopExperiment(stopErr)
+			return
+		}
+		if err != nil && ctx.Err() == nil {
+			slog.Warn("Failed to check stop condition alarms", logging.Err(err))
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/mcastellin/aws-fail-az/awsapis_mocks"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewStopConditionsWatcherShouldAcceptAlarmArns(t *testing.T) {
+	watcher := newStopConditionsWatcher(nil, []domain.StopCondition{
+		{Alarm: "high-error-rate"},
+		{Alarm: "arn:aws:cloudwatch:us-east-1:123456789012:alarm:high-latency"},
+	})
+
+	assert.Equal(t, []string{"high-error-rate", "high-latency"}, watcher.AlarmNames)
+}
+
+func TestStopConditionsCheckShouldReturnErrorWhenInAlarm(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockCloudWatchApi(ctrl)
+	mockApi.EXPECT().DescribeAlarms(gomock.Any(), gomock.Any()).Times(1).
+		Return(&cloudwatch.DescribeAlarmsOutput{
+			MetricAlarms: []types.MetricAlarm{
+				{AlarmName: aws.String("high-error-rate"), StateValue: types.StateValueOk},
+			},
+			CompositeAlarms: []types.CompositeAlarm{
+				{AlarmName: aws.String("service-health"), StateValue: types.StateValueAlarm,
+					StateReason: aws.String("threshold crossed")},
+			},
+		}, nil)
+
+	watcher := newStopConditionsWatcher(mockApi, []domain.StopCondition{
+		{Alarm: "high-error-rate"}, {Alarm: "service-health"},
+	})
+	err := watcher.Check(context.TODO())
+
+	assert.Equal(t, stopConditionError{Alarm: "service-health", Reason: "threshold crossed"}, err)
+}
+
+func TestStopConditionsCheckShouldFailWhenAlarmNotFound(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockCloudWatchApi(ctrl)
+	mockApi.EXPECT().DescribeAlarms(gomock.Any(), gomock.Any()).Times(1).
+		Return(&cloudwatch.DescribeAlarmsOutput{
+			MetricAlarms: []types.MetricAlarm{
+				{AlarmName: aws.String("high-error-rate"), StateValue: types.StateValueOk},
+			},
+		}, nil)
+
+	watcher := newStopConditionsWatcher(mockApi, []domain.StopCondition{
+		{Alarm: "high-error-rate"}, {Alarm: "missing-alarm"},
+	})
+	err := watcher.Check(context.TODO())
+
+	assert.ErrorContains(t, err, "missing-alarm not found")
+}
+
+func TestStopConditionsWatchShouldStopExperimentWhenInAlarm(t *testing.T) {
+	stopConditionsCheckInterval = time.Millisecond
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockCloudWatchApi(ctrl)
+	gomock.InOrder(
+		mockApi.EXPECT().DescribeAlarms(gomock.Any(), gomock.Any()).Times(2).
+			Return(&cloudwatch.DescribeAlarmsOutput{
+				MetricAlarms: []types.MetricAlarm{{AlarmName: aws.String("high-error-rate"), StateValue: types.StateValueOk}},
+			}, nil),
+		mockApi.EXPECT().DescribeAlarms(gomock.Any(), gomock.Any()).Times(1).
+			Return(&cloudwatch.DescribeAlarmsOutput{
+				MetricAlarms: []types.MetricAlarm{{AlarmName: aws.String("high-error-rate"), StateValue: types.StateValueAlarm}},
+			}, nil),
+	)
+
+	ctx, stopExperiment := context.WithCancelCause(context.Background())
+	defer stopExperiment(nil)
+	watcher := newStopConditionsWatcher(mockApi, []domain.StopCondition{{Alarm: "high-error-rate"}})
+	watcher.Watch(ctx, stopExperiment)
+
+	assert.Equal(t, stopConditionError{Alarm: "high-error-rate"}, context.Cause(ctx))
+}
+
+func TestFailShouldRequireDurationWithStopConditions(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	config := `{
+		"azs": ["us-east-1a"],
+		"targets": [{"type": "auto-scaling-group", "filter": "name=test-asg"}],
+		"stopConditions": [{"alarm": "high-error-rate"}]
+	}`
+	assert.Nil(t, os.WriteFile(configFile, []byte(config), 0600))
+
+	cmd := &FailCommand{Namespace: "test", ConfigFile: configFile}
+	err := cmd.Run(context.TODO())
+
+	assert.ErrorContains(t, err, "--duration")
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
+	"github.com/mcastellin/aws-fail-az/report"
+)
+
+// The default time to wait for resources to reach a steady state after failure
+const DEFAULT_VERIFY_TIMEOUT = 15 * time.Minute
+
+// The result of the steady state verification of a single resource
+type steadyStateResult struct {
+	Resource          domain.ConsistentStateResource
+	Verified          bool
+	Steady            bool
+	TimeToSteadyState time.Duration
+}
+
+// Waits for all resources to reach a steady state after AZ failure and records the
+// time it took every resource to get there, measured from the end of the failure phase.
+// Returns an error if a resource does not reach a steady state before the verification
+// timeout or if any of the configured SLOs is violated
+func verifySteadyState(ctx context.Context, config *domain.VerifyConfiguration,
+	resources []domain.ConsistentStateResource, azs []string, recorder *report.Recorder) error {
+
+	timeout := time.Duration(config.Timeout)
+	if timeout == 0 {
+		timeout = DEFAULT_VERIFY_TIMEOUT
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	slog.Info("Verifying resources reach a steady state after AZ failure")
+	start := time.Now()
+	results := make([]steadyStateResult, len(resources))
+
+	wg := new(sync.WaitGroup)
+	for idx, resource := range resources {
+		results[idx].Resource = resource
+
+		verifier, ok := resource.(domain.SteadyStateVerifier)
+		if !ok {
+			activityLogger(resource, report.PhaseVerify).Warn(
+				"Steady state verification is not supported for resource type")
+			continue
+		}
+
+		wg.Add(1)
+		go func(result *steadyStateResult, verifier domain.SteadyStateVerifier) {
+			defer wg.Done()
+			result.Verified = true
+			done := recorder.StartPhase(result.Resource.ResourceType(), result.Resource.ResourceKey(), report.PhaseVerify)
+			err := waitUntil(ctx, result.Resource, report.PhaseVerify, "steady state", func() (bool, error) {
+				return verifier.VerifySteadyState(ctx, azs)
+			})
+			done(err)
+			result.Steady = err == nil
+			result.TimeToSteadyState = time.Since(start)
+		}(&results[idx], verifier)
+	}
+	wg.Wait()
+
+	return evaluateSteadyStateResults(config.Slos, results)
+}
+
+// Reports the time to steady state of every resource and evaluates it against the SLOs
+func evaluateSteadyStateResults(slos []domain.SteadyStateSlo, results []steadyStateResult) error {
+	errs := []error{}
+	for _, result := range results {
+		resourceType, resourceKey := result.Resource.ResourceType(), result.Resource.ResourceKey()
+		if !result.Verified {
+			continue
+		}
+		if !result.Steady {
+			errs = append(errs, fmt.Errorf("%s %s did not reach a steady state after AZ failure",
+				resourceType, resourceKey))
+			continue
+		}
+
+		logging.ForResource(resourceType, resourceKey).Info("Reached steady state",
+			logging.KeyPhase, report.PhaseVerify, "timeToSteadyState", result.TimeToSteadyState.Round(time.Second))
+
+		for _, slo := range slos {
+			maxTime := time.Duration(slo.MaxTimeToSteadyState)
+			if slo.Matches(resourceType, resourceKey) && result.TimeToSteadyState > maxTime {
+				errs = append(errs, fmt.Errorf("SLO violated: %s %s reached steady state in %s, expected less than %s",
+					resourceType, resourceKey, result.TimeToSteadyState.Round(time.Second), maxTime))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateSteadyStateResultsShouldReportSloViolations(t *testing.T) {
+	var config domain.VerifyConfiguration
+	err := json.Unmarshal([]byte(`{
+		"timeout": "10m",
+		"slos": [{"type": "fake", "maxTimeToSteadyState": "5m"}]
+	}`), &config)
+	assert.Nil(t, err)
+	assert.Equal(t, domain.Duration(10*time.Minute), config.Timeout)
+
+	results := []steadyStateResult{
+		{Resource: &fakeResource{key: "fast"}, Verified: true, Steady: true, TimeToSteadyState: 2 * time.Minute},
+		{Resource: &fakeResource{key: "slow"}, Verified: true, Steady: true, TimeToSteadyState: 6 * time.Minute},
+	}
+
+	err = evaluateSteadyStateResults(config.Slos, results)
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fake slow")
+	assert.NotContains(t, err.Error(), "fake fast")
+}
+
+func TestEvaluateSteadyStateResultsShouldFailWhenResourceNotSteady(t *testing.T) {
+	results := []steadyStateResult{
+		{Resource: &fakeResource{key: "first"}, Verified: true, Steady: false},
+		{Resource: &fakeResource{key: "second"}, Verified: false},
+	}
+
+	err := evaluateSteadyStateResults([]domain.SteadyStateSlo{}, results)
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "fake first")
+	assert.NotContains(t, err.Error(), "fake second")
+}
+
+func TestEvaluateSteadyStateResultsShouldMatchSloByKey(t *testing.T) {
+	slos := []domain.SteadyStateSlo{
+		{Type: "fake", Key: "other", MaxTimeToSteadyState: domain.Duration(time.Minute)},
+	}
+	results := []steadyStateResult{
+		{Resource: &fakeResource{key: "first"}, Verified: true, Steady: true, TimeToSteadyState: 2 * time.Minute},
+	}
+
+	err := evaluateSteadyStateResults(slos, results)
+
+	assert.Nil(t, err)
+}
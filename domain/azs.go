@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// The availability zones to fail. Configured either as a list of AZs or as a random
+// selection among the AZs used by the target resources (i.e. {"random": 1, "seed": 42})
+type AzsSelector struct {
+	// The AZs to fail
+	List []string
+	// The number of AZs to select at random
+	Random int
+	// The seed of the random selection. The same seed and targets select the same AZs
+	Seed *int64
+}
+
+// The JSON representation of a random AZs selection
+type randomAzsSelector struct {
+	Random int    `json:"random"`
+	Seed   *int64 `json:"seed,omitempty"`
+}
+
+func (s AzsSelector) MarshalJSON() ([]byte, error) {
+	if s.IsRandom() {
+		return json.Marshal(randomAzsSelector{Random: s.Random, Seed: s.Seed})
+	}
+	return json.Marshal(s.List)
+}
+
+func (s *AzsSelector) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var selector randomAzsSelector
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&selector); err != nil {
+			return fmt.Errorf("Could not parse azs selector %s: %v", data, err)
+		}
+		*s = AzsSelector{Random: selector.Random, Seed: selector.Seed}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("Could not parse azs %s. Expected a list of AZs or a selector (i.e. {\"random\": 1})", data)
+	}
+	*s = AzsSelector{List: list}
+	return nil
+}
+
+// Returns true if the AZs are selected at random
+func (s AzsSelector) IsRandom() bool {
+	return s.Random != 0 || s.Seed != nil
+}
+
+// Validates either a list of AZs or a random selection has been configured
+func (s AzsSelector) Validate() error {
+	if s.IsRandom() {
+		if s.Random < 1 {
+			return fmt.Errorf("validation failed: 'azs' random selection must select at least 1 AZ")
+		}
+		return nil
+	}
+	if len(s.List) == 0 {
+		return fmt.Errorf("validation failed: 'azs' must be specified")
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// A time.Duration represented in JSON as a duration string (i.e. "5m", "1h30m")
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("Could not parse duration %s. Expected a duration string (i.e. \"5m\")", data)
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("Could not parse duration %s: %v", data, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
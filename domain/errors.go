@@ -11,6 +11,10 @@ func (e ActivityFailedError) Error() string {
 	return e.Wrap.Error()
 }
 
+func (e ActivityFailedError) Unwrap() error {
+	return e.Wrap
+}
+
 func (e ActivityFailedError) IsTemporary() bool {
 	return e.Temporary
 }
@@ -24,3 +28,7 @@ type InterruptExecutionError struct {
 func (e InterruptExecutionError) Error() string {
 	return e.Wrap.Error()
 }
+
+func (e InterruptExecutionError) Unwrap() error {
+	return e.Wrap
+}
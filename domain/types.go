@@ -1,7 +1,10 @@
 package domain
 
 import (
+	"context"
 	"fmt"
+	"net/url"
+	"regexp"
 
 	"github.com/mcastellin/aws-fail-az/state"
 )
@@ -10,21 +13,299 @@ const (
 	ResourceTypeEcsService        = "ecs-service"
 	ResourceTypeAutoScalingGroup  = "auto-scaling-group"
 	ResourceTypeElbv2LoadBalancer = "elbv2-load-balancer"
+	ResourceTypeRdsInstance       = "rds-instance"
+	ResourceTypeAuroraCluster     = "aurora-cluster"
+	ResourceTypeElastiCacheGroup  = "elasticache-replication-group"
+	ResourceTypeEksNodegroup      = "eks-nodegroup"
+	ResourceTypeLambdaFunction    = "lambda-function"
 )
 
 // A representation of an AWS resource state that can be
 // validated and stored with StateManager
 type ConsistentStateResource interface {
-	Check() (bool, error)
-	Save(state.StateManager) error
-	Fail([]string) error
-	Restore() error
+	ResourceType() string
+	ResourceKey() string
+	Check(context.Context) (bool, error)
+	Save(context.Context, state.StateManager) error
+	Fail(context.Context, []string) error
+	Restore(context.Context) error
+	Plan(context.Context, []string) (*FailurePlan, error)
+}
+
+// A description of the changes that Fail would apply to a resource
+type FailurePlan struct {
+	ResourceType   string   `json:"type"`
+	ResourceKey    string   `json:"key"`
+	CurrentSubnets []string `json:"currentSubnets"`
+	NewSubnets     []string `json:"newSubnets"`
+	// ECS tasks or EC2 instances that would be stopped or terminated
+	Terminations []string `json:"terminations"`
+}
+
+// A resource that can verify it has reached a steady state after AZ failure
+type SteadyStateVerifier interface {
+	VerifySteadyState(context.Context, []string) (bool, error)
+}
+
+// A resource that can verify its saved configuration was restored
+type RestoreVerifier interface {
+	VerifyRestored(context.Context) (bool, error)
+}
+
+// A resource that can describe its failure ahead of time. The returned function only issues
+// the calls that modify the resource, so synchronized failures can apply it right after the barrier
+type FailurePreparer interface {
+	PrepareFail(context.Context, []string) (func(context.Context) error, error)
 }
 
 // AZ Failure Configuration
 type FaultConfiguration struct {
-	Azs     []string         `json:"azs"`
+	Azs AzsSelector `json:"azs"`
+	// AZs that keep running while all other AZs used by targets are failed. Mutually exclusive with Azs
+	SurvivingAzs []string             `json:"survivingAzs,omitempty"`
+	Targets      []TargetSelector     `json:"targets"`
+	Verify       *VerifyConfiguration `json:"verify"`
+	Retry        *RetryPolicy         `json:"retry"`
+	// Ordered stages to fail targets gradually. Mutually exclusive with Targets
+	Stages []FaultStage `json:"stages"`
+	// Restore resources failed in all previous stages when a stage fails
+	RollbackOnStageFailure bool `json:"rollbackOnStageFailure"`
+	// Conditions that stop the experiment and restore all resources
+	StopConditions []StopCondition `json:"stopConditions"`
+	// HTTP probes to verify the application is reachable during the experiment
+	Probes []Probe `json:"probes"`
+	// Commands to run in every phase of the experiment lifecycle
+	Hooks *HooksConfiguration `json:"hooks"`
+}
+
+// Validates the fault configuration
+func (c FaultConfiguration) Validate() error {
+	if len(c.SurvivingAzs) > 0 {
+		if len(c.Azs.List) > 0 || c.Azs.IsRandom() {
+			return fmt.Errorf("validation failed: only one of 'azs' and 'survivingAzs' can be specified")
+		}
+	} else if err := c.Azs.Validate(); err != nil {
+		return err
+	}
+	if c.Retry != nil {
+		if err := c.Retry.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, condition := range c.StopConditions {
+		if err := condition.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, probe := range c.Probes {
+		if err := probe.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := c.Hooks.Validate(); err != nil {
+		return err
+	}
+	if len(c.Stages) > 0 {
+		if len(c.Targets) > 0 || c.Verify != nil {
+			return fmt.Errorf("validation failed: 'targets' and 'verify' must be configured in every stage when 'stages' are specified")
+		}
+		for _, stage := range c.Stages {
+			if err := stage.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return c.Verify.Validate()
+}
+
+// Returns the ordered list of stages for the AZ failure. Configurations
+// without stages have a single stage with all targets
+func (c FaultConfiguration) ExperimentStages() []FaultStage {
+	if len(c.Stages) > 0 {
+		return c.Stages
+	}
+	return []FaultStage{{Targets: c.Targets, Verify: c.Verify}}
+}
+
+// A stage of AZ failure for a subset of targets
+type FaultStage struct {
+	Name    string           `json:"name"`
 	Targets []TargetSelector `json:"targets"`
+	// Wait for resources to reach a steady state before the next stage starts
+	Verify *VerifyConfiguration `json:"verify"`
+	// The time to hold the AZ failure before the next stage starts
+	Wait Duration `json:"wait"`
+}
+
+// Validates all required fields for the stage have been provided
+func (s FaultStage) Validate() error {
+	if len(s.Targets) == 0 {
+		return fmt.Errorf("validation failed: stage '%s' has no 'targets'", s.Name)
+	}
+	if s.Wait < 0 {
+		return fmt.Errorf("validation failed: stage '%s' 'wait' must not be negative", s.Name)
+	}
+	return s.Verify.Validate()
+}
+
+// A condition to stop the experiment when a CloudWatch alarm goes into ALARM state
+type StopCondition struct {
+	// The name or ARN of the CloudWatch alarm
+	Alarm string `json:"alarm"`
+}
+
+// Validates all required fields for the stop condition have been provided
+func (s StopCondition) Validate() error {
+	if s.Alarm == "" {
+		return fmt.Errorf("validation failed: stop condition 'alarm' must be specified")
+	}
+	return nil
+}
+
+// Commands to run in every phase of the experiment lifecycle
+type HooksConfiguration struct {
+	PreCheck    []Hook `json:"preCheck"`
+	PreFail     []Hook `json:"preFail"`
+	PostFail    []Hook `json:"postFail"`
+	PreRecover  []Hook `json:"preRecover"`
+	PostRecover []Hook `json:"postRecover"`
+}
+
+// Validates all hooks in the configuration
+func (h *HooksConfiguration) Validate() error {
+	if h == nil {
+		return nil
+	}
+	for _, hooks := range [][]Hook{h.PreCheck, h.PreFail, h.PostFail, h.PreRecover, h.PostRecover} {
+		for _, hook := range hooks {
+			if err := hook.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// A shell command to run in a phase of the experiment lifecycle
+type Hook struct {
+	Command string `json:"command"`
+	// The maximum time for the command to complete
+	Timeout Duration `json:"timeout"`
+}
+
+// Validates all required fields for the hook have been provided
+func (h Hook) Validate() error {
+	if h.Command == "" {
+		return fmt.Errorf("validation failed: hook 'command' must be specified")
+	}
+	if h.Timeout < 0 {
+		return fmt.Errorf("validation failed: hook 'timeout' must not be negative")
+	}
+	return nil
+}
+
+// An HTTP probe sampled before, during and after the AZ failure
+type Probe struct {
+	Name   string `json:"name"`
+	Url    string `json:"url"`
+	Method string `json:"method"`
+	// Accepted response status codes. Any 2xx status code is accepted when empty
+	ExpectedStatus []int `json:"expectedStatus"`
+	// A regular expression the response body must match
+	BodyMatches string `json:"bodyMatches"`
+	// The maximum response time for the sample to succeed
+	MaxLatency Duration `json:"maxLatency"`
+	// The time between consecutive samples while the AZ is failed
+	Interval Duration `json:"interval"`
+	// The number of consecutive failed samples that break the probe
+	FailureThreshold int `json:"failureThreshold"`
+	// Stop the experiment and restore all resources when the probe breaks
+	AbortOnFailure bool `json:"abortOnFailure"`
+}
+
+// Validates all required fields for the probe have been provided
+func (p Probe) Validate() error {
+	probeUrl, err := url.Parse(p.Url)
+	if err != nil || (probeUrl.Scheme != "http" && probeUrl.Scheme != "https") || probeUrl.Host == "" {
+		return fmt.Errorf("validation failed: probe 'url' must be a valid http or https URL, found '%s'", p.Url)
+	}
+	if _, err := regexp.Compile(p.BodyMatches); err != nil {
+		return fmt.Errorf("validation failed: probe 'bodyMatches' is not a valid regular expression: %v", err)
+	}
+	if p.MaxLatency < 0 || p.Interval < 0 || p.FailureThreshold < 0 {
+		return fmt.Errorf("validation failed: probe 'maxLatency', 'interval' and 'failureThreshold' must not be negative")
+	}
+	return nil
+}
+
+// Retry policy for resource activities failing with temporary errors
+type RetryPolicy struct {
+	// The maximum number of attempts for each activity, including the first one
+	MaxAttempts int `json:"maxAttempts"`
+	// The delay before the first retry. Delays double on every retry
+	InitialDelay Duration `json:"initialDelay"`
+	// The maximum delay between consecutive retries
+	MaxDelay Duration `json:"maxDelay"`
+}
+
+// Validates the retry policy values are within range
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts < 0 {
+		return fmt.Errorf("validation failed: retry 'maxAttempts' must not be negative")
+	}
+	if p.InitialDelay < 0 || p.MaxDelay < 0 {
+		return fmt.Errorf("validation failed: retry delays must not be negative")
+	}
+	if p.MaxDelay > 0 && p.InitialDelay > p.MaxDelay {
+		return fmt.Errorf("validation failed: retry 'initialDelay' must not exceed 'maxDelay'")
+	}
+	return nil
+}
+
+// Post-failure steady state verification configuration
+type VerifyConfiguration struct {
+	// The maximum time to wait for resources to reach a steady state after failure
+	Timeout Duration `json:"timeout"`
+	// Service level objectives for the time resources take to reach a steady state
+	Slos []SteadyStateSlo `json:"slos"`
+}
+
+// Validates the service level objectives of the verification configuration
+func (v *VerifyConfiguration) Validate() error {
+	if v == nil {
+		return nil
+	}
+	for _, slo := range v.Slos {
+		if err := slo.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A service level objective on the time a resource takes to reach a steady
+// state after AZ failure
+type SteadyStateSlo struct {
+	Type string `json:"type"`
+	// Optionally restrict the objective to a single resource key
+	Key                  string   `json:"key"`
+	MaxTimeToSteadyState Duration `json:"maxTimeToSteadyState"`
+}
+
+// Validates all required fields for the service level objective have been provided
+func (s SteadyStateSlo) Validate() error {
+	if s.Type == "" {
+		return fmt.Errorf("validation failed: SLO 'type' must be specified")
+	}
+	if s.MaxTimeToSteadyState <= 0 {
+		return fmt.Errorf("validation failed: SLO 'maxTimeToSteadyState' must be a positive duration")
+	}
+	return nil
+}
+
+// Returns true if the service level objective applies to the resource
+func (s SteadyStateSlo) Matches(resourceType string, resourceKey string) bool {
+	return s.Type == resourceType && (s.Key == "" || s.Key == resourceKey)
 }
 
 // AWS Tag
@@ -38,6 +319,8 @@ type TargetSelector struct {
 	Type   string   `json:"type"`
 	Filter string   `json:"filter"`
 	Tags   []AWSTag `json:"tags"`
+	// Fail back to the original AZ when recovering resources that failed over to another AZ
+	FailBack bool `json:"failBack"`
 }
 
 // Validates all required fields for target selector have been provided
@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Attribute keys shared by all log records
+const (
+	KeyResourceType = "type"
+	KeyResourceKey  = "key"
+	KeyNamespace    = "namespace"
+	KeyPhase        = "phase"
+	KeyError        = "error"
+)
+
+// Supported log output formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Creates a logger writing records at or above `level` to `w` in the given format.
+// Valid levels are debug, info, warn and error; valid formats are text and json
+func NewLogger(w io.Writer, level string, format string) (*slog.Logger, error) {
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: expected one of debug, info, warn, error", level)
+	}
+	opts := &slog.HandlerOptions{Level: logLevel}
+
+	switch strings.ToLower(format) {
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("invalid log format %q: expected one of %s, %s", format, FormatText, FormatJSON)
+}
+
+// Returns a logger that adds the resource type and key to all records
+func ForResource(resourceType string, resourceKey string) *slog.Logger {
+	return slog.Default().With(KeyResourceType, resourceType, KeyResourceKey, resourceKey)
+}
+
+// Returns an attribute for the error
+func Err(err error) slog.Attr {
+	return slog.Any(KeyError, err)
+}
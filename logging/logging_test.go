@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLoggerShouldWriteJSONRecordsAtLevel(t *testing.T) {
+	var output bytes.Buffer
+	logger, err := NewLogger(&output, "warn", "json")
+	assert.Nil(t, err)
+
+	logger.Info("discarded")
+	logger.With(KeyResourceType, "ecs-service", KeyResourceKey, "cluster-service").Warn("retrying", KeyPhase, "Fail")
+
+	var record map[string]interface{}
+	assert.Nil(t, json.Unmarshal(output.Bytes(), &record))
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "retrying", record["msg"])
+	assert.Equal(t, "ecs-service", record[KeyResourceType])
+	assert.Equal(t, "cluster-service", record[KeyResourceKey])
+	assert.Equal(t, "Fail", record[KeyPhase])
+}
+
+func TestNewLoggerShouldWriteTextRecords(t *testing.T) {
+	var output bytes.Buffer
+	logger, err := NewLogger(&output, "DEBUG", "text")
+	assert.Nil(t, err)
+
+	logger.Debug("checking", KeyNamespace, "test-ns")
+
+	assert.Contains(t, output.String(), "level=DEBUG msg=checking namespace=test-ns")
+}
+
+func TestNewLoggerShouldRejectInvalidOptions(t *testing.T) {
+	_, err := NewLogger(&bytes.Buffer{}, "verbose", "text")
+	assert.ErrorContains(t, err, "invalid log level")
+
+	_, err = NewLogger(&bytes.Buffer{}, "info", "xml")
+	assert.ErrorContains(t, err, "invalid log format")
+}
@@ -3,11 +3,16 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/mcastellin/aws-fail-az/awsapis"
 	"github.com/mcastellin/aws-fail-az/cmd"
+	"github.com/mcastellin/aws-fail-az/logging"
 	"github.com/spf13/cobra"
 )
 
@@ -18,21 +23,49 @@ var (
 	awsRegion         string
 	awsProfile        string
 	stdin             bool
+	plan              bool
+	duration          time.Duration
+	noRollback        bool
+	parallelism       int
+	synchronized      bool
+	resume            bool
+	stabilityTimeout  time.Duration
+	dryRun            bool
 	namespace         string
 	resourceType      string
 	resourceKey       string
 	resourceStateData string
+	reportFile        string
+	logLevel          string
+	logFormat         string
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "aws-fail-az",
 	Short: "aws-fail-az is an AWS utility to simulate Availability Zone failure",
+	PersistentPreRunE: func(c *cobra.Command, args []string) error {
+		return setupLogging()
+	},
+}
+
+// Configures the default logger from the logging flags. Logs are always written to
+// stderr so that stdout only contains command output
+func setupLogging() error {
+	logger, err := logging.NewLogger(os.Stderr, logLevel, logFormat)
+	if err != nil {
+		return err
+	}
+	if namespace != "" {
+		logger = logger.With(logging.KeyNamespace, namespace)
+	}
+	slog.SetDefault(logger)
+	return nil
 }
 
 var failCmd = &cobra.Command{
 	Use:   "fail [CONFIG_FILE]",
 	Short: "Start AZ failure injection based on the provided configuration from stdin",
-	RunE: func(_ *cobra.Command, args []string) error {
+	RunE: func(c *cobra.Command, args []string) error {
 		if !stdin && len(args) != 1 {
 			return fmt.Errorf("Only one fault configuration file should be provided. Found %d.", len(args))
 		} else if stdin && len(args) > 0 {
@@ -42,7 +75,7 @@ var failCmd = &cobra.Command{
 		if !stdin {
 			configFile = args[0]
 		}
-		provider, err := createProvider()
+		provider, err := createProvider(c.Context())
 		if err != nil {
 			return err
 		}
@@ -51,33 +84,55 @@ var failCmd = &cobra.Command{
 			Namespace:     namespace,
 			ReadFromStdin: stdin,
 			ConfigFile:    configFile,
+			Plan:          plan,
+			Duration:      duration,
+			NoRollback:    noRollback,
+			Parallelism:   parallelism,
+			Synchronized:  synchronized,
+			Resume:        resume,
+			ReportFile:    reportFile,
+
+			StabilityTimeout: stabilityTimeout,
 		}
-		return op.Run()
+		return op.Run(c.Context())
 	},
 }
 
 var recoverCmd = &cobra.Command{
-	Use:   "recover",
+	Use:   "recover [CONFIG_FILE]",
 	Short: "Recover from AZ failure and restore saved resources state",
-	RunE: func(_ *cobra.Command, args []string) error {
-		provider, err := createProvider()
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(c *cobra.Command, args []string) error {
+		configFile := ""
+		if len(args) > 0 {
+			configFile = args[0]
+		}
+		provider, err := createProvider(c.Context())
 		if err != nil {
 			return err
 		}
-		op := &cmd.RecoverCommand{Provider: provider, Namespace: namespace}
-		return op.Run()
+		op := &cmd.RecoverCommand{
+			Provider:     provider,
+			Namespace:    namespace,
+			ResourceType: resourceType,
+			ResourceKey:  resourceKey,
+			DryRun:       dryRun,
+			ConfigFile:   configFile,
+			ReportFile:   reportFile,
+		}
+		return op.Run(c.Context())
 	},
 }
 
 var stateSaveCmd = &cobra.Command{
 	Use:   "state-save",
 	Short: "Store a state object in Dynamodb",
-	RunE: func(_ *cobra.Command, args []string) error {
+	RunE: func(c *cobra.Command, args []string) error {
 		if stdin && len(resourceStateData) > 0 {
 			return fmt.Errorf("State files are not supported when reading from stdin. Found %d.", len(args))
 		}
 
-		provider, err := createProvider()
+		provider, err := createProvider(c.Context())
 		if err != nil {
 			return err
 		}
@@ -89,15 +144,15 @@ var stateSaveCmd = &cobra.Command{
 			ReadFromStdin: stdin,
 			StateData:     resourceStateData,
 		}
-		return op.Run()
+		return op.Run(c.Context())
 	},
 }
 
 var stateReadCmd = &cobra.Command{
 	Use:   "state-read",
 	Short: "Read a state object from Dynamodb",
-	RunE: func(_ *cobra.Command, args []string) error {
-		provider, err := createProvider()
+	RunE: func(c *cobra.Command, args []string) error {
+		provider, err := createProvider(c.Context())
 		if err != nil {
 			return err
 		}
@@ -107,15 +162,15 @@ var stateReadCmd = &cobra.Command{
 			ResourceType: resourceType,
 			ResourceKey:  resourceKey,
 		}
-		return op.Run()
+		return op.Run(c.Context())
 	},
 }
 
 var stateDeleteCmd = &cobra.Command{
 	Use:   "state-delete",
 	Short: "Delete a state object from Dynamodb",
-	RunE: func(_ *cobra.Command, args []string) error {
-		provider, err := createProvider()
+	RunE: func(c *cobra.Command, args []string) error {
+		provider, err := createProvider(c.Context())
 		if err != nil {
 			return err
 		}
@@ -125,7 +180,23 @@ var stateDeleteCmd = &cobra.Command{
 			ResourceType: resourceType,
 			ResourceKey:  resourceKey,
 		}
-		return op.Run()
+		return op.Run(c.Context())
+	},
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List active and past experiments",
+	RunE: func(c *cobra.Command, args []string) error {
+		provider, err := createProvider(c.Context())
+		if err != nil {
+			return err
+		}
+		op := &cmd.StatusCommand{
+			Provider:  provider,
+			Namespace: namespace,
+		}
+		return op.Run(c.Context())
 	},
 }
 
@@ -137,10 +208,10 @@ var versionCmd = &cobra.Command{
 	},
 }
 
-func createProvider() (awsapis.AWSProvider, error) {
+func createProvider(ctx context.Context) (awsapis.AWSProvider, error) {
 	config.WithSharedConfigProfile("devlearnops")
 
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
+	cfg, err := config.LoadDefaultConfig(ctx,
 		config.WithSharedConfigProfile(awsProfile),
 		config.WithRegion(awsRegion))
 	if err != nil {
@@ -152,10 +223,24 @@ func createProvider() (awsapis.AWSProvider, error) {
 
 func main() {
 
-	failCmd.Flags().StringVar(&namespace, "ns", "", "The namespace assigned to this operation. Used to uniquely identify resources state for recovery.")
+	failCmd.Flags().StringVar(&namespace, "ns", "", "The namespace assigned to this operation. Used to uniquely identify resources state for recovery. A new experiment ID is generated when not set.")
 	failCmd.Flags().BoolVar(&stdin, "stdin", false, "Read fail configuration from stdin.")
+	failCmd.Flags().DurationVar(&duration, "duration", 0, "Hold the AZ failure for the given duration (e.g. 30m), then recover resources automatically.")
+	failCmd.Flags().BoolVar(&noRollback, "no-rollback", false, "Do not restore resources already failed when AZ failure injection fails for one of the targets.")
+	failCmd.Flags().DurationVar(&stabilityTimeout, "stability-timeout", cmd.DEFAULT_STABILITY_TIMEOUT, "The maximum time to wait for target resources to reach a stable state before AZ failure.")
+	failCmd.Flags().IntVar(&parallelism, "parallelism", 1, "The maximum number of target resources saved and failed concurrently.")
+	failCmd.Flags().BoolVar(&synchronized, "synchronized", false, "Save the state of all target resources first, then fail all of them at once.")
+	failCmd.Flags().BoolVar(&resume, "resume", false, "Resume an interrupted run: keep the states already saved in the namespace and fail the remaining resources.")
+	failCmd.Flags().StringVar(&reportFile, "report", "", "Write a report of the experiment to a file. The format is selected by file extension: .json, .md (Markdown) or .xml (JUnit).")
+	failCmd.Flags().BoolVar(&plan, "plan", false, "Print the changes that AZ failure would apply to target resources without applying them.")
 
 	recoverCmd.Flags().StringVar(&namespace, "ns", "", "The namespace assigned to this operation. Used to uniquely identify resources state for recovery.")
+	recoverCmd.Flags().StringVar(&resourceType, "type", "", "Only recover resources of this type")
+	recoverCmd.Flags().StringVar(&resourceKey, "key", "", "Only recover the resource with this key")
+	recoverCmd.Flags().StringVar(&reportFile, "report", "", "Write a report of the recovery to a file. The format is selected by file extension: .json, .md (Markdown) or .xml (JUnit).")
+	recoverCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the resource states that would be restored without restoring them.")
+
+	statusCmd.Flags().StringVar(&namespace, "ns", "", "Only show the experiment run in this namespace")
 
 	stateSaveCmd.Flags().StringVar(&namespace, "ns", "", "The namespace assigned to this operation. Used to uniquely identify resources state for recovery.")
 	stateSaveCmd.Flags().StringVar(&resourceType, "type", "", "The type of resource state to store")
@@ -177,8 +262,11 @@ func main() {
 
 	rootCmd.PersistentFlags().StringVar(&awsRegion, "region", "", "The AWS region")
 	rootCmd.PersistentFlags().StringVar(&awsProfile, "profile", "", "The AWS profile")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "The minimum level of log messages: debug, info, warn or error")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", logging.FormatText, "The format of log messages written to stderr: text or json")
 	rootCmd.AddCommand(failCmd)
 	rootCmd.AddCommand(recoverCmd)
+	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(stateSaveCmd)
 	rootCmd.AddCommand(stateReadCmd)
@@ -186,7 +274,19 @@ func main() {
 	rootCmd.SilenceUsage = true
 	rootCmd.SilenceErrors = true
 
-	if err := rootCmd.Execute(); err != nil {
-		log.Fatal(err)
+	// Cancel running operations when the process is interrupted. Once the context is
+	// cancelled the default signal behavior is restored, so a second interrupt
+	// terminates the process immediately
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
+		slog.Error("Command failed", logging.Err(err))
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,194 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+)
+
+// Renders the report as indented JSON
+func (r *Report) RenderJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// Renders the report as a Markdown document
+func (r *Report) RenderMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	outcome := "succeeded"
+	if !r.Succeeded {
+		outcome = "failed"
+	}
+	fmt.Fprintf(&b, "# aws-fail-az %s report\n\n", r.Command)
+	fmt.Fprintf(&b, "* **Outcome:** %s\n", outcome)
+	if r.Namespace != "" {
+		fmt.Fprintf(&b, "* **Namespace:** %s\n", r.Namespace)
+	}
+	if len(r.Azs) > 0 {
+		fmt.Fprintf(&b, "* **Availability zones:** %s\n", strings.Join(r.Azs, ", "))
+	}
+	fmt.Fprintf(&b, "* **Started:** %s\n", r.StartTime.Format(time.RFC3339))
+	fmt.Fprintf(&b, "* **Duration:** %s\n", roundDuration(r.Duration))
+	if r.Error != "" {
+		fmt.Fprintf(&b, "\n```\n%s\n```\n", r.Error)
+	}
+
+	for _, resource := range r.Resources {
+		fmt.Fprintf(&b, "\n## %s `%s`\n\n", resource.ResourceType, resource.ResourceKey)
+		if len(resource.SubnetsBefore) > 0 || len(resource.SubnetsAfter) > 0 {
+			fmt.Fprintf(&b, "* **Subnets before:** %s\n", strings.Join(resource.SubnetsBefore, ", "))
+			fmt.Fprintf(&b, "* **Subnets after:** %s\n", strings.Join(resource.SubnetsAfter, ", "))
+		}
+		if len(resource.Terminations) > 0 {
+			fmt.Fprintf(&b, "* **Stopped/terminated:** %s\n", strings.Join(resource.Terminations, ", "))
+		}
+
+		fmt.Fprintf(&b, "\n| Phase | Started | Duration | Result |\n|-------|---------|----------|--------|\n")
+		for _, phase := range resource.Phases {
+			result := "OK"
+			if phase.Error != "" {
+				result = "ERROR: " + markdownCell(phase.Error)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", phase.Phase,
+				phase.StartTime.Format(time.RFC3339), roundDuration(phase.Duration), result)
+		}
+	}
+
+	if len(r.Probes) > 0 {
+		fmt.Fprintf(&b, "\n## Probes\n\n| Probe | Phase | Samples | Failures | Max latency | Result |\n")
+		fmt.Fprintf(&b, "|-------|-------|---------|----------|-------------|--------|\n")
+		for _, probe := range r.Probes {
+			result := "OK"
+			if probe.Broken {
+				result = "BROKEN: " + markdownCell(probe.LastError)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %d | %d | %s | %s |\n", markdownCell(probe.Probe), probe.Phase,
+				probe.Samples, probe.Failures, roundDuration(probe.MaxLatency), result)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// JUnit XML document elements
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     float64          `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      float64         `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// Renders the report as a JUnit XML document. Every resource is rendered as
+// a test suite with a test case for every phase of the experiment
+func (r *Report) RenderJUnit(w io.Writer) error {
+	suites := junitTestSuites{Name: "aws-fail-az " + r.Command, Time: seconds(r.Duration)}
+
+	for _, resource := range r.Resources {
+		className := fmt.Sprintf("%s.%s", resource.ResourceType, resource.ResourceKey)
+		suite := junitTestSuite{Name: fmt.Sprintf("%s %s", resource.ResourceType, resource.ResourceKey)}
+		for idx, phase := range resource.Phases {
+			if idx == 0 {
+				suite.Timestamp = phase.StartTime.Format(time.RFC3339)
+			}
+			testCase := junitTestCase{Name: phase.Phase, ClassName: className, Time: seconds(phase.Duration)}
+			if phase.Error != "" {
+				testCase.Failure = &junitFailure{Message: phase.Error, Text: phase.Error}
+				suite.Failures++
+			}
+			suite.Time += testCase.Time
+			suite.Cases = append(suite.Cases, testCase)
+		}
+		suite.Tests = len(suite.Cases)
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if len(r.Probes) > 0 {
+		suite := junitTestSuite{Name: "probes"}
+		for _, probe := range r.Probes {
+			testCase := junitTestCase{Name: fmt.Sprintf("%s %s", probe.Probe, probe.Phase), ClassName: "probes"}
+			if probe.Broken {
+				message := fmt.Sprintf("%d of %d samples failed: %s", probe.Failures, probe.Samples, probe.LastError)
+				testCase.Failure = &junitFailure{Message: message, Text: message}
+				suite.Failures++
+			}
+			suite.Cases = append(suite.Cases, testCase)
+		}
+		suite.Tests = len(suite.Cases)
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if !r.Succeeded && r.Error != "" {
+		suites.Suites = append(suites.Suites, junitTestSuite{
+			Name: "experiment", Tests: 1, Failures: 1,
+			Cases: []junitTestCase{{
+				Name: r.Command, ClassName: "experiment",
+				Failure: &junitFailure{Message: r.Error, Text: r.Error},
+			}},
+		})
+	}
+
+	for _, suite := range suites.Suites {
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Rounds the duration to the second, or to the millisecond for durations under a second
+func roundDuration(d domain.Duration) time.Duration {
+	if time.Duration(d) < time.Second {
+		return time.Duration(d).Round(time.Millisecond)
+	}
+	return time.Duration(d).Round(time.Second)
+}
+
+// Returns the duration in seconds
+func seconds(d domain.Duration) float64 {
+	return time.Duration(d).Seconds()
+}
+
+// Escapes text for a Markdown table cell
+func markdownCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", "\\|"), "\n", " ")
+}
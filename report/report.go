@@ -0,0 +1,204 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+)
+
+// Phases of the experiment recorded for every resource
+const (
+	PhaseCheck   = "Check"
+	PhaseSave    = "Save"
+	PhaseFail    = "Fail"
+	PhaseVerify  = "Verify"
+	PhaseRestore = "Restore"
+)
+
+// A report of the operations applied by a command to AWS resources
+type Report struct {
+	Command   string            `json:"command"`
+	Namespace string            `json:"namespace"`
+	Azs       []string          `json:"azs,omitempty"`
+	StartTime time.Time         `json:"startTime"`
+	EndTime   time.Time         `json:"endTime"`
+	Duration  domain.Duration   `json:"duration"`
+	Succeeded bool              `json:"succeeded"`
+	Error     string            `json:"error,omitempty"`
+	Resources []*ResourceReport `json:"resources"`
+	Probes    []ProbeReport     `json:"probes,omitempty"`
+}
+
+// The timeline of the phases of the experiment for a single resource
+type ResourceReport struct {
+	ResourceType  string         `json:"type"`
+	ResourceKey   string         `json:"key"`
+	SubnetsBefore []string       `json:"subnetsBefore,omitempty"`
+	SubnetsAfter  []string       `json:"subnetsAfter,omitempty"`
+	Terminations  []string       `json:"terminations,omitempty"`
+	Phases        []*PhaseReport `json:"phases"`
+}
+
+// A phase of the experiment for a single resource
+type PhaseReport struct {
+	Phase     string          `json:"phase"`
+	StartTime time.Time       `json:"startTime"`
+	Duration  domain.Duration `json:"duration"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// The results of an HTTP probe in a phase of the experiment
+type ProbeReport struct {
+	Probe      string          `json:"probe"`
+	Phase      string          `json:"phase"`
+	Samples    int             `json:"samples"`
+	Failures   int             `json:"failures"`
+	MaxLatency domain.Duration `json:"maxLatency"`
+	LastError  string          `json:"lastError,omitempty"`
+	Broken     bool            `json:"broken"`
+}
+
+// Records the phases of the experiment for every resource.
+// All methods can be called on a nil Recorder, in which case nothing is recorded
+type Recorder struct {
+	mu     sync.Mutex
+	report Report
+}
+
+func NewRecorder(command string, namespace string) *Recorder {
+	return &Recorder{
+		report: Report{
+			Command:   command,
+			Namespace: namespace,
+			StartTime: time.Now(),
+			Resources: []*ResourceReport{},
+		},
+	}
+}
+
+// Sets the availability zones failed in the experiment
+func (r *Recorder) SetAzs(azs []string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.report.Azs = azs
+}
+
+// Records the start of a phase for the resource.
+// Returns a function to call with the phase result when the phase completes
+func (r *Recorder) StartPhase(resourceType string, resourceKey string, phase string) func(error) {
+	if r == nil {
+		return func(error) {}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	phaseReport := &PhaseReport{Phase: phase, StartTime: time.Now()}
+	resource := r.resource(resourceType, resourceKey)
+	resource.Phases = append(resource.Phases, phaseReport)
+
+	return func(err error) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		phaseReport.Duration = domain.Duration(time.Since(phaseReport.StartTime))
+		if err != nil {
+			phaseReport.Error = err.Error()
+		}
+	}
+}
+
+// Records the subnets and terminations planned for the resource
+func (r *Recorder) SetPlan(plan *domain.FailurePlan) {
+	if r == nil || plan == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	resource := r.resource(plan.ResourceType, plan.ResourceKey)
+	resource.SubnetsBefore = plan.CurrentSubnets
+	resource.SubnetsAfter = plan.NewSubnets
+	resource.Terminations = plan.Terminations
+}
+
+// Records the subnets of the resource observed after the AZ failure
+func (r *Recorder) SetSubnetsAfter(resourceType string, resourceKey string, subnets []string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.resource(resourceType, resourceKey).SubnetsAfter = subnets
+}
+
+// Sets the results of the HTTP probes
+func (r *Recorder) SetProbes(probes []ProbeReport) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.report.Probes = probes
+}
+
+// Returns the report for the resource, adding it to the report if not found
+func (r *Recorder) resource(resourceType string, resourceKey string) *ResourceReport {
+	for _, resource := range r.report.Resources {
+		if resource.ResourceType == resourceType && resource.ResourceKey == resourceKey {
+			return resource
+		}
+	}
+	resource := &ResourceReport{ResourceType: resourceType, ResourceKey: resourceKey, Phases: []*PhaseReport{}}
+	r.report.Resources = append(r.report.Resources, resource)
+	return resource
+}
+
+// Completes the report with the command result
+func (r *Recorder) Finish(err error) *Report {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.report.EndTime = time.Now()
+	r.report.Duration = domain.Duration(r.report.EndTime.Sub(r.report.StartTime))
+	r.report.Succeeded = err == nil
+	if err != nil {
+		r.report.Error = err.Error()
+	}
+	return &r.report
+}
+
+// Writes the report to a file. The format is selected from the file extension:
+// Markdown for .md files, JUnit XML for .xml files and JSON for any other extension
+func (r *Report) WriteFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var render func(io.Writer) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		render = r.RenderMarkdown
+	case ".xml":
+		render = r.RenderJUnit
+	default:
+		render = r.RenderJSON
+	}
+	if err := render(file); err != nil {
+		return fmt.Errorf("Failed to write report %s: %v", path, err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,116 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestReport() *Report {
+	recorder := NewRecorder("fail", "test-ns")
+	recorder.SetAzs([]string{"us-east-1a"})
+	recorder.SetPlan(&domain.FailurePlan{
+		ResourceType:   "ecs-service",
+		ResourceKey:    "cluster-service",
+		CurrentSubnets: []string{"subnet-a", "subnet-b"},
+		NewSubnets:     []string{"subnet-b"},
+		Terminations:   []string{"task-1"},
+	})
+	recorder.StartPhase("ecs-service", "cluster-service", PhaseSave)(nil)
+	recorder.StartPhase("ecs-service", "cluster-service", PhaseFail)(nil)
+	recorder.StartPhase("auto-scaling-group", "my-asg", PhaseSave)(nil)
+	recorder.StartPhase("auto-scaling-group", "my-asg", PhaseFail)(fmt.Errorf("access denied"))
+	recorder.SetProbes([]ProbeReport{{Probe: "frontend", Phase: "during", Samples: 10, Failures: 1}})
+
+	return recorder.Finish(fmt.Errorf("AZ failure did not complete"))
+}
+
+func TestRecorderShouldRecordResourceTimeline(t *testing.T) {
+	report := newTestReport()
+
+	assert.False(t, report.Succeeded)
+	assert.Equal(t, []string{"us-east-1a"}, report.Azs)
+	assert.Len(t, report.Resources, 2)
+
+	ecsService := report.Resources[0]
+	assert.Equal(t, []string{"subnet-a", "subnet-b"}, ecsService.SubnetsBefore)
+	assert.Equal(t, []string{"subnet-b"}, ecsService.SubnetsAfter)
+	assert.Equal(t, []string{"task-1"}, ecsService.Terminations)
+	assert.Equal(t, PhaseSave, ecsService.Phases[0].Phase)
+	assert.Equal(t, PhaseFail, ecsService.Phases[1].Phase)
+
+	assert.Equal(t, "access denied", report.Resources[1].Phases[1].Error)
+}
+
+func TestNilRecorderShouldNotRecord(t *testing.T) {
+	var recorder *Recorder
+
+	recorder.SetAzs([]string{"us-east-1a"})
+	recorder.StartPhase("ecs-service", "cluster-service", PhaseCheck)(nil)
+	recorder.SetPlan(&domain.FailurePlan{})
+
+	assert.Nil(t, recorder.Finish(nil))
+}
+
+func TestRenderJSON(t *testing.T) {
+	var output bytes.Buffer
+	err := newTestReport().RenderJSON(&output)
+	assert.Nil(t, err)
+
+	var decoded Report
+	assert.Nil(t, json.Unmarshal(output.Bytes(), &decoded))
+	assert.Equal(t, "fail", decoded.Command)
+	assert.Len(t, decoded.Resources, 2)
+	assert.Equal(t, "access denied", decoded.Resources[1].Phases[1].Error)
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	var output bytes.Buffer
+	err := newTestReport().RenderMarkdown(&output)
+	assert.Nil(t, err)
+
+	assert.Contains(t, output.String(), "* **Outcome:** failed")
+	assert.Contains(t, output.String(), "## ecs-service `cluster-service`")
+	assert.Contains(t, output.String(), "* **Subnets after:** subnet-b")
+	assert.Contains(t, output.String(), "ERROR: access denied |")
+	assert.Contains(t, output.String(), "| frontend | during | 10 | 1 |")
+}
+
+func TestRenderJUnit(t *testing.T) {
+	var output bytes.Buffer
+	err := newTestReport().RenderJUnit(&output)
+	assert.Nil(t, err)
+
+	var decoded junitTestSuites
+	assert.Nil(t, xml.Unmarshal(output.Bytes(), &decoded))
+	// 4 resource phases, 1 probe and the experiment result
+	assert.Equal(t, 6, decoded.Tests)
+	assert.Equal(t, 2, decoded.Failures)
+	assert.Equal(t, "ecs-service cluster-service", decoded.Suites[0].Name)
+	assert.Equal(t, "access denied", decoded.Suites[1].Cases[1].Failure.Message)
+}
+
+func TestWriteFileShouldSelectFormatFromExtension(t *testing.T) {
+	dir := t.TempDir()
+	report := newTestReport()
+
+	for file, prefix := range map[string]string{
+		"report.json": "{",
+		"report.md":   "# aws-fail-az fail report",
+		"report.xml":  "<?xml",
+	} {
+		path := filepath.Join(dir, file)
+		assert.Nil(t, report.WriteFile(path))
+
+		content, err := os.ReadFile(path)
+		assert.Nil(t, err)
+		assert.True(t, bytes.HasPrefix(content, []byte(prefix)), "unexpected content for %s", file)
+	}
+}
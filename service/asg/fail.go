@@ -4,14 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/mcastellin/aws-fail-az/awsapis"
 	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
 	"github.com/mcastellin/aws-fail-az/service/awsutils"
 	"github.com/mcastellin/aws-fail-az/state"
 	"golang.org/x/exp/slices"
@@ -29,11 +31,22 @@ type AutoScalingGroup struct {
 	stateSubnets []string
 }
 
-func (asg *AutoScalingGroup) Check() (bool, error) {
+func (asg *AutoScalingGroup) ResourceType() string {
+	return domain.ResourceTypeAutoScalingGroup
+}
+
+func (asg *AutoScalingGroup) ResourceKey() string {
+	return asg.AutoScalingGroupName
+}
+
+func (asg *AutoScalingGroup) logger() *slog.Logger {
+	return logging.ForResource(asg.ResourceType(), asg.ResourceKey())
+}
+
+func (asg *AutoScalingGroup) Check(ctx context.Context) (bool, error) {
 	isValid := true
 
-	log.Printf("%s name=%s: checking resource state before failure simulation",
-		domain.ResourceTypeAutoScalingGroup, asg.AutoScalingGroupName)
+	asg.logger().Debug("Checking resource state before failure simulation")
 
 	api := asg.Provider.NewAutoScalingApi()
 
@@ -41,9 +54,9 @@ func (asg *AutoScalingGroup) Check() (bool, error) {
 		AutoScalingGroupNames: []string{asg.AutoScalingGroupName},
 	}
 
-	describeAsgOutput, err := api.DescribeAutoScalingGroups(context.TODO(), input)
+	describeAsgOutput, err := api.DescribeAutoScalingGroups(ctx, input)
 	if err != nil {
-		return false, err
+		return false, awsutils.ClassifyError(err)
 	}
 
 	asgObj := describeAsgOutput.AutoScalingGroups[0]
@@ -62,7 +75,30 @@ func (asg *AutoScalingGroup) Check() (bool, error) {
 	return isValid, nil
 }
 
-func (asg *AutoScalingGroup) Save(stateManager state.StateManager) error {
+func (asg *AutoScalingGroup) Save(ctx context.Context, stateManager state.StateManager) error {
+
+	state, err := asg.CurrentState(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		asg.logger().Error("Error while marshalling autoscaling group state", logging.Err(err))
+		return err
+	}
+
+	err = stateManager.Save(ctx, domain.ResourceTypeAutoScalingGroup, state.AutoScalingGroupName, data)
+	if err != nil {
+		return awsutils.ClassifyError(err)
+	}
+	asg.stateSubnets = state.Subnets
+
+	return nil
+}
+
+// Returns the current configuration of the autoscaling group to save in state
+func (asg *AutoScalingGroup) CurrentState(ctx context.Context) (*AutoScalingGroupState, error) {
 
 	api := asg.Provider.NewAutoScalingApi()
 
@@ -70,34 +106,81 @@ func (asg *AutoScalingGroup) Save(stateManager state.StateManager) error {
 		AutoScalingGroupNames: []string{asg.AutoScalingGroupName},
 	}
 
-	describeAsgOutput, err := api.DescribeAutoScalingGroups(context.TODO(), input)
+	describeAsgOutput, err := api.DescribeAutoScalingGroups(ctx, input)
 	if err != nil {
-		return err
+		return nil, awsutils.ClassifyError(err)
 	}
 
 	asgObj := describeAsgOutput.AutoScalingGroups[0]
-	subnets := strings.Split(*asgObj.VPCZoneIdentifier, ",")
-
-	state := &AutoScalingGroupState{
+	return &AutoScalingGroupState{
 		AutoScalingGroupName: *asgObj.AutoScalingGroupName,
-		Subnets:              subnets,
-	}
+		Subnets:              strings.Split(*asgObj.VPCZoneIdentifier, ","),
+	}, nil
+}
 
-	data, err := json.Marshal(state)
+func (asg *AutoScalingGroup) Fail(ctx context.Context, azs []string) error {
+	apply, err := asg.PrepareFail(ctx, azs)
 	if err != nil {
-		log.Println("Error while marshalling autoscaling group state")
 		return err
 	}
+	return apply(ctx)
+}
+
+// Describes the autoscaling group and returns the function that removes the subnets in the
+// failed AZs and terminates the instances running in them
+func (asg *AutoScalingGroup) PrepareFail(ctx context.Context, azs []string) (func(context.Context) error, error) {
+	ec2Api := asg.Provider.NewEc2Api()
+	api := asg.Provider.NewAutoScalingApi()
+
+	input := &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []string{asg.AutoScalingGroupName},
+	}
 
-	err = stateManager.Save(domain.ResourceTypeAutoScalingGroup, *asgObj.AutoScalingGroupName, data)
+	describeAsgOutput, err := api.DescribeAutoScalingGroups(ctx, input)
 	if err != nil {
-		return err
+		return nil, awsutils.ClassifyError(err)
 	}
 
-	return nil
+	asgObj := describeAsgOutput.AutoScalingGroups[0]
+	subnets := strings.Split(*asgObj.VPCZoneIdentifier, ",")
+
+	newSubnets, err := awsutils.FilterSubnetsNotInAzs(ctx, ec2Api, subnets, azs)
+	if err != nil {
+		return nil, awsutils.ClassifyError(err)
+	}
+
+	instancesToTerminate := instancesInAzs(asgObj, azs)
+
+	return func(ctx context.Context) error {
+		asg.logger().Info("Failing AZs for autoscaling group", "azs", azs)
+
+		updateAsgInput := &autoscaling.UpdateAutoScalingGroupInput{
+			AutoScalingGroupName: aws.String(asg.AutoScalingGroupName),
+			VPCZoneIdentifier:    aws.String(strings.Join(newSubnets, ",")),
+		}
+
+		_, err := api.UpdateAutoScalingGroup(ctx, updateAsgInput)
+		if err != nil {
+			return awsutils.ClassifyError(err)
+		}
+
+		if len(instancesToTerminate) > 0 {
+			asg.logger().Info("Terminating instances that belonged to removed subnets", "instances", instancesToTerminate)
+
+			terminateInstancesInput := &ec2.TerminateInstancesInput{
+				InstanceIds: instancesToTerminate,
+			}
+			_, err = ec2Api.TerminateInstances(ctx, terminateInstancesInput)
+			if err != nil {
+				return awsutils.ClassifyError(err)
+			}
+		}
+
+		return nil
+	}, nil
 }
 
-func (asg *AutoScalingGroup) Fail(azs []string) error {
+func (asg *AutoScalingGroup) Plan(ctx context.Context, azs []string) (*domain.FailurePlan, error) {
 	ec2Api := asg.Provider.NewEc2Api()
 	api := asg.Provider.NewAutoScalingApi()
 
@@ -105,56 +188,63 @@ func (asg *AutoScalingGroup) Fail(azs []string) error {
 		AutoScalingGroupNames: []string{asg.AutoScalingGroupName},
 	}
 
-	describeAsgOutput, err := api.DescribeAutoScalingGroups(context.TODO(), input)
+	describeAsgOutput, err := api.DescribeAutoScalingGroups(ctx, input)
 	if err != nil {
-		return err
+		return nil, awsutils.ClassifyError(err)
 	}
 
 	asgObj := describeAsgOutput.AutoScalingGroups[0]
 	subnets := strings.Split(*asgObj.VPCZoneIdentifier, ",")
 
-	newSubnets, err := awsutils.FilterSubnetsNotInAzs(ec2Api, subnets, azs)
+	newSubnets, err := awsutils.FilterSubnetsNotInAzs(ctx, ec2Api, subnets, azs)
 	if err != nil {
-		return err
+		return nil, awsutils.ClassifyError(err)
 	}
 
-	log.Printf("%s name=%s: failing AZs %s for autoscaling group",
-		domain.ResourceTypeAutoScalingGroup, asg.AutoScalingGroupName, azs)
+	return &domain.FailurePlan{
+		ResourceType:   domain.ResourceTypeAutoScalingGroup,
+		ResourceKey:    asg.ResourceKey(),
+		CurrentSubnets: subnets,
+		NewSubnets:     newSubnets,
+		Terminations:   instancesInAzs(asgObj, azs),
+	}, nil
+}
 
-	updateAsgInput := &autoscaling.UpdateAutoScalingGroupInput{
-		AutoScalingGroupName: aws.String(asg.AutoScalingGroupName),
-		VPCZoneIdentifier:    aws.String(strings.Join(newSubnets, ",")),
+// Verifies the autoscaling group has replaced the instances terminated in the failed AZs
+func (asg *AutoScalingGroup) VerifySteadyState(ctx context.Context, azs []string) (bool, error) {
+	api := asg.Provider.NewAutoScalingApi()
+
+	input := &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []string{asg.AutoScalingGroupName},
 	}
 
-	_, err = api.UpdateAutoScalingGroup(context.TODO(), updateAsgInput)
+	describeAsgOutput, err := api.DescribeAutoScalingGroups(ctx, input)
 	if err != nil {
-		return err
+		return false, awsutils.ClassifyError(err)
 	}
 
-	instancesToTerminate := []string{}
-	for _, instance := range asgObj.Instances {
-		if slices.Contains(azs, *instance.AvailabilityZone) {
-			instancesToTerminate = append(instancesToTerminate, *instance.InstanceId)
-		}
+	asgObj := describeAsgOutput.AutoScalingGroups[0]
+	if instances := instancesInAzs(asgObj, azs); len(instances) > 0 {
+		return false, fmt.Errorf("Instances %s of AutoScalingGroup %s are still running in failed AZs.",
+			instances, asg.AutoScalingGroupName)
 	}
-	if len(instancesToTerminate) > 0 {
-		log.Printf("%s name=%s: terminating instances %s that belonged to removed subnets",
-			domain.ResourceTypeAutoScalingGroup, asg.AutoScalingGroupName, instancesToTerminate)
 
-		terminateInstancesInput := &ec2.TerminateInstancesInput{
-			InstanceIds: instancesToTerminate,
-		}
-		_, err = ec2Api.TerminateInstances(context.TODO(), terminateInstancesInput)
-		if err != nil {
-			return err
+	inService := 0
+	for _, instance := range asgObj.Instances {
+		if instance.LifecycleState == types.LifecycleStateInService && *instance.HealthStatus == "Healthy" {
+			inService++
 		}
 	}
+	if int(*asgObj.DesiredCapacity) > inService {
+		return false, fmt.Errorf("Desired instance capacity for AutoScalingGroup %s is not met. Desired %d, in service %d.",
+			asg.AutoScalingGroupName, *asgObj.DesiredCapacity, inService)
+	}
 
-	return nil
+	return true, nil
 }
-func (asg *AutoScalingGroup) Restore() error {
-	log.Printf("%s name=%s: restoring AZs for autoscaling group",
-		domain.ResourceTypeAutoScalingGroup, asg.AutoScalingGroupName)
+
+func (asg *AutoScalingGroup) Restore(ctx context.Context) error {
+	asg.logger().Info("Restoring AZs for autoscaling group")
 
 	api := asg.Provider.NewAutoScalingApi()
 	updateAsgInput := &autoscaling.UpdateAutoScalingGroupInput{
@@ -162,9 +252,41 @@ func (asg *AutoScalingGroup) Restore() error {
 		VPCZoneIdentifier:    aws.String(strings.Join(asg.stateSubnets, ",")),
 	}
 
-	_, err := api.UpdateAutoScalingGroup(context.TODO(), updateAsgInput)
+	_, err := api.UpdateAutoScalingGroup(ctx, updateAsgInput)
 	if err != nil {
-		return err
+		return awsutils.ClassifyError(err)
 	}
 	return nil
 }
+
+// Verifies the autoscaling group subnets match the subnets saved in state
+func (asg *AutoScalingGroup) VerifyRestored(ctx context.Context) (bool, error) {
+	api := asg.Provider.NewAutoScalingApi()
+
+	input := &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []string{asg.AutoScalingGroupName},
+	}
+
+	describeAsgOutput, err := api.DescribeAutoScalingGroups(ctx, input)
+	if err != nil {
+		return false, awsutils.ClassifyError(err)
+	}
+
+	subnets := strings.Split(*describeAsgOutput.AutoScalingGroups[0].VPCZoneIdentifier, ",")
+	if !awsutils.SameSubnets(subnets, asg.stateSubnets) {
+		return false, fmt.Errorf("AutoScalingGroup %s subnets %s do not match saved subnets %s",
+			asg.AutoScalingGroupName, subnets, asg.stateSubnets)
+	}
+	return true, nil
+}
+
+// Returns the IDs of the autoscaling group instances running in one of the `azs`
+func instancesInAzs(asgObj types.AutoScalingGroup, azs []string) []string {
+	instanceIds := []string{}
+	for _, instance := range asgObj.Instances {
+		if slices.Contains(azs, *instance.AvailabilityZone) {
+			instanceIds = append(instanceIds, *instance.InstanceId)
+		}
+	}
+	return instanceIds
+}
@@ -0,0 +1,81 @@
+package asg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/mcastellin/aws-fail-az/awsapis_mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestVerifySteadyStateShouldWaitForInstancesInFailedAzs(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockAutoScalingApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewAutoScalingApi().AnyTimes().Return(mockApi)
+
+	mockApi.EXPECT().DescribeAutoScalingGroups(gomock.Any(), gomock.Any()).Times(1).
+		Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+			AutoScalingGroups: []types.AutoScalingGroup{{
+				AutoScalingGroupName: aws.String("test-asg"),
+				DesiredCapacity:      aws.Int32(2),
+				Instances: []types.Instance{
+					newInstance("i-1111", "us-east-1a", types.LifecycleStateTerminating),
+					newInstance("i-2222", "us-east-1b", types.LifecycleStateInService),
+					newInstance("i-3333", "us-east-1b", types.LifecycleStateInService),
+				},
+			}},
+		}, nil)
+
+	result, err := (&AutoScalingGroup{
+		Provider:             mockProvider,
+		AutoScalingGroupName: "test-asg",
+	}).VerifySteadyState(context.TODO(), []string{"us-east-1a"})
+
+	assert.NotNil(t, err)
+	assert.False(t, result)
+}
+
+func TestVerifySteadyStateShouldPassWhenCapacityReplaced(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockAutoScalingApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewAutoScalingApi().AnyTimes().Return(mockApi)
+
+	mockApi.EXPECT().DescribeAutoScalingGroups(gomock.Any(), gomock.Any()).Times(1).
+		Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+			AutoScalingGroups: []types.AutoScalingGroup{{
+				AutoScalingGroupName: aws.String("test-asg"),
+				DesiredCapacity:      aws.Int32(2),
+				Instances: []types.Instance{
+					newInstance("i-2222", "us-east-1b", types.LifecycleStateInService),
+					newInstance("i-3333", "us-east-1c", types.LifecycleStateInService),
+				},
+			}},
+		}, nil)
+
+	result, err := (&AutoScalingGroup{
+		Provider:             mockProvider,
+		AutoScalingGroupName: "test-asg",
+	}).VerifySteadyState(context.TODO(), []string{"us-east-1a"})
+
+	assert.Nil(t, err)
+	assert.True(t, result)
+}
+
+func newInstance(id string, az string, lifecycleState types.LifecycleState) types.Instance {
+	return types.Instance{
+		InstanceId:       aws.String(id),
+		AvailabilityZone: aws.String(az),
+		LifecycleState:   lifecycleState,
+		HealthStatus:     aws.String("Healthy"),
+	}
+}
@@ -11,22 +11,26 @@ import (
 	"github.com/mcastellin/aws-fail-az/service/awsutils"
 )
 
-func RestoreAutoScalingGroupsFromState(stateData []byte, provider awsapis.AWSProvider) error {
+func NewAutoScalingGroupFromState(stateData []byte, provider awsapis.AWSProvider) (domain.ConsistentStateResource, error) {
 	var state AutoScalingGroupState
 	err := json.Unmarshal(stateData, &state)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	resource := AutoScalingGroup{
+	return NewAutoScalingGroupWithState(state, provider), nil
+}
+
+// Creates an autoscaling group that restores the subnets of the saved state
+func NewAutoScalingGroupWithState(state AutoScalingGroupState, provider awsapis.AWSProvider) *AutoScalingGroup {
+	return &AutoScalingGroup{
 		Provider:             provider,
 		AutoScalingGroupName: state.AutoScalingGroupName,
 		stateSubnets:         state.Subnets,
 	}
-	return resource.Restore()
 }
 
-func NewAutoScalingGroupFaultFromConfig(selector domain.TargetSelector, provider awsapis.AWSProvider) ([]domain.ConsistentStateResource, error) {
+func NewAutoScalingGroupFaultFromConfig(ctx context.Context, selector domain.TargetSelector, provider awsapis.AWSProvider) ([]domain.ConsistentStateResource, error) {
 
 	if selector.Type != domain.ResourceTypeAutoScalingGroup {
 		return nil, fmt.Errorf("Unable to create AutoScalingGroup object from selector of type %s.", selector.Type)
@@ -50,7 +54,7 @@ func NewAutoScalingGroupFaultFromConfig(selector domain.TargetSelector, provider
 
 	} else if len(selector.Tags) > 0 {
 		api := provider.NewAutoScalingApi()
-		asgNames, err = filterAutoScalingGroupsByTags(api, selector.Tags)
+		asgNames, err = filterAutoScalingGroupsByTags(ctx, api, selector.Tags)
 		if err != nil {
 			return nil, err
 		}
@@ -68,12 +72,12 @@ func NewAutoScalingGroupFaultFromConfig(selector domain.TargetSelector, provider
 	return objs, nil
 }
 
-func filterAutoScalingGroupsByTags(api awsapis.AutoScalingApi, tags []domain.AWSTag) ([]string, error) {
+func filterAutoScalingGroupsByTags(ctx context.Context, api awsapis.AutoScalingApi, tags []domain.AWSTag) ([]string, error) {
 	groupNames := []string{}
 
 	paginator := api.NewDescribeAutoScalingGroupsPaginator(&autoscaling.DescribeAutoScalingGroupsInput{})
 	for paginator.HasMorePages() {
-		response, err := paginator.NextPage(context.TODO())
+		response, err := paginator.NextPage(ctx)
 		if err != nil {
 			return nil, err
 		}
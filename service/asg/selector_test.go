@@ -43,7 +43,7 @@ func TestFilterAsgByTagsShouldNotMatchResults(t *testing.T) {
 		Value: "live",
 	}}
 
-	result, err := filterAutoScalingGroupsByTags(mockApi, filter)
+	result, err := filterAutoScalingGroupsByTags(context.TODO(), mockApi, filter)
 
 	assert.Len(t, result, 0)
 	assert.Nil(t, err)
@@ -85,7 +85,7 @@ func TestFilterAsgByTagsShouldMatchResultsInAllPages(t *testing.T) {
 		Value: "myapp",
 	}}
 
-	result, err := filterAutoScalingGroupsByTags(mockApi, filter)
+	result, err := filterAutoScalingGroupsByTags(context.TODO(), mockApi, filter)
 	t.Log(result)
 
 	assert.Equal(t, []string{"asg-name-test", "asg-name-live"}, result)
@@ -0,0 +1,83 @@
+package awsutils
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"golang.org/x/exp/slices"
+)
+
+// AWS API error codes for operations that are expected to succeed if retried,
+// typically because the resources involved are not yet consistent
+var temporaryErrorCodes = []string{
+	"InvalidDBClusterStateFault",
+	"InvalidDBInstanceState",
+	"InvalidReplicationGroupState",
+	"InvalidSubnet",
+	"InvalidSubnetID.NotFound",
+	"InvalidInstanceID.NotFound",
+	"ResourceInUse",
+	"ResourceInUseFault",
+	"ResourceContentionFault",
+	"ResourceConflictException",
+	"ScalingActivityInProgress",
+	"ScalingActivityInProgressFault",
+	"ServerException",
+	"ServiceUnavailableException",
+}
+
+// AWS API error codes that prevent any further operation from succeeding
+var interruptErrorCodes = []string{
+	"AccessDenied",
+	"AccessDeniedException",
+	"AuthFailure",
+	"ExpiredToken",
+	"ExpiredTokenException",
+	"InvalidClientTokenId",
+	"SignatureDoesNotMatch",
+	"UnauthorizedOperation",
+	"UnrecognizedClientException",
+}
+
+// Classifies errors returned by AWS API calls.
+// Throttling, transient and eventual consistency errors are returned as temporary
+// ActivityFailedError. Authorization errors and cancelled contexts are returned as
+// InterruptExecutionError. All other errors, including ServiceNotActiveException
+// for inactive ECS services, are returned as non-temporary ActivityFailedError
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var activityErr domain.ActivityFailedError
+	var interruptErr domain.InterruptExecutionError
+	if errors.As(err, &activityErr) || errors.As(err, &interruptErr) {
+		return err
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return domain.InterruptExecutionError{Wrap: err}
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		if slices.Contains(interruptErrorCodes, apiErr.ErrorCode()) {
+			return domain.InterruptExecutionError{Wrap: err}
+		}
+		if slices.Contains(temporaryErrorCodes, apiErr.ErrorCode()) {
+			return domain.ActivityFailedError{Wrap: err, Temporary: true}
+		}
+	}
+
+	for _, retryable := range retry.DefaultRetryables {
+		if retryable.IsErrorRetryable(err) == aws.TrueTernary {
+			return domain.ActivityFailedError{Wrap: err, Temporary: true}
+		}
+	}
+
+	return domain.ActivityFailedError{Wrap: err, Temporary: false}
+}
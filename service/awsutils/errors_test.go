@@ -0,0 +1,58 @@
+package awsutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyErrorShouldReturnTemporaryErrors(t *testing.T) {
+	errs := []error{
+		&smithy.GenericAPIError{Code: "InvalidSubnet", Message: "subnet not found"},
+		&smithy.GenericAPIError{Code: "ThrottlingException", Message: "rate exceeded"},
+	}
+
+	for _, err := range errs {
+		var activityErr domain.ActivityFailedError
+		assert.True(t, errors.As(ClassifyError(err), &activityErr))
+		assert.True(t, activityErr.IsTemporary())
+		assert.ErrorIs(t, activityErr, err)
+	}
+}
+
+func TestClassifyErrorShouldReturnPermanentErrors(t *testing.T) {
+	errs := []error{
+		&smithy.GenericAPIError{Code: "ServiceNotActiveException", Message: "service not active"},
+		fmt.Errorf("unknown error"),
+	}
+
+	for _, err := range errs {
+		var activityErr domain.ActivityFailedError
+		assert.True(t, errors.As(ClassifyError(err), &activityErr))
+		assert.False(t, activityErr.IsTemporary())
+	}
+}
+
+func TestClassifyErrorShouldReturnInterruptErrors(t *testing.T) {
+	errs := []error{
+		&smithy.GenericAPIError{Code: "UnauthorizedOperation", Message: "not authorized"},
+		context.Canceled,
+	}
+
+	for _, err := range errs {
+		var interruptErr domain.InterruptExecutionError
+		assert.True(t, errors.As(ClassifyError(err), &interruptErr))
+	}
+}
+
+func TestClassifyErrorShouldNotWrapClassifiedErrors(t *testing.T) {
+	err := domain.ActivityFailedError{Wrap: fmt.Errorf("already classified"), Temporary: true}
+
+	assert.Equal(t, err, ClassifyError(err))
+	assert.Nil(t, ClassifyError(nil))
+}
@@ -45,11 +45,11 @@ func TokenizeResourceFilter(filter string, validKeys []string) (map[string]strin
 // Filter a list of subnets by Availability Zone
 // Returns all subnets in the `subnetIds` list that are not attached to one of the availability
 // zones in the `azs` parameter
-func FilterSubnetsNotInAzs(api awsapis.Ec2Api, subnetIds []string, azs []string) ([]string, error) {
+func FilterSubnetsNotInAzs(ctx context.Context, api awsapis.Ec2Api, subnetIds []string, azs []string) ([]string, error) {
 	input := &ec2.DescribeSubnetsInput{
 		SubnetIds: subnetIds,
 	}
-	describeSubnetsOutput, err := api.DescribeSubnets(context.TODO(), input)
+	describeSubnetsOutput, err := api.DescribeSubnets(ctx, input)
 	if err != nil {
 		return []string{}, err
 	}
@@ -63,3 +63,64 @@ func FilterSubnetsNotInAzs(api awsapis.Ec2Api, subnetIds []string, azs []string)
 
 	return newSubnets, nil
 }
+
+// Resolves the availability zones to their names in the current account and region.
+// Each item in `azs` can be either an AZ name (i.e. us-east-1a) or an AZ ID (i.e. use1-az1).
+// Returns an error if an AZ does not exist in the region
+func ResolveAzNames(ctx context.Context, api awsapis.Ec2Api, azs []string) ([]string, error) {
+	output, err := api.DescribeAvailabilityZones(ctx, &ec2.DescribeAvailabilityZonesInput{})
+	if err != nil {
+		return []string{}, err
+	}
+
+	names := map[string]string{}
+	for _, zone := range output.AvailabilityZones {
+		names[*zone.ZoneName] = *zone.ZoneName
+		names[*zone.ZoneId] = *zone.ZoneName
+	}
+
+	resolved := []string{}
+	for _, az := range azs {
+		name, ok := names[az]
+		if !ok {
+			return []string{}, fmt.Errorf("Availability zone %s not found in the current region", az)
+		}
+		if !slices.Contains(resolved, name) {
+			resolved = append(resolved, name)
+		}
+	}
+	return resolved, nil
+}
+
+// Returns the sorted list of the availability zones of the subnets
+func SubnetsAzs(ctx context.Context, api awsapis.Ec2Api, subnetIds []string) ([]string, error) {
+	input := &ec2.DescribeSubnetsInput{
+		SubnetIds: subnetIds,
+	}
+	describeSubnetsOutput, err := api.DescribeSubnets(ctx, input)
+	if err != nil {
+		return []string{}, err
+	}
+
+	azs := []string{}
+	for _, subnet := range describeSubnetsOutput.Subnets {
+		if !slices.Contains(azs, *subnet.AvailabilityZone) {
+			azs = append(azs, *subnet.AvailabilityZone)
+		}
+	}
+	slices.Sort(azs)
+	return azs, nil
+}
+
+// Returns true if both lists contain the same subnets, regardless of their order
+func SameSubnets(subnetIds []string, otherSubnetIds []string) bool {
+	if len(subnetIds) != len(otherSubnetIds) {
+		return false
+	}
+	for _, subnet := range subnetIds {
+		if !slices.Contains(otherSubnetIds, subnet) {
+			return false
+		}
+	}
+	return true
+}
@@ -37,13 +37,68 @@ func TestFilterSubnetsNotInAzs(t *testing.T) {
 			return output, nil
 		})
 
-	newSubnets, err := FilterSubnetsNotInAzs(mockApi, []string{"s-1234", "s-0000"}, []string{"us-east-1b"})
+	newSubnets, err := FilterSubnetsNotInAzs(context.TODO(), mockApi, []string{"s-1234", "s-0000"}, []string{"us-east-1b"})
 
 	assert.Nil(t, err)
 	assert.Equal(t, []string{"s-0000"}, newSubnets, "Should have returned only subnet not in failing az")
 
 }
 
+func TestSubnetsAzsShouldReturnSortedUniqueAzs(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+	mockApi := awsapis_mocks.NewMockEc2Api(ctrl)
+
+	mockApi.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []types.Subnet{
+				{SubnetId: aws.String("s-1"), AvailabilityZone: aws.String("us-east-1c")},
+				{SubnetId: aws.String("s-2"), AvailabilityZone: aws.String("us-east-1a")},
+				{SubnetId: aws.String("s-3"), AvailabilityZone: aws.String("us-east-1c")},
+			},
+		}, nil)
+
+	azs, err := SubnetsAzs(context.TODO(), mockApi, []string{"s-1", "s-2", "s-3"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"us-east-1a", "us-east-1c"}, azs)
+}
+
+func mockAvailabilityZones(mockApi *awsapis_mocks.MockEc2Api) {
+	mockApi.EXPECT().DescribeAvailabilityZones(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(&ec2.DescribeAvailabilityZonesOutput{
+			AvailabilityZones: []types.AvailabilityZone{
+				{ZoneName: aws.String("us-east-1a"), ZoneId: aws.String("use1-az4")},
+				{ZoneName: aws.String("us-east-1b"), ZoneId: aws.String("use1-az1")},
+			},
+		}, nil)
+}
+
+func TestResolveAzNamesShouldTranslateAzIds(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+	mockApi := awsapis_mocks.NewMockEc2Api(ctrl)
+	mockAvailabilityZones(mockApi)
+
+	azs, err := ResolveAzNames(context.TODO(), mockApi, []string{"use1-az1", "us-east-1a", "us-east-1b"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"us-east-1b", "us-east-1a"}, azs)
+}
+
+func TestResolveAzNamesShouldRefuseUnknownAzs(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+	mockApi := awsapis_mocks.NewMockEc2Api(ctrl)
+	mockAvailabilityZones(mockApi)
+
+	_, err := ResolveAzNames(context.TODO(), mockApi, []string{"us-east-1z"})
+
+	assert.NotNil(t, err)
+}
+
 func TestTokenizeResourceFilter(t *testing.T) {
 	attributes, err := TokenizeResourceFilter("cluster=test;service=test-service", []string{"cluster", "service"})
 
@@ -73,3 +128,9 @@ func TestTokenizeResourceFilterShouldRefuseInvalidKeys(t *testing.T) {
 
 	assert.NotNil(t, err)
 }
+
+func TestSameSubnetsShouldIgnoreOrder(t *testing.T) {
+	assert.True(t, SameSubnets([]string{"s-1111", "s-2222"}, []string{"s-2222", "s-1111"}))
+	assert.False(t, SameSubnets([]string{"s-1111", "s-2222"}, []string{"s-1111"}))
+	assert.False(t, SameSubnets([]string{"s-1111", "s-2222"}, []string{"s-1111", "s-3333"}))
+}
@@ -0,0 +1,47 @@
+package awsutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+)
+
+// The default maximum time to wait for asynchronous AWS operations to complete
+const DEFAULT_WAIT_TIMEOUT = 30 * time.Minute
+
+// The time between consecutive checks of asynchronous AWS operations
+var WaitInterval = 10 * time.Second
+
+// Polls `conditionFn` every WaitInterval until it returns true.
+// Temporary errors are polled again, any other error stops waiting and is returned.
+// Returns an error if the condition is not met within `timeout`, or an
+// InterruptExecutionError if the context is cancelled while waiting
+func WaitUntil(ctx context.Context, timeout time.Duration, condition string, conditionFn func() (bool, error)) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		isValid, err := conditionFn()
+		var activityErr domain.ActivityFailedError
+		if err != nil && !(errors.As(err, &activityErr) && activityErr.IsTemporary()) {
+			return err
+		}
+		if isValid && err == nil {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			if err != nil {
+				return fmt.Errorf("Timed out after %s waiting for %s: %w", timeout, condition, err)
+			}
+			return fmt.Errorf("Timed out after %s waiting for %s", timeout, condition)
+		}
+
+		select {
+		case <-ctx.Done():
+			return domain.InterruptExecutionError{Wrap: context.Cause(ctx)}
+		case <-time.After(WaitInterval):
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package awsutils
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitUntilShouldPollUntilConditionIsMet(t *testing.T) {
+	WaitInterval = time.Millisecond
+
+	checks := 0
+	err := WaitUntil(context.TODO(), time.Second, "condition", func() (bool, error) {
+		checks++
+		if checks == 1 {
+			return false, domain.ActivityFailedError{Wrap: fmt.Errorf("throttled"), Temporary: true}
+		}
+		return checks == 3, nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, checks)
+}
+
+func TestWaitUntilShouldStopOnPermanentErrors(t *testing.T) {
+	WaitInterval = time.Millisecond
+
+	checks := 0
+	err := WaitUntil(context.TODO(), time.Second, "condition", func() (bool, error) {
+		checks++
+		return false, fmt.Errorf("not found")
+	})
+
+	assert.ErrorContains(t, err, "not found")
+	assert.Equal(t, 1, checks)
+}
+
+func TestWaitUntilShouldFailAfterTimeout(t *testing.T) {
+	WaitInterval = time.Millisecond
+
+	err := WaitUntil(context.TODO(), 10*time.Millisecond, "condition", func() (bool, error) {
+		return false, nil
+	})
+
+	assert.ErrorContains(t, err, "Timed out")
+}
@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
@@ -13,6 +13,7 @@ import (
 	ecsTypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
 	"github.com/mcastellin/aws-fail-az/awsapis"
 	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
 	"github.com/mcastellin/aws-fail-az/service/awsutils"
 	"github.com/mcastellin/aws-fail-az/state"
 	"golang.org/x/exp/slices"
@@ -35,15 +36,27 @@ type ECSServiceState struct {
 	Subnets     []string `json:"subnets"`
 }
 
-func (svc *ECSService) Check() (bool, error) {
+func (svc *ECSService) ResourceType() string {
+	return domain.ResourceTypeEcsService
+}
+
+func (svc *ECSService) ResourceKey() string {
+	return fmt.Sprintf("%s-%s", svc.ClusterArn, svc.ServiceName)
+}
+
+func (svc *ECSService) logger() *slog.Logger {
+	return logging.ForResource(svc.ResourceType(), svc.ResourceKey())
+}
+
+func (svc *ECSService) Check(ctx context.Context) (bool, error) {
 	isValid := true
 
-	log.Printf("%s cluster=%s,name=%s: checking resource state before failure simulation",
-		domain.ResourceTypeEcsService, svc.ClusterArn, svc.ServiceName)
+	svc.logger().Debug("Checking resource state before failure simulation")
 
 	api := svc.Provider.NewEcsApi()
 
-	result, err := serviceStable(api, svc.ClusterArn, svc.ServiceName)
+	// AWS errors are already classified, other errors describe what the service is waiting for
+	result, err := serviceStable(ctx, api, svc.ClusterArn, svc.ServiceName)
 	if err != nil {
 		return false, err
 	}
@@ -51,7 +64,7 @@ func (svc *ECSService) Check() (bool, error) {
 	return isValid && result, nil
 }
 
-func (svc *ECSService) Save(stateManager state.StateManager) error {
+func (svc *ECSService) Save(ctx context.Context, stateManager state.StateManager) error {
 	api := svc.Provider.NewEcsApi()
 
 	input := &ecs.DescribeServicesInput{
@@ -59,9 +72,9 @@ func (svc *ECSService) Save(stateManager state.StateManager) error {
 		Services: []string{*aws.String(svc.ServiceName)},
 	}
 
-	describeOutput, err := api.DescribeServices(context.TODO(), input)
+	describeOutput, err := api.DescribeServices(ctx, input)
 	if err != nil {
-		return err
+		return awsutils.ClassifyError(err)
 	}
 
 	service := describeOutput.Services[0]
@@ -75,20 +88,30 @@ func (svc *ECSService) Save(stateManager state.StateManager) error {
 
 	data, err := json.Marshal(state)
 	if err != nil {
-		log.Println("Error while marshalling service state")
+		svc.logger().Error("Error while marshalling service state", logging.Err(err))
 		return err
 	}
 
-	resourceKey := fmt.Sprintf("%s-%s", svc.ClusterArn, svc.ServiceName)
-	err = stateManager.Save(domain.ResourceTypeEcsService, resourceKey, data)
+	err = stateManager.Save(ctx, domain.ResourceTypeEcsService, svc.ResourceKey(), data)
 	if err != nil {
-		return err
+		return awsutils.ClassifyError(err)
 	}
+	svc.stateSubnets = subnets
 
 	return nil
 }
 
-func (svc *ECSService) Fail(azs []string) error {
+func (svc *ECSService) Fail(ctx context.Context, azs []string) error {
+	apply, err := svc.PrepareFail(ctx, azs)
+	if err != nil {
+		return err
+	}
+	return apply(ctx)
+}
+
+// Describes the service and its tasks and returns the function that removes the subnets in the
+// failed AZs and stops the tasks running in them
+func (svc *ECSService) PrepareFail(ctx context.Context, azs []string) (func(context.Context) error, error) {
 	ec2Api := svc.Provider.NewEc2Api()
 	ecsApi := svc.Provider.NewEcsApi()
 
@@ -97,53 +120,129 @@ func (svc *ECSService) Fail(azs []string) error {
 		Services: []string{*aws.String(svc.ServiceName)},
 	}
 
-	describeOutput, err := ecsApi.DescribeServices(context.TODO(), input)
+	describeOutput, err := ecsApi.DescribeServices(ctx, input)
 	if err != nil {
-		return err
+		return nil, awsutils.ClassifyError(err)
 	}
 
 	service := describeOutput.Services[0]
 	subnets := service.NetworkConfiguration.AwsvpcConfiguration.Subnets
 
-	newSubnets, err := awsutils.FilterSubnetsNotInAzs(ec2Api, subnets, azs)
+	newSubnets, err := awsutils.FilterSubnetsNotInAzs(ctx, ec2Api, subnets, azs)
 	if err != nil {
-		log.Printf("Error while filtering subnets by AZs: %v", err)
-		return err
+		svc.logger().Error("Error while filtering subnets by AZs", logging.Err(err))
+		return nil, awsutils.ClassifyError(err)
 	}
 
 	if len(newSubnets) == 0 {
-		return fmt.Errorf("AZ failure for service %s would remove all available subnets. Service failure will now stop", svc.ServiceName)
+		return nil, fmt.Errorf("AZ failure for service %s would remove all available subnets. Service failure will now stop", svc.ServiceName)
 	}
 
-	log.Printf("%s cluster=%s,name=%s: failing AZs %s for ecs-service",
-		domain.ResourceTypeEcsService, svc.ClusterArn, svc.ServiceName, azs)
+	taskArns, err := findTasksInRemovedSubnets(ctx, ecsApi, svc.ClusterArn, svc.ServiceName, newSubnets)
+	if err != nil {
+		return nil, awsutils.ClassifyError(err)
+	}
 
-	updatedNetworkConfig := service.NetworkConfiguration
-	updatedNetworkConfig.AwsvpcConfiguration.Subnets = newSubnets
+	return func(ctx context.Context) error {
+		svc.logger().Info("Failing AZs for ecs-service", "azs", azs)
 
-	updateServiceInput := &ecs.UpdateServiceInput{
-		Cluster:              aws.String(svc.ClusterArn),
-		Service:              aws.String(svc.ServiceName),
-		TaskDefinition:       service.TaskDefinition,
-		NetworkConfiguration: updatedNetworkConfig,
+		updatedNetworkConfig := service.NetworkConfiguration
+		updatedNetworkConfig.AwsvpcConfiguration.Subnets = newSubnets
+
+		updateServiceInput := &ecs.UpdateServiceInput{
+			Cluster:              aws.String(svc.ClusterArn),
+			Service:              aws.String(svc.ServiceName),
+			TaskDefinition:       service.TaskDefinition,
+			NetworkConfiguration: updatedNetworkConfig,
+		}
+
+		_, err := ecsApi.UpdateService(ctx, updateServiceInput)
+		if err != nil {
+			return awsutils.ClassifyError(err)
+		}
+
+		return stopTasks(ctx, ecsApi, svc.ClusterArn, svc.ServiceName, taskArns)
+	}, nil
+}
+
+func (svc *ECSService) Plan(ctx context.Context, azs []string) (*domain.FailurePlan, error) {
+	ec2Api := svc.Provider.NewEc2Api()
+	ecsApi := svc.Provider.NewEcsApi()
+
+	input := &ecs.DescribeServicesInput{
+		Cluster:  aws.String(svc.ClusterArn),
+		Services: []string{*aws.String(svc.ServiceName)},
 	}
 
-	_, err = ecsApi.UpdateService(context.TODO(), updateServiceInput)
+	describeOutput, err := ecsApi.DescribeServices(ctx, input)
 	if err != nil {
-		return err
+		return nil, awsutils.ClassifyError(err)
+	}
+
+	service := describeOutput.Services[0]
+	subnets := service.NetworkConfiguration.AwsvpcConfiguration.Subnets
+
+	newSubnets, err := awsutils.FilterSubnetsNotInAzs(ctx, ec2Api, subnets, azs)
+	if err != nil {
+		return nil, awsutils.ClassifyError(err)
+	}
+
+	if len(newSubnets) == 0 {
+		return nil, fmt.Errorf("AZ failure for service %s would remove all available subnets", svc.ServiceName)
 	}
 
-	err = stopTasksInRemovedSubnets(ecsApi, svc.ClusterArn, svc.ServiceName, newSubnets)
+	tasks, err := findTasksInRemovedSubnets(ctx, ecsApi, svc.ClusterArn, svc.ServiceName, newSubnets)
 	if err != nil {
-		return err
+		return nil, awsutils.ClassifyError(err)
 	}
 
-	return nil
+	return &domain.FailurePlan{
+		ResourceType:   domain.ResourceTypeEcsService,
+		ResourceKey:    svc.ResourceKey(),
+		CurrentSubnets: subnets,
+		NewSubnets:     newSubnets,
+		Terminations:   tasks,
+	}, nil
 }
 
-func (svc *ECSService) Restore() error {
-	log.Printf("%s cluster=%s,name=%s: restoring AZs for ecs-service",
-		domain.ResourceTypeEcsService, svc.ClusterArn, svc.ServiceName)
+// Verifies the service is back to its desired task count with no tasks running in the failed AZs
+func (svc *ECSService) VerifySteadyState(ctx context.Context, azs []string) (bool, error) {
+	api := svc.Provider.NewEcsApi()
+
+	input := &ecs.DescribeServicesInput{
+		Cluster:  aws.String(svc.ClusterArn),
+		Services: []string{*aws.String(svc.ServiceName)},
+	}
+
+	describeOutput, err := api.DescribeServices(ctx, input)
+	if err != nil {
+		return false, awsutils.ClassifyError(err)
+	}
+	if len(describeOutput.Services) == 0 {
+		return false, serviceNotFoundError(svc.ServiceName, svc.ClusterArn)
+	}
+
+	service := describeOutput.Services[0]
+	if service.RunningCount < service.DesiredCount {
+		return false, fmt.Errorf("Desired task count for service %s is not met. Desired %d, running %d.",
+			svc.ServiceName, service.DesiredCount, service.RunningCount)
+	}
+
+	subnets := service.NetworkConfiguration.AwsvpcConfiguration.Subnets
+	tasks, err := findTasksInRemovedSubnets(ctx, api, svc.ClusterArn, svc.ServiceName, subnets)
+	if err != nil {
+		return false, awsutils.ClassifyError(err)
+	}
+	if len(tasks) > 0 {
+		return false, fmt.Errorf("Service %s still has %d tasks running in removed subnets",
+			svc.ServiceName, len(tasks))
+	}
+
+	return true, nil
+}
+
+func (svc *ECSService) Restore(ctx context.Context) error {
+	svc.logger().Info("Restoring AZs for ecs-service")
 
 	api := svc.Provider.NewEcsApi()
 
@@ -152,9 +251,9 @@ func (svc *ECSService) Restore() error {
 		Services: []string{*aws.String(svc.ServiceName)},
 	}
 
-	describeOutput, err := api.DescribeServices(context.TODO(), input)
+	describeOutput, err := api.DescribeServices(ctx, input)
 	if err != nil {
-		return err
+		return awsutils.ClassifyError(err)
 	}
 
 	service := describeOutput.Services[0]
@@ -169,55 +268,95 @@ func (svc *ECSService) Restore() error {
 		NetworkConfiguration: updatedNetworkConfig,
 	}
 
-	_, err = api.UpdateService(context.TODO(), updateServiceInput)
+	_, err = api.UpdateService(ctx, updateServiceInput)
 	if err != nil {
-		return err
+		return awsutils.ClassifyError(err)
 	}
 	return nil
 }
 
+// Verifies the service network configuration matches the subnets saved in state
+func (svc *ECSService) VerifyRestored(ctx context.Context) (bool, error) {
+	api := svc.Provider.NewEcsApi()
+
+	input := &ecs.DescribeServicesInput{
+		Cluster:  aws.String(svc.ClusterArn),
+		Services: []string{*aws.String(svc.ServiceName)},
+	}
+
+	describeOutput, err := api.DescribeServices(ctx, input)
+	if err != nil {
+		return false, awsutils.ClassifyError(err)
+	}
+	if len(describeOutput.Services) == 0 {
+		return false, serviceNotFoundError(svc.ServiceName, svc.ClusterArn)
+	}
+
+	subnets := describeOutput.Services[0].NetworkConfiguration.AwsvpcConfiguration.Subnets
+	if !awsutils.SameSubnets(subnets, svc.stateSubnets) {
+		return false, fmt.Errorf("Service %s subnets %s do not match saved subnets %s",
+			svc.ServiceName, subnets, svc.stateSubnets)
+	}
+	return true, nil
+}
+
 // Search and terminate tasks that have an attachment to subnets that have been eliminated from
 // the network configuration
-func stopTasksInRemovedSubnets(api awsapis.EcsApi, cluster string, service string, validSubnets []string) error {
+func stopTasks(ctx context.Context, api awsapis.EcsApi, cluster string, service string, taskArns []string) error {
+	for _, taskArn := range taskArns {
+		stopTaskInput := &ecs.StopTaskInput{
+			Cluster: aws.String(cluster),
+			Task:    aws.String(taskArn),
+			Reason:  aws.String("AZ failure simulation. Task belonged to removed subnet."),
+		}
+		_, err := api.StopTask(ctx, stopTaskInput)
+		if err != nil {
+			return awsutils.ClassifyError(err)
+		}
+		logging.ForResource(domain.ResourceTypeEcsService, fmt.Sprintf("%s-%s", cluster, service)).Info(
+			"Terminating task running in removed subnets", "task", taskArn)
+	}
+
+	return nil
+}
+
+// Returns the ARNs of the service tasks that have an attachment to subnets that are not
+// in the `validSubnets` list
+func findTasksInRemovedSubnets(ctx context.Context, api awsapis.EcsApi, cluster string, service string, validSubnets []string) ([]string, error) {
+	taskArns := []string{}
+
 	paginator := api.NewListTasksPaginator(&ecs.ListTasksInput{
 		Cluster:     aws.String(cluster),
 		ServiceName: aws.String(service),
 	})
 
 	for paginator.HasMorePages() {
-		listTasksOutput, err := paginator.NextPage(context.TODO())
+		listTasksOutput, err := paginator.NextPage(ctx)
 		if err != nil {
-			return err
+			return nil, awsutils.ClassifyError(err)
+		}
+		if len(listTasksOutput.TaskArns) == 0 {
+			continue
 		}
-		describeTasksOutput, err := api.DescribeTasks(context.TODO(), &ecs.DescribeTasksInput{
+		describeTasksOutput, err := api.DescribeTasks(ctx, &ecs.DescribeTasksInput{
 			Cluster: aws.String(cluster),
 			Tasks:   listTasksOutput.TaskArns,
 		})
 		if err != nil {
-			return err
+			return nil, awsutils.ClassifyError(err)
 		}
 
 		for _, task := range describeTasksOutput.Tasks {
-			taskSubnets := getTaskSubnets(task)
-			for _, sub := range taskSubnets {
+			for _, sub := range getTaskSubnets(task) {
 				if !slices.Contains(validSubnets, sub) {
-					stopTaskInput := &ecs.StopTaskInput{
-						Cluster: aws.String(cluster),
-						Task:    task.TaskArn,
-						Reason:  aws.String("AZ failure simulation. Task belonged to removed subnet."),
-					}
-					_, err = api.StopTask(context.TODO(), stopTaskInput)
-					if err != nil {
-						return err
-					}
-					log.Printf("%s cluster=%s,name=%s: terminating task %s running in removed subnets.",
-						domain.ResourceTypeEcsService, cluster, service, *task.TaskArn)
+					taskArns = append(taskArns, *task.TaskArn)
+					break
 				}
 			}
 		}
 	}
 
-	return nil
+	return taskArns, nil
 }
 
 // Returns the list of subnets attached to an ECS task
@@ -236,31 +375,45 @@ func getTaskSubnets(task ecsTypes.Task) []string {
 // Verify if it's safe to fail service availability zones
 // In order to avoid compromising already unstable services, this method verifies that
 // the service exists and has currently reached a stable state.
-func serviceStable(api awsapis.EcsApi, clusterArn string, serviceName string) (bool, error) {
+// When the service is not stable, the returned error describes what the service is waiting for.
+func serviceStable(ctx context.Context, api awsapis.EcsApi, clusterArn string, serviceName string) (bool, error) {
 
 	input := &ecs.DescribeServicesInput{
 		Cluster:  aws.String(clusterArn),
 		Services: []string{*aws.String(serviceName)},
 	}
 
-	describeOutput, err := api.DescribeServices(context.TODO(), input)
+	describeOutput, err := api.DescribeServices(ctx, input)
 
+	// Missing services and clusters stop the checks, as they will not become stable by waiting
 	if err != nil {
 		if t := new(types.ResourceNotFoundException); errors.As(err, &t) {
-			return false, nil
+			return false, serviceNotFoundError(serviceName, clusterArn)
 		} else if t := new(types.ClusterNotFoundException); errors.As(err, &t) {
-			return false, nil
+			err := fmt.Errorf("Cluster %s not found", clusterArn)
+			return false, domain.ActivityFailedError{Wrap: err, Temporary: false}
 		}
-		return false, err
+		return false, awsutils.ClassifyError(err)
 	}
 
 	if len(describeOutput.Services) == 0 {
-		return false, nil
+		return false, serviceNotFoundError(serviceName, clusterArn)
 	}
 	svc := describeOutput.Services[0]
 
-	serviceStable := *svc.Status == "ACTIVE" &&
-		svc.DesiredCount == svc.RunningCount
+	if *svc.Status != "ACTIVE" {
+		return false, fmt.Errorf("Service %s is not active. Found status %s.", serviceName, *svc.Status)
+	}
+	if svc.DesiredCount != svc.RunningCount {
+		return false, fmt.Errorf("Desired task count for service %s is not met. Desired %d, running %d.",
+			serviceName, svc.DesiredCount, svc.RunningCount)
+	}
+
+	return true, nil
+}
 
-	return serviceStable, nil
+// Returns a non-temporary error for a service missing from the cluster
+func serviceNotFoundError(serviceName string, clusterArn string) error {
+	err := fmt.Errorf("Service %s not found in cluster %s", serviceName, clusterArn)
+	return domain.ActivityFailedError{Wrap: err, Temporary: false}
 }
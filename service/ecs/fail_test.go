@@ -0,0 +1,57 @@
+package ecs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/mcastellin/aws-fail-az/awsapis_mocks"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCheckShouldStopForMissingService(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockEcsApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewEcsApi().AnyTimes().Return(mockApi)
+
+	mockApi.EXPECT().DescribeServices(gomock.Any(), gomock.Any()).Times(1).
+		Return(&ecs.DescribeServicesOutput{Services: []types.Service{}}, nil)
+
+	result, err := (&ECSService{Provider: mockProvider, ClusterArn: "test-cluster", ServiceName: "test-service"}).
+		Check(context.TODO())
+
+	var activityErr domain.ActivityFailedError
+	assert.False(t, result)
+	assert.True(t, errors.As(err, &activityErr))
+	assert.False(t, activityErr.IsTemporary())
+}
+
+func TestCheckShouldWaitForDesiredTaskCount(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockEcsApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewEcsApi().AnyTimes().Return(mockApi)
+
+	mockApi.EXPECT().DescribeServices(gomock.Any(), gomock.Any()).Times(1).
+		Return(&ecs.DescribeServicesOutput{Services: []types.Service{
+			{ServiceName: aws.String("test-service"), Status: aws.String("ACTIVE"), DesiredCount: 2, RunningCount: 1},
+		}}, nil)
+
+	result, err := (&ECSService{Provider: mockProvider, ClusterArn: "test-cluster", ServiceName: "test-service"}).
+		Check(context.TODO())
+
+	var activityErr domain.ActivityFailedError
+	assert.False(t, result)
+	assert.NotNil(t, err)
+	assert.False(t, errors.As(err, &activityErr))
+}
@@ -12,23 +12,23 @@ import (
 	"github.com/mcastellin/aws-fail-az/service/awsutils"
 )
 
-func RestoreEcsServicesFromState(stateData []byte, provider awsapis.AWSProvider) error {
+func NewEcsServiceFromState(stateData []byte, provider awsapis.AWSProvider) (domain.ConsistentStateResource, error) {
 	var state ECSServiceState
 	err := json.Unmarshal(stateData, &state)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	resource := ECSService{
+	resource := &ECSService{
 		Provider:     provider,
 		ClusterArn:   state.ClusterArn,
 		ServiceName:  state.ServiceName,
 		stateSubnets: state.Subnets,
 	}
-	return resource.Restore()
+	return resource, nil
 }
 
-func NewEcsServiceFaultFromConfig(selector domain.TargetSelector, provider awsapis.AWSProvider) ([]domain.ConsistentStateResource, error) {
+func NewEcsServiceFaultFromConfig(ctx context.Context, selector domain.TargetSelector, provider awsapis.AWSProvider) ([]domain.ConsistentStateResource, error) {
 	if selector.Type != domain.ResourceTypeEcsService {
 		return nil, fmt.Errorf("unable to create ECSService object from selector of type %s", selector.Type)
 	}
@@ -56,7 +56,7 @@ func NewEcsServiceFaultFromConfig(selector domain.TargetSelector, provider awsap
 		}
 	} else if len(selector.Tags) > 0 {
 		api := provider.NewEcsApi()
-		clusters, err := searchAllClusters(api, selector.Tags)
+		clusters, err := searchAllClusters(ctx, api, selector.Tags)
 		if err != nil {
 			return nil, err
 		}
@@ -75,18 +75,18 @@ func NewEcsServiceFaultFromConfig(selector domain.TargetSelector, provider awsap
 	return objs, nil
 }
 
-func searchAllClusters(api awsapis.EcsApi, tags []domain.AWSTag) (map[string][]string, error) {
+func searchAllClusters(ctx context.Context, api awsapis.EcsApi, tags []domain.AWSTag) (map[string][]string, error) {
 	allClusters := map[string][]string{}
 
 	paginator := api.NewListClustersPaginator(&ecs.ListClustersInput{})
 	for paginator.HasMorePages() {
-		response, err := paginator.NextPage(context.TODO())
+		response, err := paginator.NextPage(ctx)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, cluster := range response.ClusterArns {
-			serviceArns, err := filterECSServicesByTag(api, cluster, tags)
+			serviceArns, err := filterECSServicesByTag(ctx, api, cluster, tags)
 			if err != nil {
 				return nil, err
 			}
@@ -98,7 +98,7 @@ func searchAllClusters(api awsapis.EcsApi, tags []domain.AWSTag) (map[string][]s
 	return allClusters, nil
 }
 
-func filterECSServicesByTag(api awsapis.EcsApi, cluster string, tags []domain.AWSTag) ([]string, error) {
+func filterECSServicesByTag(ctx context.Context, api awsapis.EcsApi, cluster string, tags []domain.AWSTag) ([]string, error) {
 	serviceArns := []string{}
 
 	paginator := api.NewListServicesPaginator(&ecs.ListServicesInput{
@@ -106,13 +106,13 @@ func filterECSServicesByTag(api awsapis.EcsApi, cluster string, tags []domain.AW
 	})
 
 	for paginator.HasMorePages() {
-		response, err := paginator.NextPage(context.TODO())
+		response, err := paginator.NextPage(ctx)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, arn := range response.ServiceArns {
-			service, err := api.ListTagsForResource(context.TODO(), &ecs.ListTagsForResourceInput{
+			service, err := api.ListTagsForResource(ctx, &ecs.ListTagsForResourceInput{
 				ResourceArn: aws.String(arn),
 			})
 			if err != nil {
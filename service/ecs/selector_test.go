@@ -40,7 +40,7 @@ func TestFilterServiceByTagsShouldExcludeResults(t *testing.T) {
 		Tags: []domain.AWSTag{{Name: "Application", Value: "notfound"}},
 	}
 
-	results, err := NewEcsServiceFaultFromConfig(config, mockProvider)
+	results, err := NewEcsServiceFaultFromConfig(context.TODO(), config, mockProvider)
 
 	assert.Nil(t, err)
 	assert.Len(t, results, 0)
@@ -74,7 +74,7 @@ func TestFilterServiceByTagsShouldMatch(t *testing.T) {
 		Tags: []domain.AWSTag{{Name: "Application", Value: "live-app"}},
 	}
 
-	results, err := NewEcsServiceFaultFromConfig(config, mockProvider)
+	results, err := NewEcsServiceFaultFromConfig(context.TODO(), config, mockProvider)
 
 	assert.Nil(t, err)
 	assert.Len(t, results, 1)
@@ -109,7 +109,7 @@ func TestFilterServiceByTagsShouldMatchResultsFromAllPages(t *testing.T) {
 		Tags: []domain.AWSTag{{Name: "Application", Value: "live-app"}},
 	}
 
-	results, err := NewEcsServiceFaultFromConfig(config, mockProvider)
+	results, err := NewEcsServiceFaultFromConfig(context.TODO(), config, mockProvider)
 
 	assert.Nil(t, err)
 	assert.Len(t, results, 3)
@@ -0,0 +1,256 @@
+package eks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/mcastellin/aws-fail-az/awsapis"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
+	"github.com/mcastellin/aws-fail-az/service/asg"
+	"github.com/mcastellin/aws-fail-az/service/awsutils"
+	"github.com/mcastellin/aws-fail-az/state"
+	"golang.org/x/exp/slices"
+)
+
+type NodegroupState struct {
+	ClusterName   string `json:"clusterName"`
+	NodegroupName string `json:"nodegroupName"`
+	// The autoscaling groups backing the node group when the state was saved
+	AutoScalingGroups []asg.AutoScalingGroupState `json:"autoScalingGroups"`
+}
+
+// An EKS managed node group. AZ failure is applied to the autoscaling groups of the node group
+type Nodegroup struct {
+	Provider      awsapis.AWSProvider
+	ClusterName   string
+	NodegroupName string
+
+	stateGroups []asg.AutoScalingGroupState
+}
+
+func (ng *Nodegroup) ResourceType() string {
+	return domain.ResourceTypeEksNodegroup
+}
+
+func (ng *Nodegroup) ResourceKey() string {
+	return fmt.Sprintf("%s/%s", ng.ClusterName, ng.NodegroupName)
+}
+
+func (ng *Nodegroup) logger() *slog.Logger {
+	return logging.ForResource(ng.ResourceType(), ng.ResourceKey())
+}
+
+func (ng *Nodegroup) Check(ctx context.Context) (bool, error) {
+	ng.logger().Debug("Checking resource state before failure simulation")
+
+	nodegroup, err := describeNodegroup(ctx, ng.Provider.NewEksApi(), ng.ClusterName, ng.NodegroupName)
+	if err != nil {
+		return false, err
+	}
+	if nodegroup.Status != types.NodegroupStatusActive {
+		return false, fmt.Errorf("Node group %s is not active. Found status %s.", ng.ResourceKey(), nodegroup.Status)
+	}
+
+	groups, err := ng.autoScalingGroups(nodegroup)
+	if err != nil {
+		return false, err
+	}
+	for _, group := range groups {
+		if ok, err := group.Check(ctx); !ok || err != nil {
+			return ok, err
+		}
+	}
+	return true, nil
+}
+
+func (ng *Nodegroup) Save(ctx context.Context, stateManager state.StateManager) error {
+
+	groups, err := ng.describeAutoScalingGroups(ctx)
+	if err != nil {
+		return err
+	}
+
+	state := &NodegroupState{
+		ClusterName:       ng.ClusterName,
+		NodegroupName:     ng.NodegroupName,
+		AutoScalingGroups: []asg.AutoScalingGroupState{},
+	}
+	for _, group := range groups {
+		groupState, err := group.CurrentState(ctx)
+		if err != nil {
+			return err
+		}
+		state.AutoScalingGroups = append(state.AutoScalingGroups, *groupState)
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		ng.logger().Error("Error while marshalling node group state", logging.Err(err))
+		return err
+	}
+	err = stateManager.Save(ctx, domain.ResourceTypeEksNodegroup, ng.ResourceKey(), data)
+	if err != nil {
+		return awsutils.ClassifyError(err)
+	}
+	ng.stateGroups = state.AutoScalingGroups
+
+	return nil
+}
+
+func (ng *Nodegroup) Fail(ctx context.Context, azs []string) error {
+	apply, err := ng.PrepareFail(ctx, azs)
+	if err != nil {
+		return err
+	}
+	return apply(ctx)
+}
+
+// Describes the node group autoscaling groups and returns the function that fails them one
+// after the other
+func (ng *Nodegroup) PrepareFail(ctx context.Context, azs []string) (func(context.Context) error, error) {
+
+	groups, err := ng.describeAutoScalingGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	applies := make([]func(context.Context) error, 0, len(groups))
+	for _, group := range groups {
+		apply, err := group.PrepareFail(ctx, azs)
+		if err != nil {
+			return nil, err
+		}
+		applies = append(applies, apply)
+	}
+
+	return func(ctx context.Context) error {
+		ng.logger().Info("Failing AZs for node group autoscaling groups", "azs", azs)
+
+		for _, apply := range applies {
+			if err := apply(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
+
+func (ng *Nodegroup) Plan(ctx context.Context, azs []string) (*domain.FailurePlan, error) {
+
+	groups, err := ng.describeAutoScalingGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &domain.FailurePlan{
+		ResourceType:   domain.ResourceTypeEksNodegroup,
+		ResourceKey:    ng.ResourceKey(),
+		CurrentSubnets: []string{},
+		NewSubnets:     []string{},
+		Terminations:   []string{},
+	}
+	for _, group := range groups {
+		groupPlan, err := group.Plan(ctx, azs)
+		if err != nil {
+			return nil, err
+		}
+		plan.CurrentSubnets = appendMissing(plan.CurrentSubnets, groupPlan.CurrentSubnets)
+		plan.NewSubnets = appendMissing(plan.NewSubnets, groupPlan.NewSubnets)
+		plan.Terminations = append(plan.Terminations, groupPlan.Terminations...)
+	}
+	return plan, nil
+}
+
+// Verifies the node group autoscaling groups have replaced the instances terminated in the failed AZs
+func (ng *Nodegroup) VerifySteadyState(ctx context.Context, azs []string) (bool, error) {
+	groups, err := ng.describeAutoScalingGroups(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, group := range groups {
+		if ok, err := group.VerifySteadyState(ctx, azs); !ok || err != nil {
+			return ok, err
+		}
+	}
+	return true, nil
+}
+
+// Restores the subnets of the autoscaling groups saved in state, even if the node
+// group has been updated since
+func (ng *Nodegroup) Restore(ctx context.Context) error {
+	ng.logger().Info("Restoring AZs for node group autoscaling groups")
+
+	for _, groupState := range ng.stateGroups {
+		group := asg.NewAutoScalingGroupWithState(groupState, ng.Provider)
+		if err := group.Restore(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Verifies the subnets of the autoscaling groups match the subnets saved in state
+func (ng *Nodegroup) VerifyRestored(ctx context.Context) (bool, error) {
+	for _, groupState := range ng.stateGroups {
+		group := asg.NewAutoScalingGroupWithState(groupState, ng.Provider)
+		if ok, err := group.VerifyRestored(ctx); !ok || err != nil {
+			return ok, err
+		}
+	}
+	return true, nil
+}
+
+// Returns the autoscaling groups currently backing the node group
+func (ng *Nodegroup) describeAutoScalingGroups(ctx context.Context) ([]*asg.AutoScalingGroup, error) {
+	nodegroup, err := describeNodegroup(ctx, ng.Provider.NewEksApi(), ng.ClusterName, ng.NodegroupName)
+	if err != nil {
+		return nil, err
+	}
+	return ng.autoScalingGroups(nodegroup)
+}
+
+func (ng *Nodegroup) autoScalingGroups(nodegroup *types.Nodegroup) ([]*asg.AutoScalingGroup, error) {
+	if nodegroup.Resources == nil || len(nodegroup.Resources.AutoScalingGroups) == 0 {
+		return nil, fmt.Errorf("Could not find the autoscaling groups of node group %s", ng.ResourceKey())
+	}
+
+	groups := []*asg.AutoScalingGroup{}
+	for _, group := range nodegroup.Resources.AutoScalingGroups {
+		groups = append(groups, &asg.AutoScalingGroup{
+			Provider:             ng.Provider,
+			AutoScalingGroupName: aws.ToString(group.Name),
+		})
+	}
+	return groups, nil
+}
+
+func describeNodegroup(ctx context.Context, api awsapis.EksNodegroupDescriptor, cluster string, name string) (*types.Nodegroup, error) {
+	output, err := api.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
+		ClusterName:   aws.String(cluster),
+		NodegroupName: aws.String(name),
+	})
+	if err != nil {
+		return nil, awsutils.ClassifyError(err)
+	}
+	if output.Nodegroup == nil {
+		return nil, fmt.Errorf("Could not describe node group %s in cluster %s", name, cluster)
+	}
+	return output.Nodegroup, nil
+}
+
+// Appends the items not already in the list
+func appendMissing(list []string, items []string) []string {
+	for _, item := range items {
+		if !slices.Contains(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,94 @@
+package eks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	asgTypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/mcastellin/aws-fail-az/awsapis_mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFailShouldFailNodegroupAutoScalingGroups(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockEksApi := awsapis_mocks.NewMockEksApi(ctrl)
+	mockAsgApi := awsapis_mocks.NewMockAutoScalingApi(ctrl)
+	mockEc2Api := awsapis_mocks.NewMockEc2Api(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewEksApi().AnyTimes().Return(mockEksApi)
+	mockProvider.EXPECT().NewAutoScalingApi().AnyTimes().Return(mockAsgApi)
+	mockProvider.EXPECT().NewEc2Api().AnyTimes().Return(mockEc2Api)
+
+	mockEksApi.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Times(1).
+		Return(&eks.DescribeNodegroupOutput{Nodegroup: &types.Nodegroup{
+			ClusterName:   aws.String("test-cluster"),
+			NodegroupName: aws.String("test-ng"),
+			Resources: &types.NodegroupResources{
+				AutoScalingGroups: []types.AutoScalingGroup{{Name: aws.String("eks-test-ng-asg")}},
+			},
+		}}, nil)
+	mockAsgApi.EXPECT().DescribeAutoScalingGroups(gomock.Any(), gomock.Any()).Times(1).
+		Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+			AutoScalingGroups: []asgTypes.AutoScalingGroup{{
+				AutoScalingGroupName: aws.String("eks-test-ng-asg"),
+				VPCZoneIdentifier:    aws.String("s-1,s-2"),
+				Instances: []asgTypes.Instance{
+					{InstanceId: aws.String("i-1"), AvailabilityZone: aws.String("us-east-1a")},
+					{InstanceId: aws.String("i-2"), AvailabilityZone: aws.String("us-east-1b")},
+				},
+			}},
+		}, nil)
+	mockEc2Api.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any()).Times(1).
+		Return(&ec2.DescribeSubnetsOutput{Subnets: []ec2Types.Subnet{
+			{SubnetId: aws.String("s-1"), AvailabilityZone: aws.String("us-east-1a")},
+			{SubnetId: aws.String("s-2"), AvailabilityZone: aws.String("us-east-1b")},
+		}}, nil)
+	mockAsgApi.EXPECT().UpdateAutoScalingGroup(gomock.Any(), &autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String("eks-test-ng-asg"),
+		VPCZoneIdentifier:    aws.String("s-2"),
+	}).Times(1).Return(&autoscaling.UpdateAutoScalingGroupOutput{}, nil)
+	mockEc2Api.EXPECT().TerminateInstances(gomock.Any(), &ec2.TerminateInstancesInput{
+		InstanceIds: []string{"i-1"},
+	}).Times(1).Return(&ec2.TerminateInstancesOutput{}, nil)
+
+	err := (&Nodegroup{
+		Provider:      mockProvider,
+		ClusterName:   "test-cluster",
+		NodegroupName: "test-ng",
+	}).Fail(context.TODO(), []string{"us-east-1a"})
+
+	assert.Nil(t, err)
+}
+
+func TestRestoreShouldRestoreSavedAutoScalingGroups(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockAsgApi := awsapis_mocks.NewMockAutoScalingApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewAutoScalingApi().AnyTimes().Return(mockAsgApi)
+	mockProvider.EXPECT().NewEksApi().Times(0)
+
+	mockAsgApi.EXPECT().UpdateAutoScalingGroup(gomock.Any(), &autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String("eks-test-ng-asg"),
+		VPCZoneIdentifier:    aws.String("s-1,s-2"),
+	}).Times(1).Return(&autoscaling.UpdateAutoScalingGroupOutput{}, nil)
+
+	resource, err := NewNodegroupFromState([]byte(`{"clusterName":"test-cluster","nodegroupName":"test-ng",`+
+		`"autoScalingGroups":[{"asgName":"eks-test-ng-asg","subnets":["s-1","s-2"]}]}`), mockProvider)
+	assert.Nil(t, err)
+	assert.Equal(t, "test-cluster/test-ng", resource.ResourceKey())
+
+	err = resource.Restore(context.TODO())
+
+	assert.Nil(t, err)
+}
@@ -0,0 +1,118 @@
+package eks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/mcastellin/aws-fail-az/awsapis"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/service/awsutils"
+)
+
+func NewNodegroupFromState(stateData []byte, provider awsapis.AWSProvider) (domain.ConsistentStateResource, error) {
+	var state NodegroupState
+	err := json.Unmarshal(stateData, &state)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := &Nodegroup{
+		Provider:      provider,
+		ClusterName:   state.ClusterName,
+		NodegroupName: state.NodegroupName,
+		stateGroups:   state.AutoScalingGroups,
+	}
+	return resource, nil
+}
+
+func NewNodegroupFaultFromConfig(ctx context.Context, selector domain.TargetSelector, provider awsapis.AWSProvider) ([]domain.ConsistentStateResource, error) {
+	if selector.Type != domain.ResourceTypeEksNodegroup {
+		return nil, fmt.Errorf("Unable to create Nodegroup object from selector of type %s.", selector.Type)
+	}
+
+	objs := []domain.ConsistentStateResource{}
+	var err error
+
+	err = selector.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := awsutils.TokenizeResourceFilter(selector.Filter, []string{"cluster", "nodegroup"})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(attributes) == 2 {
+		objs = []domain.ConsistentStateResource{
+			&Nodegroup{
+				Provider:      provider,
+				ClusterName:   attributes["cluster"],
+				NodegroupName: attributes["nodegroup"],
+			},
+		}
+	} else if len(selector.Tags) > 0 {
+		api := provider.NewEksApi()
+		nodegroups, err := filterNodegroupsByTags(ctx, api, selector.Tags)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, nodegroup := range nodegroups {
+			objs = append(objs, &Nodegroup{
+				Provider:      provider,
+				ClusterName:   nodegroup[0],
+				NodegroupName: nodegroup[1],
+			})
+		}
+	}
+
+	return objs, nil
+}
+
+// Returns the cluster and node group names of all node groups with matching tags
+func filterNodegroupsByTags(ctx context.Context, api awsapis.EksApi, tags []domain.AWSTag) ([][2]string, error) {
+	nodegroups := [][2]string{}
+
+	clustersPaginator := api.NewListClustersPaginator(&eks.ListClustersInput{})
+	for clustersPaginator.HasMorePages() {
+		clustersPage, err := clustersPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cluster := range clustersPage.Clusters {
+			nodegroupsPaginator := api.NewListNodegroupsPaginator(&eks.ListNodegroupsInput{ClusterName: aws.String(cluster)})
+			for nodegroupsPaginator.HasMorePages() {
+				nodegroupsPage, err := nodegroupsPaginator.NextPage(ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, name := range nodegroupsPage.Nodegroups {
+					nodegroup, err := describeNodegroup(ctx, api, cluster, name)
+					if err != nil {
+						return nil, err
+					}
+					if resourceTagsMatchFilters(nodegroup.Tags, tags) {
+						nodegroups = append(nodegroups, [2]string{cluster, name})
+					}
+				}
+			}
+		}
+	}
+
+	return nodegroups, nil
+}
+
+func resourceTagsMatchFilters(resourceTags map[string]string, filterTags []domain.AWSTag) bool {
+	for _, filterTag := range filterTags {
+		if value, ok := resourceTags[filterTag.Name]; !ok || value != filterTag.Value {
+			return false
+		}
+	}
+	return true
+}
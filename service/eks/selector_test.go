@@ -0,0 +1,68 @@
+package eks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/mcastellin/aws-fail-az/awsapis_mocks"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFilterNodegroupsByTagsShouldMatchInAllClusters(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	clustersPager := awsapis_mocks.NewMockEksListClustersPager(ctrl)
+	gomock.InOrder(
+		clustersPager.EXPECT().HasMorePages().Times(1).Return(true),
+		clustersPager.EXPECT().HasMorePages().Times(1).Return(false),
+	)
+	clustersPager.EXPECT().NextPage(gomock.Any()).Times(1).
+		Return(&eks.ListClustersOutput{Clusters: []string{"cluster-1", "cluster-2"}}, nil)
+
+	mockApi := awsapis_mocks.NewMockEksApi(ctrl)
+	mockApi.EXPECT().NewListClustersPaginator(gomock.Any()).Times(1).Return(clustersPager)
+	for cluster, nodegroups := range map[string][]string{"cluster-1": {"ng-1"}, "cluster-2": {"ng-2", "ng-3"}} {
+		nodegroupsPager := awsapis_mocks.NewMockEksListNodegroupsPager(ctrl)
+		gomock.InOrder(
+			nodegroupsPager.EXPECT().HasMorePages().Times(1).Return(true),
+			nodegroupsPager.EXPECT().HasMorePages().Times(1).Return(false),
+		)
+		nodegroupsPager.EXPECT().NextPage(gomock.Any()).Times(1).
+			Return(&eks.ListNodegroupsOutput{Nodegroups: nodegroups}, nil)
+		mockApi.EXPECT().NewListNodegroupsPaginator(&eks.ListNodegroupsInput{ClusterName: aws.String(cluster)}).
+			Times(1).Return(nodegroupsPager)
+	}
+
+	tags := map[string]map[string]string{
+		"ng-1": {"Environment": "live", "Application": "test"},
+		"ng-2": {"Environment": "live"},
+		"ng-3": {"Environment": "live", "Application": "test", "Other": "tag"},
+	}
+	mockApi.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Times(3).
+		DoAndReturn(func(ctx context.Context, input *eks.DescribeNodegroupInput, _ ...func(*eks.Options)) (*eks.DescribeNodegroupOutput, error) {
+			return &eks.DescribeNodegroupOutput{Nodegroup: &types.Nodegroup{
+				NodegroupName: input.NodegroupName,
+				Tags:          tags[*input.NodegroupName],
+			}}, nil
+		})
+
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewEksApi().AnyTimes().Return(mockApi)
+
+	config := domain.TargetSelector{
+		Type: domain.ResourceTypeEksNodegroup,
+		Tags: []domain.AWSTag{{Name: "Environment", Value: "live"}, {Name: "Application", Value: "test"}},
+	}
+	results, err := NewNodegroupFaultFromConfig(context.TODO(), config, mockProvider)
+
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "cluster-1/ng-1", results[0].ResourceKey())
+	assert.Equal(t, "cluster-2/ng-3", results[1].ResourceKey())
+}
@@ -0,0 +1,325 @@
+package elasticache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	"github.com/mcastellin/aws-fail-az/awsapis"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
+	"github.com/mcastellin/aws-fail-az/service/awsutils"
+	"github.com/mcastellin/aws-fail-az/state"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	replicationGroupStatusAvailable = "available"
+	nodeRolePrimary                 = "primary"
+)
+
+type ReplicationGroupState struct {
+	ReplicationGroupId string `json:"replicationGroupId"`
+	// The primary cache cluster of every node group by node group ID
+	Primaries map[string]string `json:"primaries"`
+}
+
+type ReplicationGroup struct {
+	Provider           awsapis.AWSProvider
+	ReplicationGroupId string
+
+	statePrimaries map[string]string
+}
+
+func (rg *ReplicationGroup) ResourceType() string {
+	return domain.ResourceTypeElastiCacheGroup
+}
+
+func (rg *ReplicationGroup) ResourceKey() string {
+	return rg.ReplicationGroupId
+}
+
+func (rg *ReplicationGroup) logger() *slog.Logger {
+	return logging.ForResource(rg.ResourceType(), rg.ResourceKey())
+}
+
+func (rg *ReplicationGroup) Check(ctx context.Context) (bool, error) {
+	rg.logger().Debug("Checking resource state before failure simulation")
+
+	group, err := describeReplicationGroup(ctx, rg.Provider.NewElastiCacheApi(), rg.ReplicationGroupId)
+	if err != nil {
+		return false, err
+	}
+
+	if group.AutomaticFailover != types.AutomaticFailoverStatusEnabled {
+		err := fmt.Errorf("Replication group %s does not have automatic failover enabled and cannot fail over"+
+			" to another AZ", rg.ReplicationGroupId)
+		return false, domain.ActivityFailedError{Wrap: err, Temporary: false}
+	}
+	if status := aws.ToString(group.Status); status != replicationGroupStatusAvailable {
+		return false, fmt.Errorf("Replication group %s is not available. Found status %s.", rg.ReplicationGroupId, status)
+	}
+
+	return true, nil
+}
+
+func (rg *ReplicationGroup) Save(ctx context.Context, stateManager state.StateManager) error {
+
+	group, err := describeReplicationGroup(ctx, rg.Provider.NewElastiCacheApi(), rg.ReplicationGroupId)
+	if err != nil {
+		return err
+	}
+
+	state := &ReplicationGroupState{
+		ReplicationGroupId: rg.ReplicationGroupId,
+		Primaries:          primaryNodes(group),
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		rg.logger().Error("Error while marshalling replication group state", logging.Err(err))
+		return err
+	}
+	err = stateManager.Save(ctx, domain.ResourceTypeElastiCacheGroup, rg.ReplicationGroupId, data)
+	if err != nil {
+		return awsutils.ClassifyError(err)
+	}
+	rg.statePrimaries = state.Primaries
+
+	return nil
+}
+
+// Fails over the node groups with the primary node in one of the failed AZs. Node groups
+// can only fail over one at a time: every failover waits for the replication group to be
+// available with a new primary node before the next node group is failed over
+func (rg *ReplicationGroup) Fail(ctx context.Context, azs []string) error {
+
+	api := rg.Provider.NewElastiCacheApi()
+
+	group, err := rg.waitForGroup(ctx, api, "replication group available", func(*types.ReplicationGroup) bool {
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	nodeGroupIds := nodeGroupsWithPrimaryInAzs(group, azs)
+	if len(nodeGroupIds) == 0 {
+		rg.logger().Info("No primary node in failed AZs, skipping failover")
+		return nil
+	}
+	primaries := primaryNodes(group)
+
+	for _, nodeGroupId := range nodeGroupIds {
+		rg.logger().Info("Failing over replication group node group", "azs", azs, "nodeGroup", nodeGroupId)
+
+		_, err = api.TestFailover(ctx, &elasticache.TestFailoverInput{
+			ReplicationGroupId: aws.String(rg.ReplicationGroupId),
+			NodeGroupId:        aws.String(nodeGroupId),
+		})
+		if err != nil {
+			return awsutils.ClassifyError(err)
+		}
+
+		_, err = rg.waitForGroup(ctx, api, "node group failover", func(group *types.ReplicationGroup) bool {
+			primary, ok := primaryNodes(group)[nodeGroupId]
+			return ok && primary != primaries[nodeGroupId]
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rg *ReplicationGroup) Plan(ctx context.Context, azs []string) (*domain.FailurePlan, error) {
+
+	api := rg.Provider.NewElastiCacheApi()
+
+	group, err := describeReplicationGroup(ctx, api, rg.ReplicationGroupId)
+	if err != nil {
+		return nil, err
+	}
+	subnetGroup, err := describeCacheSubnetGroup(ctx, api, group)
+	if err != nil {
+		return nil, err
+	}
+
+	// A failover replaces the primary node of the node group
+	nodeAzs := []string{}
+	terminations := []string{}
+	for _, nodeGroup := range group.NodeGroups {
+		for _, member := range nodeGroup.NodeGroupMembers {
+			if az := aws.ToString(member.PreferredAvailabilityZone); !slices.Contains(nodeAzs, az) {
+				nodeAzs = append(nodeAzs, az)
+			}
+		}
+		primary := primaryNode(nodeGroup)
+		if primary != nil && slices.Contains(azs, aws.ToString(primary.PreferredAvailabilityZone)) {
+			terminations = append(terminations, aws.ToString(primary.CacheClusterId))
+		}
+	}
+
+	currentSubnets := subnetsInAzs(subnetGroup, nodeAzs)
+	failedSubnets := subnetsInAzs(subnetGroup, azs)
+	newSubnets := []string{}
+	for _, subnet := range currentSubnets {
+		if !slices.Contains(failedSubnets, subnet) {
+			newSubnets = append(newSubnets, subnet)
+		}
+	}
+
+	return &domain.FailurePlan{
+		ResourceType:   domain.ResourceTypeElastiCacheGroup,
+		ResourceKey:    rg.ResourceKey(),
+		CurrentSubnets: currentSubnets,
+		NewSubnets:     newSubnets,
+		Terminations:   terminations,
+	}, nil
+}
+
+// Verifies the replication group is available with no primary node in the failed AZs
+func (rg *ReplicationGroup) VerifySteadyState(ctx context.Context, azs []string) (bool, error) {
+	group, err := describeReplicationGroup(ctx, rg.Provider.NewElastiCacheApi(), rg.ReplicationGroupId)
+	if err != nil {
+		return false, err
+	}
+
+	if status := aws.ToString(group.Status); status != replicationGroupStatusAvailable {
+		return false, fmt.Errorf("Replication group %s is not available. Found status %s.", rg.ReplicationGroupId, status)
+	}
+	if nodeGroupIds := nodeGroupsWithPrimaryInAzs(group, azs); len(nodeGroupIds) > 0 {
+		return false, fmt.Errorf("Replication group %s node groups %s still have the primary node in failed AZs",
+			rg.ReplicationGroupId, nodeGroupIds)
+	}
+	return true, nil
+}
+
+// Waits for the replication group to be available. Primary nodes are not failed back
+// to the primaries saved in state, as failover does not allow to select the new primary
+func (rg *ReplicationGroup) Restore(ctx context.Context) error {
+
+	group, err := rg.waitForGroup(ctx, rg.Provider.NewElastiCacheApi(), "replication group available",
+		func(*types.ReplicationGroup) bool { return true })
+	if err != nil {
+		return err
+	}
+
+	for _, nodeGroup := range group.NodeGroups {
+		nodeGroupId := aws.ToString(nodeGroup.NodeGroupId)
+		primary := primaryNode(nodeGroup)
+		if primary != nil && aws.ToString(primary.CacheClusterId) != rg.statePrimaries[nodeGroupId] {
+			rg.logger().Info("Node group primary changed after failover", "nodeGroup", nodeGroupId,
+				"primary", aws.ToString(primary.CacheClusterId), "savedPrimary", rg.statePrimaries[nodeGroupId])
+		}
+	}
+	return nil
+}
+
+// Waits until the replication group is available and `conditionFn` holds for it.
+// Returns the last description of the replication group
+func (rg *ReplicationGroup) waitForGroup(ctx context.Context, api awsapis.ElastiCacheReplicationGroupsDescriptor,
+	condition string, conditionFn func(*types.ReplicationGroup) bool) (*types.ReplicationGroup, error) {
+
+	var group *types.ReplicationGroup
+	err := awsutils.WaitUntil(ctx, awsutils.DEFAULT_WAIT_TIMEOUT, condition, func() (bool, error) {
+		var err error
+		group, err = describeReplicationGroup(ctx, api, rg.ReplicationGroupId)
+		if err != nil {
+			return false, err
+		}
+		return aws.ToString(group.Status) == replicationGroupStatusAvailable && conditionFn(group), nil
+	})
+	return group, err
+}
+
+func describeReplicationGroup(ctx context.Context, api awsapis.ElastiCacheReplicationGroupsDescriptor, id string) (*types.ReplicationGroup, error) {
+	output, err := api.DescribeReplicationGroups(ctx, &elasticache.DescribeReplicationGroupsInput{
+		ReplicationGroupId: aws.String(id),
+	})
+	if err != nil {
+		return nil, awsutils.ClassifyError(err)
+	}
+	if len(output.ReplicationGroups) == 0 {
+		return nil, fmt.Errorf("Could not describe replication group with ID %s", id)
+	}
+	return &output.ReplicationGroups[0], nil
+}
+
+// Returns the cache subnet group of the replication group member clusters
+func describeCacheSubnetGroup(ctx context.Context, api awsapis.ElastiCacheApi, group *types.ReplicationGroup) (*types.CacheSubnetGroup, error) {
+	groupId := aws.ToString(group.ReplicationGroupId)
+	if len(group.MemberClusters) == 0 {
+		return nil, fmt.Errorf("Replication group %s has no member clusters", groupId)
+	}
+
+	clustersOutput, err := api.DescribeCacheClusters(ctx, &elasticache.DescribeCacheClustersInput{
+		CacheClusterId: aws.String(group.MemberClusters[0]),
+	})
+	if err != nil {
+		return nil, awsutils.ClassifyError(err)
+	}
+	if len(clustersOutput.CacheClusters) == 0 {
+		return nil, fmt.Errorf("Could not describe cache cluster %s of replication group %s",
+			group.MemberClusters[0], groupId)
+	}
+
+	subnetGroupsOutput, err := api.DescribeCacheSubnetGroups(ctx, &elasticache.DescribeCacheSubnetGroupsInput{
+		CacheSubnetGroupName: clustersOutput.CacheClusters[0].CacheSubnetGroupName,
+	})
+	if err != nil {
+		return nil, awsutils.ClassifyError(err)
+	}
+	if len(subnetGroupsOutput.CacheSubnetGroups) == 0 {
+		return nil, fmt.Errorf("Could not describe cache subnet group of replication group %s", groupId)
+	}
+	return &subnetGroupsOutput.CacheSubnetGroups[0], nil
+}
+
+// Returns the subnets of the cache subnet group in one of the `azs`
+func subnetsInAzs(subnetGroup *types.CacheSubnetGroup, azs []string) []string {
+	subnets := []string{}
+	for _, subnet := range subnetGroup.Subnets {
+		if subnet.SubnetAvailabilityZone != nil &&
+			slices.Contains(azs, aws.ToString(subnet.SubnetAvailabilityZone.Name)) {
+			subnets = append(subnets, aws.ToString(subnet.SubnetIdentifier))
+		}
+	}
+	return subnets
+}
+
+// Returns the primary cache cluster of every node group by node group ID
+func primaryNodes(group *types.ReplicationGroup) map[string]string {
+	primaries := map[string]string{}
+	for _, nodeGroup := range group.NodeGroups {
+		if primary := primaryNode(nodeGroup); primary != nil {
+			primaries[aws.ToString(nodeGroup.NodeGroupId)] = aws.ToString(primary.CacheClusterId)
+		}
+	}
+	return primaries
+}
+
+// Returns the primary node of the node group, or nil if no member has the primary role
+func primaryNode(nodeGroup types.NodeGroup) *types.NodeGroupMember {
+	for idx := range nodeGroup.NodeGroupMembers {
+		if aws.ToString(nodeGroup.NodeGroupMembers[idx].CurrentRole) == nodeRolePrimary {
+			return &nodeGroup.NodeGroupMembers[idx]
+		}
+	}
+	return nil
+}
+
+// Returns the IDs of the node groups with the primary node in one of the `azs`
+func nodeGroupsWithPrimaryInAzs(group *types.ReplicationGroup, azs []string) []string {
+	nodeGroupIds := []string{}
+	for _, nodeGroup := range group.NodeGroups {
+		primary := primaryNode(nodeGroup)
+		if primary != nil && slices.Contains(azs, aws.ToString(primary.PreferredAvailabilityZone)) {
+			nodeGroupIds = append(nodeGroupIds, aws.ToString(nodeGroup.NodeGroupId))
+		}
+	}
+	return nodeGroupIds
+}
@@ -0,0 +1,193 @@
+package elasticache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	"github.com/mcastellin/aws-fail-az/awsapis_mocks"
+	"github.com/mcastellin/aws-fail-az/service/awsutils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func nodeGroup(id string, primaryAz string, replicaAz string) types.NodeGroup {
+	return types.NodeGroup{
+		NodeGroupId: aws.String(id),
+		NodeGroupMembers: []types.NodeGroupMember{
+			{CacheClusterId: aws.String(id + "-001"), CurrentRole: aws.String("primary"),
+				PreferredAvailabilityZone: aws.String(primaryAz)},
+			{CacheClusterId: aws.String(id + "-002"), CurrentRole: aws.String("replica"),
+				PreferredAvailabilityZone: aws.String(replicaAz)},
+		},
+	}
+}
+
+func describeOutput(nodeGroups ...types.NodeGroup) *elasticache.DescribeReplicationGroupsOutput {
+	return &elasticache.DescribeReplicationGroupsOutput{
+		ReplicationGroups: []types.ReplicationGroup{{
+			ReplicationGroupId: aws.String("test-rg"),
+			Status:             aws.String("available"),
+			AutomaticFailover:  types.AutomaticFailoverStatusEnabled,
+			NodeGroups:         nodeGroups,
+		}},
+	}
+}
+
+// Returns a copy of the node group with the primary and replica roles swapped
+func failedOver(nodeGroup types.NodeGroup) types.NodeGroup {
+	members := []types.NodeGroupMember{}
+	for _, member := range nodeGroup.NodeGroupMembers {
+		if aws.ToString(member.CurrentRole) == "primary" {
+			member.CurrentRole = aws.String("replica")
+		} else {
+			member.CurrentRole = aws.String("primary")
+		}
+		members = append(members, member)
+	}
+	nodeGroup.NodeGroupMembers = members
+	return nodeGroup
+}
+
+func TestFailShouldFailOverOneNodeGroupAtATime(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+	awsutils.WaitInterval = time.Millisecond
+
+	mockApi := awsapis_mocks.NewMockElastiCacheApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewElastiCacheApi().AnyTimes().Return(mockApi)
+
+	first := nodeGroup("0001", "us-east-1a", "us-east-1b")
+	second := nodeGroup("0002", "us-east-1b", "us-east-1a")
+	third := nodeGroup("0003", "us-east-1a", "us-east-1c")
+	modifying := describeOutput(failedOver(first), second, third)
+	modifying.ReplicationGroups[0].Status = aws.String("modifying")
+
+	gomock.InOrder(
+		mockApi.EXPECT().DescribeReplicationGroups(gomock.Any(), gomock.Any()).Times(1).
+			Return(describeOutput(first, second, third), nil),
+		mockApi.EXPECT().TestFailover(gomock.Any(), &elasticache.TestFailoverInput{
+			ReplicationGroupId: aws.String("test-rg"),
+			NodeGroupId:        aws.String("0001"),
+		}).Times(1).Return(&elasticache.TestFailoverOutput{}, nil),
+		mockApi.EXPECT().DescribeReplicationGroups(gomock.Any(), gomock.Any()).Times(1).
+			Return(describeOutput(first, second, third), nil),
+		mockApi.EXPECT().DescribeReplicationGroups(gomock.Any(), gomock.Any()).Times(1).
+			Return(modifying, nil),
+		mockApi.EXPECT().DescribeReplicationGroups(gomock.Any(), gomock.Any()).Times(1).
+			Return(describeOutput(failedOver(first), second, third), nil),
+		mockApi.EXPECT().TestFailover(gomock.Any(), &elasticache.TestFailoverInput{
+			ReplicationGroupId: aws.String("test-rg"),
+			NodeGroupId:        aws.String("0003"),
+		}).Times(1).Return(&elasticache.TestFailoverOutput{}, nil),
+		mockApi.EXPECT().DescribeReplicationGroups(gomock.Any(), gomock.Any()).Times(1).
+			Return(describeOutput(failedOver(first), second, failedOver(third)), nil),
+	)
+
+	err := (&ReplicationGroup{Provider: mockProvider, ReplicationGroupId: "test-rg"}).
+		Fail(context.TODO(), []string{"us-east-1a"})
+
+	assert.Nil(t, err)
+}
+
+func TestPlanShouldReturnSubnetsOfNodeAzs(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockElastiCacheApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewElastiCacheApi().AnyTimes().Return(mockApi)
+
+	output := describeOutput(nodeGroup("0001", "us-east-1a", "us-east-1b"))
+	output.ReplicationGroups[0].MemberClusters = []string{"0001-001", "0001-002"}
+	mockApi.EXPECT().DescribeReplicationGroups(gomock.Any(), gomock.Any()).Times(1).Return(output, nil)
+	mockApi.EXPECT().DescribeCacheClusters(gomock.Any(), &elasticache.DescribeCacheClustersInput{
+		CacheClusterId: aws.String("0001-001"),
+	}).Times(1).Return(&elasticache.DescribeCacheClustersOutput{
+		CacheClusters: []types.CacheCluster{{CacheSubnetGroupName: aws.String("test-subnets")}},
+	}, nil)
+	subnet := func(id string, az string) types.Subnet {
+		return types.Subnet{SubnetIdentifier: aws.String(id), SubnetAvailabilityZone: &types.AvailabilityZone{Name: aws.String(az)}}
+	}
+	mockApi.EXPECT().DescribeCacheSubnetGroups(gomock.Any(), &elasticache.DescribeCacheSubnetGroupsInput{
+		CacheSubnetGroupName: aws.String("test-subnets"),
+	}).Times(1).Return(&elasticache.DescribeCacheSubnetGroupsOutput{
+		CacheSubnetGroups: []types.CacheSubnetGroup{{Subnets: []types.Subnet{
+			subnet("s-a", "us-east-1a"), subnet("s-b", "us-east-1b"), subnet("s-c", "us-east-1c"),
+		}}},
+	}, nil)
+
+	plan, err := (&ReplicationGroup{Provider: mockProvider, ReplicationGroupId: "test-rg"}).
+		Plan(context.TODO(), []string{"us-east-1a"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"s-a", "s-b"}, plan.CurrentSubnets)
+	assert.Equal(t, []string{"s-b"}, plan.NewSubnets)
+	assert.Equal(t, []string{"0001-001"}, plan.Terminations)
+}
+
+func TestFailShouldSkipWhenNoPrimaryInFailedAzs(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockElastiCacheApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewElastiCacheApi().AnyTimes().Return(mockApi)
+
+	mockApi.EXPECT().DescribeReplicationGroups(gomock.Any(), gomock.Any()).Times(1).
+		Return(describeOutput(nodeGroup("0001", "us-east-1b", "us-east-1a")), nil)
+	mockApi.EXPECT().TestFailover(gomock.Any(), gomock.Any()).Times(0)
+
+	err := (&ReplicationGroup{Provider: mockProvider, ReplicationGroupId: "test-rg"}).
+		Fail(context.TODO(), []string{"us-east-1a"})
+
+	assert.Nil(t, err)
+}
+
+func TestVerifySteadyStateShouldWaitForPrimaryToLeaveFailedAzs(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockElastiCacheApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewElastiCacheApi().AnyTimes().Return(mockApi)
+
+	mockApi.EXPECT().DescribeReplicationGroups(gomock.Any(), gomock.Any()).Times(1).
+		Return(describeOutput(nodeGroup("0001", "us-east-1a", "us-east-1b")), nil)
+
+	result, err := (&ReplicationGroup{Provider: mockProvider, ReplicationGroupId: "test-rg"}).
+		VerifySteadyState(context.TODO(), []string{"us-east-1a"})
+
+	assert.NotNil(t, err)
+	assert.False(t, result)
+}
+
+func TestRestoreShouldWaitForGroupToBeAvailable(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+	awsutils.WaitInterval = time.Millisecond
+
+	mockApi := awsapis_mocks.NewMockElastiCacheApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewElastiCacheApi().AnyTimes().Return(mockApi)
+
+	modifying := describeOutput(failedOver(nodeGroup("0001", "us-east-1a", "us-east-1b")))
+	modifying.ReplicationGroups[0].Status = aws.String("modifying")
+	gomock.InOrder(
+		mockApi.EXPECT().DescribeReplicationGroups(gomock.Any(), gomock.Any()).Times(2).Return(modifying, nil),
+		mockApi.EXPECT().DescribeReplicationGroups(gomock.Any(), gomock.Any()).Times(1).
+			Return(describeOutput(failedOver(nodeGroup("0001", "us-east-1a", "us-east-1b"))), nil),
+	)
+
+	resource, err := NewReplicationGroupFromState(
+		[]byte(`{"replicationGroupId":"test-rg","primaries":{"0001":"0001-001"}}`), mockProvider)
+	assert.Nil(t, err)
+
+	err = resource.Restore(context.TODO())
+
+	assert.Nil(t, err)
+}
@@ -0,0 +1,108 @@
+package elasticache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	"github.com/mcastellin/aws-fail-az/awsapis"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/service/awsutils"
+)
+
+func NewReplicationGroupFromState(stateData []byte, provider awsapis.AWSProvider) (domain.ConsistentStateResource, error) {
+	var state ReplicationGroupState
+	err := json.Unmarshal(stateData, &state)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := &ReplicationGroup{
+		Provider:           provider,
+		ReplicationGroupId: state.ReplicationGroupId,
+		statePrimaries:     state.Primaries,
+	}
+	return resource, nil
+}
+
+func NewReplicationGroupFaultFromConfig(ctx context.Context, selector domain.TargetSelector, provider awsapis.AWSProvider) ([]domain.ConsistentStateResource, error) {
+
+	if selector.Type != domain.ResourceTypeElastiCacheGroup {
+		return nil, fmt.Errorf("Unable to create ReplicationGroup object from selector of type %s.", selector.Type)
+	}
+
+	var groupIds []string
+	var err error
+
+	err = selector.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := awsutils.TokenizeResourceFilter(selector.Filter, []string{"id"})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(attributes) == 1 {
+		groupIds = []string{attributes["id"]}
+	} else if len(selector.Tags) > 0 {
+		api := provider.NewElastiCacheApi()
+
+		groupIds, err = filterReplicationGroupsByTags(ctx, api, selector.Tags)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	objs := make([]domain.ConsistentStateResource, len(groupIds))
+	for idx := range groupIds {
+		objs[idx] = &ReplicationGroup{
+			Provider:           provider,
+			ReplicationGroupId: groupIds[idx],
+		}
+	}
+
+	return objs, nil
+}
+
+func filterReplicationGroupsByTags(ctx context.Context, api awsapis.ElastiCacheApi, tags []domain.AWSTag) ([]string, error) {
+	groupIds := []string{}
+
+	paginator := api.NewDescribeReplicationGroupsPaginator(&elasticache.DescribeReplicationGroupsInput{})
+	for paginator.HasMorePages() {
+		response, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range response.ReplicationGroups {
+			tagsOutput, err := api.ListTagsForResource(ctx,
+				&elasticache.ListTagsForResourceInput{ResourceName: group.ARN})
+			if err != nil {
+				return nil, err
+			}
+			if resourceTagsMatchFilters(tagsOutput.TagList, tags) {
+				groupIds = append(groupIds, *group.ReplicationGroupId)
+			}
+		}
+	}
+
+	return groupIds, nil
+}
+
+func resourceTagsMatchFilters(resourceTags []types.Tag, filterTags []domain.AWSTag) bool {
+	allMatch := len(resourceTags) >= len(filterTags)
+	for _, filterTag := range filterTags {
+		match := false
+		for _, resourceTag := range resourceTags {
+			if *resourceTag.Key == filterTag.Name && *resourceTag.Value == filterTag.Value {
+				match = true
+			}
+		}
+		allMatch = allMatch && match
+	}
+	return allMatch
+}
@@ -0,0 +1,58 @@
+package elasticache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	"github.com/mcastellin/aws-fail-az/awsapis_mocks"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFilterReplicationGroupsByTagsShouldMatchResults(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	pager := awsapis_mocks.NewMockDescribeReplicationGroupsPager(ctrl)
+	gomock.InOrder(
+		pager.EXPECT().HasMorePages().Times(1).Return(true),
+		pager.EXPECT().HasMorePages().Times(1).Return(false),
+	)
+	pager.EXPECT().NextPage(gomock.Any()).Times(1).
+		Return(&elasticache.DescribeReplicationGroupsOutput{
+			ReplicationGroups: []types.ReplicationGroup{
+				{ReplicationGroupId: aws.String("rg-1"), ARN: aws.String("arn-1")},
+				{ReplicationGroupId: aws.String("rg-2"), ARN: aws.String("arn-2")},
+			},
+		}, nil)
+
+	mockApi := awsapis_mocks.NewMockElastiCacheApi(ctrl)
+	mockApi.EXPECT().NewDescribeReplicationGroupsPaginator(gomock.Any()).Times(1).Return(pager)
+	mockApi.EXPECT().ListTagsForResource(gomock.Any(), &elasticache.ListTagsForResourceInput{ResourceName: aws.String("arn-1")}).
+		Times(1).
+		Return(&elasticache.ListTagsForResourceOutput{TagList: []types.Tag{
+			{Key: aws.String("Environment"), Value: aws.String("staging")},
+		}}, nil)
+	mockApi.EXPECT().ListTagsForResource(gomock.Any(), &elasticache.ListTagsForResourceInput{ResourceName: aws.String("arn-2")}).
+		Times(1).
+		Return(&elasticache.ListTagsForResourceOutput{TagList: []types.Tag{
+			{Key: aws.String("Environment"), Value: aws.String("live")},
+		}}, nil)
+
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewElastiCacheApi().AnyTimes().Return(mockApi)
+
+	config := domain.TargetSelector{
+		Type: domain.ResourceTypeElastiCacheGroup,
+		Tags: []domain.AWSTag{{Name: "Environment", Value: "live"}},
+	}
+	results, err := NewReplicationGroupFaultFromConfig(context.TODO(), config, mockProvider)
+
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "rg-2", results[0].(*ReplicationGroup).ReplicationGroupId)
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -12,6 +12,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
 	"github.com/mcastellin/aws-fail-az/awsapis"
 	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
 	"github.com/mcastellin/aws-fail-az/service/awsutils"
 	"github.com/mcastellin/aws-fail-az/state"
 )
@@ -27,15 +28,26 @@ type LoadBalancer struct {
 	stateSubnets []string
 }
 
-func (lb *LoadBalancer) Check() (bool, error) {
-	log.Printf("%s name=%s: checking resource state before failure simulation",
-		domain.ResourceTypeElbv2LoadBalancer, lb.Name)
+func (lb *LoadBalancer) ResourceType() string {
+	return domain.ResourceTypeElbv2LoadBalancer
+}
+
+func (lb *LoadBalancer) ResourceKey() string {
+	return lb.Name
+}
+
+func (lb *LoadBalancer) logger() *slog.Logger {
+	return logging.ForResource(lb.ResourceType(), lb.ResourceKey())
+}
+
+func (lb *LoadBalancer) Check(ctx context.Context) (bool, error) {
+	lb.logger().Debug("Checking resource state before failure simulation")
 
 	api := lb.Provider.NewElbV2Api()
 
-	output, err := describeLoadBalancer(api, lb.Name)
+	output, err := describeLoadBalancer(ctx, api, lb.Name)
 	if err != nil {
-		return false, err
+		return false, awsutils.ClassifyError(err)
 	}
 	if len(output.LoadBalancers) == 0 {
 		return false, fmt.Errorf("Could not describe load balancer with name %s", lb.Name)
@@ -43,21 +55,22 @@ func (lb *LoadBalancer) Check() (bool, error) {
 	subnetIds := getLoadBalancerSubnets(output.LoadBalancers[0])
 
 	if len(subnetIds) <= 2 {
-		return false, fmt.Errorf("Insufficient number of subnets for resource %s."+
+		err := fmt.Errorf("Insufficient number of subnets for resource %s."+
 			" Load balancers require a minimum of 3 availability zones to simulate AZ failure, found %d.",
 			lb.Name, len(subnetIds))
+		return false, domain.ActivityFailedError{Wrap: err, Temporary: false}
 	}
 
 	return true, nil
 }
 
-func (lb *LoadBalancer) Save(stateManager state.StateManager) error {
+func (lb *LoadBalancer) Save(ctx context.Context, stateManager state.StateManager) error {
 
 	api := lb.Provider.NewElbV2Api()
 
-	describeOutput, err := describeLoadBalancer(api, lb.Name)
+	describeOutput, err := describeLoadBalancer(ctx, api, lb.Name)
 	if err != nil {
-		return err
+		return awsutils.ClassifyError(err)
 	}
 	if len(describeOutput.LoadBalancers) == 0 {
 		return fmt.Errorf("Could not describe load balancer with name %s", lb.Name)
@@ -71,72 +84,163 @@ func (lb *LoadBalancer) Save(stateManager state.StateManager) error {
 	}
 	data, err := json.Marshal(state)
 	if err != nil {
-		log.Println("Error while marshalling load balancer state")
+		lb.logger().Error("Error while marshalling load balancer state", logging.Err(err))
 		return err
 	}
-	err = stateManager.Save(domain.ResourceTypeElbv2LoadBalancer, lb.Name, data)
+	err = stateManager.Save(ctx, domain.ResourceTypeElbv2LoadBalancer, lb.Name, data)
+	if err != nil {
+		return awsutils.ClassifyError(err)
+	}
+	lb.stateSubnets = subnetIds
 
-	return err
+	return nil
 }
 
-func (lb *LoadBalancer) Fail(azs []string) error {
+func (lb *LoadBalancer) Fail(ctx context.Context, azs []string) error {
+	apply, err := lb.PrepareFail(ctx, azs)
+	if err != nil {
+		return err
+	}
+	return apply(ctx)
+}
+
+// Describes the load balancer and returns the function that removes its subnets in the failed AZs
+func (lb *LoadBalancer) PrepareFail(ctx context.Context, azs []string) (func(context.Context) error, error) {
 
 	api := lb.Provider.NewElbV2Api()
 	ec2Api := lb.Provider.NewEc2Api()
 
-	describeOutput, err := describeLoadBalancer(api, lb.Name)
+	describeOutput, err := describeLoadBalancer(ctx, api, lb.Name)
 	if err != nil {
-		return err
+		return nil, awsutils.ClassifyError(err)
 	}
 	if len(describeOutput.LoadBalancers) == 0 {
-		return fmt.Errorf("Could not describe load balancer with name %s", lb.Name)
+		return nil, fmt.Errorf("Could not describe load balancer with name %s", lb.Name)
 	}
 	loadBalancerDescriptor := describeOutput.LoadBalancers[0]
 	subnetIds := getLoadBalancerSubnets(loadBalancerDescriptor)
 
-	newSubnets, err := awsutils.FilterSubnetsNotInAzs(ec2Api, subnetIds, azs)
+	newSubnets, err := awsutils.FilterSubnetsNotInAzs(ctx, ec2Api, subnetIds, azs)
 	if err != nil {
-		log.Printf("Error while filtering subnets by AZs: %v", err)
-		return err
+		lb.logger().Error("Error while filtering subnets by AZs", logging.Err(err))
+		return nil, awsutils.ClassifyError(err)
 	}
 	if len(newSubnets) <= 1 {
-		return fmt.Errorf("AZ failure for load-balancer %s would remove all but one subnets."+
+		return nil, fmt.Errorf("AZ failure for load-balancer %s would remove all but one subnets."+
 			" Load balancers require at least 2 availability zones. AZ failure will now stop", lb.Name)
 	}
 
-	log.Printf("%s name=%s: failing AZs %s for load-balancer", domain.ResourceTypeElbv2LoadBalancer, lb.Name, azs)
+	return func(ctx context.Context) error {
+		lb.logger().Info("Failing AZs for load-balancer", "azs", azs)
 
-	_, err = api.SetSubnets(context.TODO(), &elasticloadbalancingv2.SetSubnetsInput{
-		LoadBalancerArn: loadBalancerDescriptor.LoadBalancerArn,
-		Subnets:         newSubnets,
-	})
+		_, err := api.SetSubnets(ctx, &elasticloadbalancingv2.SetSubnetsInput{
+			LoadBalancerArn: loadBalancerDescriptor.LoadBalancerArn,
+			Subnets:         newSubnets,
+		})
+
+		return awsutils.ClassifyError(err)
+	}, nil
+}
+
+func (lb *LoadBalancer) Plan(ctx context.Context, azs []string) (*domain.FailurePlan, error) {
+
+	api := lb.Provider.NewElbV2Api()
+	ec2Api := lb.Provider.NewEc2Api()
+
+	describeOutput, err := describeLoadBalancer(ctx, api, lb.Name)
+	if err != nil {
+		return nil, awsutils.ClassifyError(err)
+	}
+	if len(describeOutput.LoadBalancers) == 0 {
+		return nil, fmt.Errorf("Could not describe load balancer with name %s", lb.Name)
+	}
+	subnetIds := getLoadBalancerSubnets(describeOutput.LoadBalancers[0])
+
+	newSubnets, err := awsutils.FilterSubnetsNotInAzs(ctx, ec2Api, subnetIds, azs)
+	if err != nil {
+		return nil, awsutils.ClassifyError(err)
+	}
+	if len(newSubnets) <= 1 {
+		return nil, fmt.Errorf("AZ failure for load-balancer %s would remove all but one subnets."+
+			" Load balancers require at least 2 availability zones", lb.Name)
+	}
+
+	return &domain.FailurePlan{
+		ResourceType:   domain.ResourceTypeElbv2LoadBalancer,
+		ResourceKey:    lb.ResourceKey(),
+		CurrentSubnets: subnetIds,
+		NewSubnets:     newSubnets,
+		Terminations:   []string{},
+	}, nil
+}
+
+// Verifies the load balancer is active after AZ failure
+func (lb *LoadBalancer) VerifySteadyState(ctx context.Context, azs []string) (bool, error) {
+	api := lb.Provider.NewElbV2Api()
+
+	describeOutput, err := describeLoadBalancer(ctx, api, lb.Name)
+	if err != nil {
+		return false, awsutils.ClassifyError(err)
+	}
+	if len(describeOutput.LoadBalancers) == 0 {
+		return false, fmt.Errorf("Could not describe load balancer with name %s", lb.Name)
+	}
+
+	lbState := describeOutput.LoadBalancers[0].State
+	if lbState == nil || lbState.Code != types.LoadBalancerStateEnumActive {
+		code := "unknown"
+		if lbState != nil {
+			code = string(lbState.Code)
+		}
+		return false, fmt.Errorf("Load balancer %s is not active. Found state %s.", lb.Name, code)
+	}
 
-	return err
+	return true, nil
 }
 
-func (lb *LoadBalancer) Restore() error {
+func (lb *LoadBalancer) Restore(ctx context.Context) error {
 
-	log.Printf("%s name=%s: restoring AZs for load-balancer", domain.ResourceTypeElbv2LoadBalancer, lb.Name)
+	lb.logger().Info("Restoring AZs for load-balancer")
 
 	api := lb.Provider.NewElbV2Api()
 
 	arn := aws.String(lb.Name)
 	if !strings.HasPrefix(*arn, "arn:") {
-		out, err := describeLoadBalancer(api, lb.Name)
+		out, err := describeLoadBalancer(ctx, api, lb.Name)
 		if err != nil {
-			return err
+			return awsutils.ClassifyError(err)
 		}
 		arn = out.LoadBalancers[0].LoadBalancerArn
 	}
 
-	_, err := api.SetSubnets(context.TODO(), &elasticloadbalancingv2.SetSubnetsInput{
+	_, err := api.SetSubnets(ctx, &elasticloadbalancingv2.SetSubnetsInput{
 		LoadBalancerArn: arn,
 		Subnets:         lb.stateSubnets,
 	})
-	return err
+	return awsutils.ClassifyError(err)
+}
+
+// Verifies the load balancer subnets match the subnets saved in state
+func (lb *LoadBalancer) VerifyRestored(ctx context.Context) (bool, error) {
+	api := lb.Provider.NewElbV2Api()
+
+	describeOutput, err := describeLoadBalancer(ctx, api, lb.Name)
+	if err != nil {
+		return false, awsutils.ClassifyError(err)
+	}
+	if len(describeOutput.LoadBalancers) == 0 {
+		return false, fmt.Errorf("Could not describe load balancer with name %s", lb.Name)
+	}
+
+	subnetIds := getLoadBalancerSubnets(describeOutput.LoadBalancers[0])
+	if !awsutils.SameSubnets(subnetIds, lb.stateSubnets) {
+		return false, fmt.Errorf("Load balancer %s subnets %s do not match saved subnets %s",
+			lb.Name, subnetIds, lb.stateSubnets)
+	}
+	return true, nil
 }
 
-func describeLoadBalancer(api awsapis.ElbV2LoadBalancersDescriptor, name string) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+func describeLoadBalancer(ctx context.Context, api awsapis.ElbV2LoadBalancersDescriptor, name string) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
 
 	input := &elasticloadbalancingv2.DescribeLoadBalancersInput{}
 	if strings.HasPrefix(name, "arn:") {
@@ -145,7 +249,7 @@ func describeLoadBalancer(api awsapis.ElbV2LoadBalancersDescriptor, name string)
 		input.Names = []string{name}
 	}
 
-	return api.DescribeLoadBalancers(context.TODO(), input)
+	return api.DescribeLoadBalancers(ctx, input)
 }
 
 func getLoadBalancerSubnets(descriptor types.LoadBalancer) []string {
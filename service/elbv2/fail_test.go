@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
 	"github.com/mcastellin/aws-fail-az/awsapis_mocks"
@@ -35,7 +37,7 @@ func TestCheckFailNotEnoughSubnets(t *testing.T) {
 	result, err := (&LoadBalancer{
 		Provider: mockProvider,
 		Name:     "test-alb",
-	}).Check()
+	}).Check(context.TODO())
 
 	assert.NotNil(t, err)
 	assert.False(t, result)
@@ -63,7 +65,7 @@ func TestCheckPass(t *testing.T) {
 	result, err := (&LoadBalancer{
 		Provider: mockProvider,
 		Name:     "test-alb",
-	}).Check()
+	}).Check(context.TODO())
 
 	assert.Nil(t, err)
 	assert.True(t, result)
@@ -96,7 +98,7 @@ func TestDescribeLoadBalancerWithArn(t *testing.T) {
 	result, err := (&LoadBalancer{
 		Provider: mockProvider,
 		Name:     arn,
-	}).Check()
+	}).Check(context.TODO())
 
 	assert.Nil(t, err)
 	assert.True(t, result)
@@ -127,7 +129,7 @@ func TestDescribeLoadBalancerWithName(t *testing.T) {
 	result, err := (&LoadBalancer{
 		Provider: mockProvider,
 		Name:     "alb-name",
-	}).Check()
+	}).Check(context.TODO())
 
 	assert.Nil(t, err)
 	assert.True(t, result)
@@ -148,3 +150,73 @@ func (m describeLoadBalancersMatcher) Matches(x interface{}) bool {
 func (m describeLoadBalancersMatcher) String() string {
 	return fmt.Sprintf("%v", m.x)
 }
+
+func TestPlanShouldNotModifyLoadBalancer(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockElbV2Api(ctrl)
+	mockEc2Api := awsapis_mocks.NewMockEc2Api(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewElbV2Api().AnyTimes().Return(mockApi)
+	mockProvider.EXPECT().NewEc2Api().AnyTimes().Return(mockEc2Api)
+
+	mockApi.EXPECT().DescribeLoadBalancers(gomock.Any(), gomock.Any()).Times(1).
+		Return(&elasticloadbalancingv2.DescribeLoadBalancersOutput{
+			LoadBalancers: []types.LoadBalancer{{
+				AvailabilityZones: []types.AvailabilityZone{
+					{SubnetId: aws.String("s-1111")},
+					{SubnetId: aws.String("s-2222")},
+					{SubnetId: aws.String("s-3333")},
+				},
+			}},
+		}, nil)
+	mockApi.EXPECT().SetSubnets(gomock.Any(), gomock.Any()).Times(0)
+
+	mockEc2Api.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any()).Times(1).
+		Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []ec2Types.Subnet{
+				{SubnetId: aws.String("s-1111"), AvailabilityZone: aws.String("us-east-1a")},
+				{SubnetId: aws.String("s-2222"), AvailabilityZone: aws.String("us-east-1b")},
+				{SubnetId: aws.String("s-3333"), AvailabilityZone: aws.String("us-east-1c")},
+			},
+		}, nil)
+
+	plan, err := (&LoadBalancer{
+		Provider: mockProvider,
+		Name:     "test-alb",
+	}).Plan(context.TODO(), []string{"us-east-1a"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "test-alb", plan.ResourceKey)
+	assert.Equal(t, []string{"s-1111", "s-2222", "s-3333"}, plan.CurrentSubnets)
+	assert.Equal(t, []string{"s-2222", "s-3333"}, plan.NewSubnets)
+}
+
+func TestVerifyRestoredShouldFailWhenSubnetsDiffer(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockElbV2Api(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewElbV2Api().AnyTimes().Return(mockApi)
+
+	mockApi.EXPECT().DescribeLoadBalancers(gomock.Any(), gomock.Any()).Times(1).
+		Return(&elasticloadbalancingv2.DescribeLoadBalancersOutput{
+			LoadBalancers: []types.LoadBalancer{{
+				AvailabilityZones: []types.AvailabilityZone{
+					{SubnetId: aws.String("s-2222")},
+					{SubnetId: aws.String("s-3333")},
+				},
+			}},
+		}, nil)
+
+	result, err := (&LoadBalancer{
+		Provider:     mockProvider,
+		Name:         "test-alb",
+		stateSubnets: []string{"s-1111", "s-2222", "s-3333"},
+	}).VerifyRestored(context.TODO())
+
+	assert.NotNil(t, err)
+	assert.False(t, result)
+}
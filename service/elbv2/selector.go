@@ -12,22 +12,22 @@ import (
 	"github.com/mcastellin/aws-fail-az/service/awsutils"
 )
 
-func RestoreElbv2LoadBalancersFromState(stateData []byte, provider awsapis.AWSProvider) error {
+func NewElbv2LoadBalancerFromState(stateData []byte, provider awsapis.AWSProvider) (domain.ConsistentStateResource, error) {
 	var state LoadBalancerState
 	err := json.Unmarshal(stateData, &state)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	resource := LoadBalancer{
+	resource := &LoadBalancer{
 		Provider:     provider,
 		Name:         state.LoadBalancerName,
 		stateSubnets: state.Subnets,
 	}
-	return resource.Restore()
+	return resource, nil
 }
 
-func NewElbv2LoadBalancerFaultFromConfig(selector domain.TargetSelector, provider awsapis.AWSProvider) ([]domain.ConsistentStateResource, error) {
+func NewElbv2LoadBalancerFaultFromConfig(ctx context.Context, selector domain.TargetSelector, provider awsapis.AWSProvider) ([]domain.ConsistentStateResource, error) {
 
 	if selector.Type != domain.ResourceTypeElbv2LoadBalancer {
 		return nil, fmt.Errorf("Unable to create LoadBalancer object from selector of type %s.", selector.Type)
@@ -51,7 +51,7 @@ func NewElbv2LoadBalancerFaultFromConfig(selector domain.TargetSelector, provide
 	} else if len(selector.Tags) > 0 {
 		api := provider.NewElbV2Api()
 
-		lbNames, err = filterLoadBalancersByTag(api, selector.Tags)
+		lbNames, err = filterLoadBalancersByTag(ctx, api, selector.Tags)
 		if err != nil {
 			return nil, err
 		}
@@ -68,14 +68,14 @@ func NewElbv2LoadBalancerFaultFromConfig(selector domain.TargetSelector, provide
 	return objs, nil
 }
 
-func filterLoadBalancersByTag(api awsapis.ElbV2Api, tags []domain.AWSTag) ([]string, error) {
+func filterLoadBalancersByTag(ctx context.Context, api awsapis.ElbV2Api, tags []domain.AWSTag) ([]string, error) {
 	lbNames := []string{}
 
 	paginator := api.NewDescribeLoadBalancersPaginator(
 		&elasticloadbalancingv2.DescribeLoadBalancersInput{})
 
 	for paginator.HasMorePages() {
-		response, err := paginator.NextPage(context.TODO())
+		response, err := paginator.NextPage(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -88,7 +88,7 @@ func filterLoadBalancersByTag(api awsapis.ElbV2Api, tags []domain.AWSTag) ([]str
 			resourceArns[idx] = *lb.LoadBalancerArn
 		}
 
-		describeTagsOutput, err := api.DescribeTags(context.TODO(),
+		describeTagsOutput, err := api.DescribeTags(ctx,
 			&elasticloadbalancingv2.DescribeTagsInput{ResourceArns: resourceArns})
 		if err != nil {
 			return nil, err
@@ -88,7 +88,7 @@ func TestFilterLoadBalancersByTagsShouldMatchInAllPages(t *testing.T) {
 		Type: domain.ResourceTypeElbv2LoadBalancer,
 		Tags: []domain.AWSTag{{Name: "Environment", Value: "live"}, {Name: "Application", Value: "test"}},
 	}
-	results, err := NewElbv2LoadBalancerFaultFromConfig(config, mockProvider)
+	results, err := NewElbv2LoadBalancerFaultFromConfig(context.TODO(), config, mockProvider)
 
 	assert.Nil(t, err)
 	assert.Len(t, results, 3)
@@ -0,0 +1,259 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/mcastellin/aws-fail-az/awsapis"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
+	"github.com/mcastellin/aws-fail-az/service/awsutils"
+	"github.com/mcastellin/aws-fail-az/state"
+)
+
+type LambdaFunctionState struct {
+	FunctionName string   `json:"functionName"`
+	Subnets      []string `json:"subnets"`
+}
+
+type LambdaFunction struct {
+	Provider     awsapis.AWSProvider
+	FunctionName string
+
+	stateSubnets []string
+}
+
+func (fn *LambdaFunction) ResourceType() string {
+	return domain.ResourceTypeLambdaFunction
+}
+
+func (fn *LambdaFunction) ResourceKey() string {
+	return fn.FunctionName
+}
+
+func (fn *LambdaFunction) logger() *slog.Logger {
+	return logging.ForResource(fn.ResourceType(), fn.ResourceKey())
+}
+
+func (fn *LambdaFunction) Check(ctx context.Context) (bool, error) {
+	fn.logger().Debug("Checking resource state before failure simulation")
+
+	config, err := getFunctionConfiguration(ctx, fn.Provider.NewLambdaApi(), fn.FunctionName)
+	if err != nil {
+		return false, err
+	}
+
+	if len(functionSubnets(config)) == 0 {
+		err := fmt.Errorf("Lambda function %s is not connected to a VPC", fn.FunctionName)
+		return false, domain.ActivityFailedError{Wrap: err, Temporary: false}
+	}
+	if config.LastUpdateStatus != types.LastUpdateStatusSuccessful {
+		return false, fmt.Errorf("Last update of Lambda function %s is not successful. Found status %s.",
+			fn.FunctionName, config.LastUpdateStatus)
+	}
+
+	return true, nil
+}
+
+func (fn *LambdaFunction) Save(ctx context.Context, stateManager state.StateManager) error {
+
+	config, err := getFunctionConfiguration(ctx, fn.Provider.NewLambdaApi(), fn.FunctionName)
+	if err != nil {
+		return err
+	}
+	subnets := functionSubnets(config)
+
+	state := &LambdaFunctionState{
+		FunctionName: fn.FunctionName,
+		Subnets:      subnets,
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		fn.logger().Error("Error while marshalling Lambda function state", logging.Err(err))
+		return err
+	}
+	err = stateManager.Save(ctx, domain.ResourceTypeLambdaFunction, fn.FunctionName, data)
+	if err != nil {
+		return awsutils.ClassifyError(err)
+	}
+	fn.stateSubnets = subnets
+
+	return nil
+}
+
+func (fn *LambdaFunction) Fail(ctx context.Context, azs []string) error {
+	ec2Api := fn.Provider.NewEc2Api()
+	api := fn.Provider.NewLambdaApi()
+
+	config, err := getFunctionConfiguration(ctx, api, fn.FunctionName)
+	if err != nil {
+		return err
+	}
+	subnets := functionSubnets(config)
+
+	newSubnets, err := awsutils.FilterSubnetsNotInAzs(ctx, ec2Api, subnets, azs)
+	if err != nil {
+		fn.logger().Error("Error while filtering subnets by AZs", logging.Err(err))
+		return awsutils.ClassifyError(err)
+	}
+
+	if len(newSubnets) == 0 {
+		return fmt.Errorf("AZ failure for Lambda function %s would remove all available subnets. Function failure will now stop", fn.FunctionName)
+	}
+
+	fn.logger().Info("Failing AZs for lambda-function", "azs", azs)
+
+	err = updateFunctionSubnets(ctx, api, config, newSubnets)
+	if err != nil {
+		return err
+	}
+	return waitForSuccessfulUpdate(ctx, api, fn.FunctionName)
+}
+
+func (fn *LambdaFunction) Plan(ctx context.Context, azs []string) (*domain.FailurePlan, error) {
+	ec2Api := fn.Provider.NewEc2Api()
+
+	config, err := getFunctionConfiguration(ctx, fn.Provider.NewLambdaApi(), fn.FunctionName)
+	if err != nil {
+		return nil, err
+	}
+	subnets := functionSubnets(config)
+
+	newSubnets, err := awsutils.FilterSubnetsNotInAzs(ctx, ec2Api, subnets, azs)
+	if err != nil {
+		return nil, awsutils.ClassifyError(err)
+	}
+
+	if len(newSubnets) == 0 {
+		return nil, fmt.Errorf("AZ failure for Lambda function %s would remove all available subnets", fn.FunctionName)
+	}
+
+	return &domain.FailurePlan{
+		ResourceType:   domain.ResourceTypeLambdaFunction,
+		ResourceKey:    fn.ResourceKey(),
+		CurrentSubnets: subnets,
+		NewSubnets:     newSubnets,
+		Terminations:   []string{},
+	}, nil
+}
+
+// Verifies the update of the function subnets has completed successfully
+func (fn *LambdaFunction) VerifySteadyState(ctx context.Context, azs []string) (bool, error) {
+	config, err := getFunctionConfiguration(ctx, fn.Provider.NewLambdaApi(), fn.FunctionName)
+	if err != nil {
+		return false, err
+	}
+
+	if config.LastUpdateStatus != types.LastUpdateStatusSuccessful {
+		return false, fmt.Errorf("Last update of Lambda function %s is not successful. Found status %s.",
+			fn.FunctionName, config.LastUpdateStatus)
+	}
+	return true, nil
+}
+
+func (fn *LambdaFunction) Restore(ctx context.Context) error {
+	fn.logger().Info("Restoring AZs for lambda-function")
+
+	api := fn.Provider.NewLambdaApi()
+
+	// The function can only be updated once the update applied by AZ failure has completed
+	config, err := waitForUpdate(ctx, api, fn.FunctionName)
+	if err != nil {
+		return err
+	}
+
+	err = updateFunctionSubnets(ctx, api, config, fn.stateSubnets)
+	if err != nil {
+		return err
+	}
+	return waitForSuccessfulUpdate(ctx, api, fn.FunctionName)
+}
+
+// Verifies the function subnets match the subnets saved in state once the update has completed
+func (fn *LambdaFunction) VerifyRestored(ctx context.Context) (bool, error) {
+	config, err := getFunctionConfiguration(ctx, fn.Provider.NewLambdaApi(), fn.FunctionName)
+	if err != nil {
+		return false, err
+	}
+
+	if config.LastUpdateStatus != types.LastUpdateStatusSuccessful {
+		return false, fmt.Errorf("Last update of Lambda function %s is not successful. Found status %s.",
+			fn.FunctionName, config.LastUpdateStatus)
+	}
+
+	subnets := functionSubnets(config)
+	if !awsutils.SameSubnets(subnets, fn.stateSubnets) {
+		return false, fmt.Errorf("Lambda function %s subnets %s do not match saved subnets %s",
+			fn.FunctionName, subnets, fn.stateSubnets)
+	}
+	return true, nil
+}
+
+// Waits until no update of the function is in progress and returns the function configuration
+func waitForUpdate(ctx context.Context, api awsapis.LambdaFunctionConfigurationGetter, name string) (*lambda.GetFunctionConfigurationOutput, error) {
+	var config *lambda.GetFunctionConfigurationOutput
+	err := awsutils.WaitUntil(ctx, awsutils.DEFAULT_WAIT_TIMEOUT, "function update completed", func() (bool, error) {
+		var err error
+		config, err = getFunctionConfiguration(ctx, api, name)
+		if err != nil {
+			return false, err
+		}
+		return config.LastUpdateStatus != types.LastUpdateStatusInProgress, nil
+	})
+	return config, err
+}
+
+// Waits until the last update of the function has completed.
+// Returns a non-temporary error if the update failed
+func waitForSuccessfulUpdate(ctx context.Context, api awsapis.LambdaFunctionConfigurationGetter, name string) error {
+	config, err := waitForUpdate(ctx, api, name)
+	if err != nil {
+		return err
+	}
+	if config.LastUpdateStatus == types.LastUpdateStatusFailed {
+		err := fmt.Errorf("Update of Lambda function %s failed: %s", name, aws.ToString(config.LastUpdateStatusReason))
+		return domain.ActivityFailedError{Wrap: err, Temporary: false}
+	}
+	return nil
+}
+
+func getFunctionConfiguration(ctx context.Context, api awsapis.LambdaFunctionConfigurationGetter, name string) (*lambda.GetFunctionConfigurationOutput, error) {
+	output, err := api.GetFunctionConfiguration(ctx, &lambda.GetFunctionConfigurationInput{
+		FunctionName: aws.String(name),
+	})
+	if err != nil {
+		return nil, awsutils.ClassifyError(err)
+	}
+	return output, nil
+}
+
+// Updates the function subnets, keeping its security groups
+func updateFunctionSubnets(ctx context.Context, api awsapis.LambdaFunctionConfigurationUpdater,
+	config *lambda.GetFunctionConfigurationOutput, subnets []string) error {
+
+	securityGroups := []string{}
+	if config.VpcConfig != nil {
+		securityGroups = config.VpcConfig.SecurityGroupIds
+	}
+
+	_, err := api.UpdateFunctionConfiguration(ctx, &lambda.UpdateFunctionConfigurationInput{
+		FunctionName: config.FunctionName,
+		VpcConfig: &types.VpcConfig{
+			SubnetIds:        subnets,
+			SecurityGroupIds: securityGroups,
+		},
+	})
+	return awsutils.ClassifyError(err)
+}
+
+func functionSubnets(config *lambda.GetFunctionConfigurationOutput) []string {
+	if config.VpcConfig == nil {
+		return []string{}
+	}
+	return config.VpcConfig.SubnetIds
+}
@@ -0,0 +1,187 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/mcastellin/aws-fail-az/awsapis_mocks"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/service/awsutils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func functionConfiguration(status types.LastUpdateStatus, subnets ...string) *lambda.GetFunctionConfigurationOutput {
+	return &lambda.GetFunctionConfigurationOutput{
+		FunctionName:     aws.String("test-function"),
+		LastUpdateStatus: status,
+		VpcConfig: &types.VpcConfigResponse{
+			SubnetIds:        subnets,
+			SecurityGroupIds: []string{"sg-1"},
+		},
+	}
+}
+
+func TestFailShouldRemoveSubnetsInFailedAzs(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+	awsutils.WaitInterval = time.Millisecond
+
+	mockApi := awsapis_mocks.NewMockLambdaApi(ctrl)
+	mockEc2Api := awsapis_mocks.NewMockEc2Api(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewLambdaApi().AnyTimes().Return(mockApi)
+	mockProvider.EXPECT().NewEc2Api().AnyTimes().Return(mockEc2Api)
+
+	mockEc2Api.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []ec2types.Subnet{
+				{SubnetId: aws.String("s-1"), AvailabilityZone: aws.String("us-east-1a")},
+				{SubnetId: aws.String("s-2"), AvailabilityZone: aws.String("us-east-1b")},
+			},
+		}, nil)
+	gomock.InOrder(
+		mockApi.EXPECT().GetFunctionConfiguration(gomock.Any(), gomock.Any()).Times(1).
+			Return(functionConfiguration(types.LastUpdateStatusSuccessful, "s-1", "s-2"), nil),
+		mockApi.EXPECT().UpdateFunctionConfiguration(gomock.Any(), &lambda.UpdateFunctionConfigurationInput{
+			FunctionName: aws.String("test-function"),
+			VpcConfig: &types.VpcConfig{
+				SubnetIds:        []string{"s-2"},
+				SecurityGroupIds: []string{"sg-1"},
+			},
+		}).Times(1).Return(&lambda.UpdateFunctionConfigurationOutput{}, nil),
+		mockApi.EXPECT().GetFunctionConfiguration(gomock.Any(), gomock.Any()).Times(1).
+			Return(functionConfiguration(types.LastUpdateStatusInProgress, "s-1", "s-2"), nil),
+		mockApi.EXPECT().GetFunctionConfiguration(gomock.Any(), gomock.Any()).Times(1).
+			Return(functionConfiguration(types.LastUpdateStatusSuccessful, "s-2"), nil),
+	)
+
+	err := (&LambdaFunction{Provider: mockProvider, FunctionName: "test-function"}).
+		Fail(context.TODO(), []string{"us-east-1a"})
+
+	assert.Nil(t, err)
+}
+
+func TestFailShouldReturnPermanentErrorWhenUpdateFails(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockLambdaApi(ctrl)
+	mockEc2Api := awsapis_mocks.NewMockEc2Api(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewLambdaApi().AnyTimes().Return(mockApi)
+	mockProvider.EXPECT().NewEc2Api().AnyTimes().Return(mockEc2Api)
+
+	mockEc2Api.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []ec2types.Subnet{
+				{SubnetId: aws.String("s-1"), AvailabilityZone: aws.String("us-east-1a")},
+				{SubnetId: aws.String("s-2"), AvailabilityZone: aws.String("us-east-1b")},
+			},
+		}, nil)
+	gomock.InOrder(
+		mockApi.EXPECT().GetFunctionConfiguration(gomock.Any(), gomock.Any()).Times(1).
+			Return(functionConfiguration(types.LastUpdateStatusSuccessful, "s-1", "s-2"), nil),
+		mockApi.EXPECT().UpdateFunctionConfiguration(gomock.Any(), gomock.Any()).Times(1).
+			Return(&lambda.UpdateFunctionConfigurationOutput{}, nil),
+		mockApi.EXPECT().GetFunctionConfiguration(gomock.Any(), gomock.Any()).Times(1).
+			Return(functionConfiguration(types.LastUpdateStatusFailed, "s-1", "s-2"), nil),
+	)
+
+	err := (&LambdaFunction{Provider: mockProvider, FunctionName: "test-function"}).
+		Fail(context.TODO(), []string{"us-east-1a"})
+
+	var activityErr domain.ActivityFailedError
+	assert.True(t, errors.As(err, &activityErr))
+	assert.False(t, activityErr.IsTemporary())
+}
+
+func TestFailShouldRefuseToRemoveAllSubnets(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockLambdaApi(ctrl)
+	mockEc2Api := awsapis_mocks.NewMockEc2Api(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewLambdaApi().AnyTimes().Return(mockApi)
+	mockProvider.EXPECT().NewEc2Api().AnyTimes().Return(mockEc2Api)
+
+	mockApi.EXPECT().GetFunctionConfiguration(gomock.Any(), gomock.Any()).Times(1).
+		Return(functionConfiguration(types.LastUpdateStatusSuccessful, "s-1"), nil)
+	mockEc2Api.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []ec2types.Subnet{
+				{SubnetId: aws.String("s-1"), AvailabilityZone: aws.String("us-east-1a")},
+			},
+		}, nil)
+	mockApi.EXPECT().UpdateFunctionConfiguration(gomock.Any(), gomock.Any()).Times(0)
+
+	err := (&LambdaFunction{Provider: mockProvider, FunctionName: "test-function"}).
+		Fail(context.TODO(), []string{"us-east-1a"})
+
+	assert.NotNil(t, err)
+}
+
+func TestRestoreShouldWaitForUpdateInProgress(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+	awsutils.WaitInterval = time.Millisecond
+
+	mockApi := awsapis_mocks.NewMockLambdaApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewLambdaApi().AnyTimes().Return(mockApi)
+
+	gomock.InOrder(
+		mockApi.EXPECT().GetFunctionConfiguration(gomock.Any(), gomock.Any()).Times(1).
+			Return(functionConfiguration(types.LastUpdateStatusInProgress, "s-2"), nil),
+		mockApi.EXPECT().GetFunctionConfiguration(gomock.Any(), gomock.Any()).Times(1).
+			Return(functionConfiguration(types.LastUpdateStatusSuccessful, "s-2"), nil),
+		mockApi.EXPECT().UpdateFunctionConfiguration(gomock.Any(), &lambda.UpdateFunctionConfigurationInput{
+			FunctionName: aws.String("test-function"),
+			VpcConfig: &types.VpcConfig{
+				SubnetIds:        []string{"s-1", "s-2"},
+				SecurityGroupIds: []string{"sg-1"},
+			},
+		}).Times(1).Return(&lambda.UpdateFunctionConfigurationOutput{}, nil),
+		mockApi.EXPECT().GetFunctionConfiguration(gomock.Any(), gomock.Any()).Times(1).
+			Return(functionConfiguration(types.LastUpdateStatusSuccessful, "s-1", "s-2"), nil),
+	)
+
+	fn := &LambdaFunction{Provider: mockProvider, FunctionName: "test-function", stateSubnets: []string{"s-1", "s-2"}}
+	err := fn.Restore(context.TODO())
+
+	assert.Nil(t, err)
+}
+
+func TestVerifyRestoredShouldMatchSavedSubnets(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockLambdaApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewLambdaApi().AnyTimes().Return(mockApi)
+
+	gomock.InOrder(
+		mockApi.EXPECT().GetFunctionConfiguration(gomock.Any(), gomock.Any()).Times(1).
+			Return(functionConfiguration(types.LastUpdateStatusSuccessful, "s-2"), nil),
+		mockApi.EXPECT().GetFunctionConfiguration(gomock.Any(), gomock.Any()).Times(1).
+			Return(functionConfiguration(types.LastUpdateStatusSuccessful, "s-2", "s-1"), nil),
+	)
+
+	fn := &LambdaFunction{Provider: mockProvider, FunctionName: "test-function", stateSubnets: []string{"s-1", "s-2"}}
+
+	restored, err := fn.VerifyRestored(context.TODO())
+	assert.False(t, restored)
+	assert.NotNil(t, err)
+
+	restored, err = fn.VerifyRestored(context.TODO())
+	assert.True(t, restored)
+	assert.Nil(t, err)
+}
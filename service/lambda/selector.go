@@ -0,0 +1,105 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/mcastellin/aws-fail-az/awsapis"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/service/awsutils"
+)
+
+func NewLambdaFunctionFromState(stateData []byte, provider awsapis.AWSProvider) (domain.ConsistentStateResource, error) {
+	var state LambdaFunctionState
+	err := json.Unmarshal(stateData, &state)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := &LambdaFunction{
+		Provider:     provider,
+		FunctionName: state.FunctionName,
+		stateSubnets: state.Subnets,
+	}
+	return resource, nil
+}
+
+func NewLambdaFunctionFaultFromConfig(ctx context.Context, selector domain.TargetSelector, provider awsapis.AWSProvider) ([]domain.ConsistentStateResource, error) {
+
+	if selector.Type != domain.ResourceTypeLambdaFunction {
+		return nil, fmt.Errorf("Unable to create LambdaFunction object from selector of type %s.", selector.Type)
+	}
+
+	var functionNames []string
+	var err error
+
+	err = selector.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := awsutils.TokenizeResourceFilter(selector.Filter, []string{"name"})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(attributes) == 1 {
+		functionNames = []string{attributes["name"]}
+	} else if len(selector.Tags) > 0 {
+		api := provider.NewLambdaApi()
+
+		functionNames, err = filterFunctionsByTags(ctx, api, selector.Tags)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	objs := make([]domain.ConsistentStateResource, len(functionNames))
+	for idx := range functionNames {
+		objs[idx] = &LambdaFunction{
+			Provider:     provider,
+			FunctionName: functionNames[idx],
+		}
+	}
+
+	return objs, nil
+}
+
+// Returns the names of the functions connected to a VPC with matching tags
+func filterFunctionsByTags(ctx context.Context, api awsapis.LambdaApi, tags []domain.AWSTag) ([]string, error) {
+	functionNames := []string{}
+
+	paginator := api.NewListFunctionsPaginator(&lambda.ListFunctionsInput{})
+	for paginator.HasMorePages() {
+		response, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, function := range response.Functions {
+			if function.VpcConfig == nil || len(function.VpcConfig.SubnetIds) == 0 {
+				continue
+			}
+			tagsOutput, err := api.ListTags(ctx, &lambda.ListTagsInput{Resource: function.FunctionArn})
+			if err != nil {
+				return nil, err
+			}
+			if resourceTagsMatchFilters(tagsOutput.Tags, tags) {
+				functionNames = append(functionNames, *function.FunctionName)
+			}
+		}
+	}
+
+	return functionNames, nil
+}
+
+func resourceTagsMatchFilters(resourceTags map[string]string, filterTags []domain.AWSTag) bool {
+	for _, filterTag := range filterTags {
+		if value, ok := resourceTags[filterTag.Name]; !ok || value != filterTag.Value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package lambda
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/mcastellin/aws-fail-az/awsapis_mocks"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFilterFunctionsByTagsShouldMatchResults(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	pager := awsapis_mocks.NewMockListFunctionsPager(ctrl)
+	gomock.InOrder(
+		pager.EXPECT().HasMorePages().Times(1).Return(true),
+		pager.EXPECT().HasMorePages().Times(1).Return(false),
+	)
+	pager.EXPECT().NextPage(gomock.Any()).Times(1).
+		Return(&lambda.ListFunctionsOutput{
+			Functions: []types.FunctionConfiguration{
+				{FunctionName: aws.String("fn-1"), FunctionArn: aws.String("arn-1"),
+					VpcConfig: &types.VpcConfigResponse{SubnetIds: []string{"s-1"}}},
+				{FunctionName: aws.String("fn-2"), FunctionArn: aws.String("arn-2"),
+					VpcConfig: &types.VpcConfigResponse{SubnetIds: []string{"s-1"}}},
+				{FunctionName: aws.String("fn-3"), FunctionArn: aws.String("arn-3")},
+			},
+		}, nil)
+
+	mockApi := awsapis_mocks.NewMockLambdaApi(ctrl)
+	mockApi.EXPECT().NewListFunctionsPaginator(gomock.Any()).Times(1).Return(pager)
+	mockApi.EXPECT().ListTags(gomock.Any(), &lambda.ListTagsInput{Resource: aws.String("arn-1")}).
+		Times(1).
+		Return(&lambda.ListTagsOutput{Tags: map[string]string{"Environment": "staging"}}, nil)
+	mockApi.EXPECT().ListTags(gomock.Any(), &lambda.ListTagsInput{Resource: aws.String("arn-2")}).
+		Times(1).
+		Return(&lambda.ListTagsOutput{Tags: map[string]string{"Environment": "live"}}, nil)
+
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewLambdaApi().AnyTimes().Return(mockApi)
+
+	config := domain.TargetSelector{
+		Type: domain.ResourceTypeLambdaFunction,
+		Tags: []domain.AWSTag{{Name: "Environment", Value: "live"}},
+	}
+	results, err := NewLambdaFunctionFaultFromConfig(context.TODO(), config, mockProvider)
+
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "fn-2", results[0].(*LambdaFunction).FunctionName)
+}
+
+func TestNewLambdaFunctionFaultFromConfigShouldUseFilter(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+
+	config := domain.TargetSelector{
+		Type:   domain.ResourceTypeLambdaFunction,
+		Filter: "name=my-function",
+	}
+	results, err := NewLambdaFunctionFaultFromConfig(context.TODO(), config, mockProvider)
+
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "my-function", results[0].(*LambdaFunction).FunctionName)
+}
@@ -0,0 +1,344 @@
+package rds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/mcastellin/aws-fail-az/awsapis"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
+	"github.com/mcastellin/aws-fail-az/service/awsutils"
+	"github.com/mcastellin/aws-fail-az/state"
+	"golang.org/x/exp/slices"
+)
+
+const dbClusterStatusAvailable = "available"
+
+type DBClusterState struct {
+	Identifier string `json:"identifier"`
+	Writer     string `json:"writer"`
+}
+
+type DBCluster struct {
+	Provider   awsapis.AWSProvider
+	Identifier string
+
+	stateWriter string
+}
+
+// An Aurora cluster with the instances of its members
+type clusterTopology struct {
+	Cluster   types.DBCluster
+	Writer    *types.DBInstance
+	Readers   []types.DBInstance
+	promotion map[string]int32
+}
+
+func (c *DBCluster) ResourceType() string {
+	return domain.ResourceTypeAuroraCluster
+}
+
+func (c *DBCluster) ResourceKey() string {
+	return c.Identifier
+}
+
+func (c *DBCluster) logger() *slog.Logger {
+	return logging.ForResource(c.ResourceType(), c.ResourceKey())
+}
+
+func (c *DBCluster) Check(ctx context.Context) (bool, error) {
+	c.logger().Debug("Checking resource state before failure simulation")
+
+	topology, err := describeClusterTopology(ctx, c.Provider.NewRdsApi(), c.Identifier)
+	if err != nil {
+		return false, err
+	}
+
+	if len(topology.Readers) == 0 {
+		err := fmt.Errorf("Aurora cluster %s has no reader instances. At least one reader is required"+
+			" to fail over the writer to another AZ", c.Identifier)
+		return false, domain.ActivityFailedError{Wrap: err, Temporary: false}
+	}
+	if status := aws.ToString(topology.Cluster.Status); status != dbClusterStatusAvailable {
+		return false, fmt.Errorf("Aurora cluster %s is not available. Found status %s.", c.Identifier, status)
+	}
+
+	return true, nil
+}
+
+func (c *DBCluster) Save(ctx context.Context, stateManager state.StateManager) error {
+
+	topology, err := describeClusterTopology(ctx, c.Provider.NewRdsApi(), c.Identifier)
+	if err != nil {
+		return err
+	}
+
+	state := &DBClusterState{
+		Identifier: c.Identifier,
+		Writer:     aws.ToString(topology.Writer.DBInstanceIdentifier),
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		c.logger().Error("Error while marshalling Aurora cluster state", logging.Err(err))
+		return err
+	}
+	err = stateManager.Save(ctx, domain.ResourceTypeAuroraCluster, c.Identifier, data)
+	if err != nil {
+		return awsutils.ClassifyError(err)
+	}
+	c.stateWriter = state.Writer
+
+	return nil
+}
+
+func (c *DBCluster) Fail(ctx context.Context, azs []string) error {
+
+	api := c.Provider.NewRdsApi()
+
+	topology, err := describeClusterTopology(ctx, api, c.Identifier)
+	if err != nil {
+		return err
+	}
+	if status := aws.ToString(topology.Cluster.Status); status != dbClusterStatusAvailable {
+		err := fmt.Errorf("Aurora cluster %s is not available. Found status %s.", c.Identifier, status)
+		return domain.ActivityFailedError{Wrap: err, Temporary: true}
+	}
+
+	writerAz := aws.ToString(topology.Writer.AvailabilityZone)
+	if slices.Contains(azs, writerAz) {
+		target := topology.failoverTarget(azs)
+		if target == "" {
+			err := fmt.Errorf("AZ failure for Aurora cluster %s would leave no reader in a surviving AZ"+
+				" to fail over the writer to", c.Identifier)
+			return domain.ActivityFailedError{Wrap: err, Temporary: false}
+		}
+
+		c.logger().Info("Failing over Aurora cluster writer", "azs", azs, "writer",
+			aws.ToString(topology.Writer.DBInstanceIdentifier), "target", target)
+
+		_, err = api.FailoverDBCluster(ctx, &rds.FailoverDBClusterInput{
+			DBClusterIdentifier:        aws.String(c.Identifier),
+			TargetDBInstanceIdentifier: aws.String(target),
+		})
+		if err != nil {
+			return awsutils.ClassifyError(err)
+		}
+
+		// The failover demotes the writer to a reader in the failed AZ, so readers are
+		// only rebooted from the topology described once the failover has completed
+		err = awsutils.WaitUntil(ctx, awsutils.DEFAULT_WAIT_TIMEOUT, "Aurora cluster failover to "+target, func() (bool, error) {
+			topology, err = describeClusterTopology(ctx, api, c.Identifier)
+			if err != nil {
+				return false, err
+			}
+			return aws.ToString(topology.Cluster.Status) == dbClusterStatusAvailable &&
+				aws.ToString(topology.Writer.DBInstanceIdentifier) == target, nil
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		c.logger().Info("Aurora cluster writer is not in a failed AZ, skipping failover", "writerAz", writerAz)
+	}
+
+	// Readers in the failed AZs are rebooted to drop their connections
+	for _, reader := range topology.readersInAzs(azs) {
+		if aws.ToString(reader.DBInstanceStatus) != dbInstanceStatusAvailable {
+			continue
+		}
+		c.logger().Info("Rebooting Aurora reader in failed AZ", "reader", aws.ToString(reader.DBInstanceIdentifier),
+			"az", aws.ToString(reader.AvailabilityZone))
+		_, err = api.RebootDBInstance(ctx, &rds.RebootDBInstanceInput{
+			DBInstanceIdentifier: reader.DBInstanceIdentifier,
+		})
+		if err != nil {
+			return awsutils.ClassifyError(err)
+		}
+	}
+
+	return nil
+}
+
+func (c *DBCluster) Plan(ctx context.Context, azs []string) (*domain.FailurePlan, error) {
+
+	topology, err := describeClusterTopology(ctx, c.Provider.NewRdsApi(), c.Identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	currentSubnets := []string{}
+	newSubnets := []string{}
+	terminations := []string{}
+	for _, instance := range append([]types.DBInstance{*topology.Writer}, topology.Readers...) {
+		instanceAz := aws.ToString(instance.AvailabilityZone)
+		for _, subnet := range subnetsInAzs(instance.DBSubnetGroup, []string{instanceAz}) {
+			if !slices.Contains(currentSubnets, subnet) {
+				currentSubnets = append(currentSubnets, subnet)
+				if !slices.Contains(azs, instanceAz) {
+					newSubnets = append(newSubnets, subnet)
+				}
+			}
+		}
+		// The writer is restarted by the failover and readers are rebooted
+		if slices.Contains(azs, instanceAz) {
+			terminations = append(terminations, aws.ToString(instance.DBInstanceIdentifier))
+		}
+	}
+
+	if slices.Contains(azs, aws.ToString(topology.Writer.AvailabilityZone)) && topology.failoverTarget(azs) == "" {
+		return nil, fmt.Errorf("AZ failure for Aurora cluster %s would leave no reader in a surviving AZ"+
+			" to fail over the writer to", c.Identifier)
+	}
+
+	return &domain.FailurePlan{
+		ResourceType:   domain.ResourceTypeAuroraCluster,
+		ResourceKey:    c.ResourceKey(),
+		CurrentSubnets: currentSubnets,
+		NewSubnets:     newSubnets,
+		Terminations:   terminations,
+	}, nil
+}
+
+// Verifies the Aurora cluster is available with its writer outside of the failed AZs
+func (c *DBCluster) VerifySteadyState(ctx context.Context, azs []string) (bool, error) {
+	topology, err := describeClusterTopology(ctx, c.Provider.NewRdsApi(), c.Identifier)
+	if err != nil {
+		return false, err
+	}
+
+	if status := aws.ToString(topology.Cluster.Status); status != dbClusterStatusAvailable {
+		return false, fmt.Errorf("Aurora cluster %s is not available. Found status %s.", c.Identifier, status)
+	}
+	if writerAz := aws.ToString(topology.Writer.AvailabilityZone); slices.Contains(azs, writerAz) {
+		return false, fmt.Errorf("Aurora cluster %s writer is still in failed AZ %s", c.Identifier, writerAz)
+	}
+	return true, nil
+}
+
+// Fails back the Aurora cluster to the writer instance saved in state. The fail back is
+// issued once the cluster is available, as the failover may still be in progress when the
+// cluster is restored right after failure
+func (c *DBCluster) Restore(ctx context.Context) error {
+
+	api := c.Provider.NewRdsApi()
+
+	var topology *clusterTopology
+	err := awsutils.WaitUntil(ctx, awsutils.DEFAULT_WAIT_TIMEOUT, "Aurora cluster available", func() (bool, error) {
+		var err error
+		topology, err = describeClusterTopology(ctx, api, c.Identifier)
+		if err != nil {
+			return false, err
+		}
+		return aws.ToString(topology.Cluster.Status) == dbClusterStatusAvailable, nil
+	})
+	if err != nil {
+		return err
+	}
+	if aws.ToString(topology.Writer.DBInstanceIdentifier) == c.stateWriter {
+		return nil
+	}
+
+	c.logger().Info("Failing back Aurora cluster writer", "writer", c.stateWriter)
+
+	_, err = api.FailoverDBCluster(ctx, &rds.FailoverDBClusterInput{
+		DBClusterIdentifier:        aws.String(c.Identifier),
+		TargetDBInstanceIdentifier: aws.String(c.stateWriter),
+	})
+	return awsutils.ClassifyError(err)
+}
+
+// Verifies the Aurora cluster is available with the writer instance saved in state.
+// The fail back completes asynchronously, so the verification is polled until it does
+func (c *DBCluster) VerifyRestored(ctx context.Context) (bool, error) {
+	topology, err := describeClusterTopology(ctx, c.Provider.NewRdsApi(), c.Identifier)
+	if err != nil {
+		return false, err
+	}
+	if status := aws.ToString(topology.Cluster.Status); status != dbClusterStatusAvailable {
+		return false, fmt.Errorf("Aurora cluster %s is not available. Found status %s.", c.Identifier, status)
+	}
+	if writer := aws.ToString(topology.Writer.DBInstanceIdentifier); writer != c.stateWriter {
+		return false, fmt.Errorf("Aurora cluster %s writer is %s, expected saved writer %s",
+			c.Identifier, writer, c.stateWriter)
+	}
+	return true, nil
+}
+
+// Returns the reader with the highest promotion priority outside of the failed AZs,
+// or an empty string if all readers are in failed AZs
+func (t *clusterTopology) failoverTarget(azs []string) string {
+	candidates := []types.DBInstance{}
+	for _, reader := range t.Readers {
+		if !slices.Contains(azs, aws.ToString(reader.AvailabilityZone)) {
+			candidates = append(candidates, reader)
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return t.promotion[aws.ToString(candidates[i].DBInstanceIdentifier)] <
+			t.promotion[aws.ToString(candidates[j].DBInstanceIdentifier)]
+	})
+	return aws.ToString(candidates[0].DBInstanceIdentifier)
+}
+
+// Returns the readers in one of the `azs`
+func (t *clusterTopology) readersInAzs(azs []string) []types.DBInstance {
+	readers := []types.DBInstance{}
+	for _, reader := range t.Readers {
+		if slices.Contains(azs, aws.ToString(reader.AvailabilityZone)) {
+			readers = append(readers, reader)
+		}
+	}
+	return readers
+}
+
+func describeClusterTopology(ctx context.Context, api awsapis.RdsApi, identifier string) (*clusterTopology, error) {
+	clustersOutput, err := api.DescribeDBClusters(ctx, &rds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(identifier),
+	})
+	if err != nil {
+		return nil, awsutils.ClassifyError(err)
+	}
+	if len(clustersOutput.DBClusters) == 0 {
+		return nil, fmt.Errorf("Could not describe Aurora cluster with identifier %s", identifier)
+	}
+
+	instancesOutput, err := api.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+		Filters: []types.Filter{{Name: aws.String("db-cluster-id"), Values: []string{identifier}}},
+	})
+	if err != nil {
+		return nil, awsutils.ClassifyError(err)
+	}
+	instances := map[string]types.DBInstance{}
+	for _, instance := range instancesOutput.DBInstances {
+		instances[aws.ToString(instance.DBInstanceIdentifier)] = instance
+	}
+
+	topology := &clusterTopology{Cluster: clustersOutput.DBClusters[0], promotion: map[string]int32{}}
+	for _, member := range topology.Cluster.DBClusterMembers {
+		memberId := aws.ToString(member.DBInstanceIdentifier)
+		instance, ok := instances[memberId]
+		if !ok {
+			return nil, fmt.Errorf("Could not describe DB instance %s of Aurora cluster %s", memberId, identifier)
+		}
+		if member.IsClusterWriter {
+			topology.Writer = &instance
+		} else {
+			topology.Readers = append(topology.Readers, instance)
+			topology.promotion[memberId] = aws.ToInt32(member.PromotionTier)
+		}
+	}
+	if topology.Writer == nil {
+		err := fmt.Errorf("Could not find the writer instance of Aurora cluster %s", identifier)
+		return nil, domain.ActivityFailedError{Wrap: err, Temporary: true}
+	}
+	return topology, nil
+}
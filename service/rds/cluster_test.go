@@ -0,0 +1,200 @@
+package rds
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/mcastellin/aws-fail-az/awsapis_mocks"
+	"github.com/mcastellin/aws-fail-az/service/awsutils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// Mocks the description of a cluster with a writer in us-east-1a and readers in us-east-1a,
+// us-east-1b and us-east-1c. The reader in us-east-1c has the highest promotion priority
+func mockClusterTopology(mockApi *awsapis_mocks.MockRdsApi, writer string) {
+	mockClusterState(mockApi, writer, "available", -1)
+}
+
+// Mocks `times` descriptions of the cluster in `status` with `writer`, or any number of
+// descriptions when `times` is negative
+func mockClusterState(mockApi *awsapis_mocks.MockRdsApi, writer string, status string, times int) {
+	members := []types.DBClusterMember{}
+	for _, id := range []string{"db-a1", "db-a2", "db-b", "db-c"} {
+		tier := int32(1)
+		if id == "db-c" {
+			tier = 0
+		}
+		members = append(members, types.DBClusterMember{
+			DBInstanceIdentifier: aws.String(id),
+			IsClusterWriter:      id == writer,
+			PromotionTier:        aws.Int32(tier),
+		})
+	}
+	clustersCall := mockApi.EXPECT().DescribeDBClusters(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBClustersOutput{DBClusters: []types.DBCluster{{
+			DBClusterIdentifier: aws.String("test-cluster"),
+			Status:              aws.String(status),
+			DBClusterMembers:    members,
+		}}}, nil)
+
+	instance := func(id string, az string) types.DBInstance {
+		return types.DBInstance{
+			DBInstanceIdentifier: aws.String(id),
+			DBInstanceStatus:     aws.String("available"),
+			AvailabilityZone:     aws.String(az),
+		}
+	}
+	instancesCall := mockApi.EXPECT().DescribeDBInstances(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBInstancesOutput{DBInstances: []types.DBInstance{
+			instance("db-a1", "us-east-1a"),
+			instance("db-a2", "us-east-1a"),
+			instance("db-b", "us-east-1b"),
+			instance("db-c", "us-east-1c"),
+		}}, nil)
+
+	if times < 0 {
+		clustersCall.AnyTimes()
+		instancesCall.AnyTimes()
+	} else {
+		clustersCall.Times(times)
+		instancesCall.Times(times)
+	}
+}
+
+func TestClusterFailShouldFailoverWriterAndRebootReadersInFailedAz(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+	awsutils.WaitInterval = time.Millisecond
+
+	mockApi := awsapis_mocks.NewMockRdsApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewRdsApi().AnyTimes().Return(mockApi)
+	mockClusterState(mockApi, "db-a1", "available", 1)
+	mockClusterState(mockApi, "db-a1", "failing-over", 2)
+	mockClusterState(mockApi, "db-c", "available", 1)
+
+	mockApi.EXPECT().FailoverDBCluster(gomock.Any(), &rds.FailoverDBClusterInput{
+		DBClusterIdentifier:        aws.String("test-cluster"),
+		TargetDBInstanceIdentifier: aws.String("db-c"),
+	}).Times(1).Return(&rds.FailoverDBClusterOutput{}, nil)
+	// The writer demoted by the failover is rebooted with the other reader in the failed AZ
+	for _, reader := range []string{"db-a1", "db-a2"} {
+		mockApi.EXPECT().RebootDBInstance(gomock.Any(), &rds.RebootDBInstanceInput{
+			DBInstanceIdentifier: aws.String(reader),
+		}).Times(1).Return(&rds.RebootDBInstanceOutput{}, nil)
+	}
+
+	err := (&DBCluster{Provider: mockProvider, Identifier: "test-cluster"}).Fail(context.TODO(), []string{"us-east-1a"})
+
+	assert.Nil(t, err)
+}
+
+func TestClusterFailShouldOnlyRebootReadersWhenWriterIsNotInFailedAz(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockRdsApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewRdsApi().AnyTimes().Return(mockApi)
+	mockClusterState(mockApi, "db-c", "available", 1)
+
+	mockApi.EXPECT().FailoverDBCluster(gomock.Any(), gomock.Any()).Times(0)
+	mockApi.EXPECT().RebootDBInstance(gomock.Any(), &rds.RebootDBInstanceInput{
+		DBInstanceIdentifier: aws.String("db-b"),
+	}).Times(1).Return(&rds.RebootDBInstanceOutput{}, nil)
+
+	err := (&DBCluster{Provider: mockProvider, Identifier: "test-cluster"}).Fail(context.TODO(), []string{"us-east-1b"})
+
+	assert.Nil(t, err)
+}
+
+func TestClusterFailShouldRefuseWithoutSurvivingReaders(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockRdsApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewRdsApi().AnyTimes().Return(mockApi)
+	mockClusterTopology(mockApi, "db-a1")
+
+	mockApi.EXPECT().FailoverDBCluster(gomock.Any(), gomock.Any()).Times(0)
+
+	err := (&DBCluster{Provider: mockProvider, Identifier: "test-cluster"}).
+		Fail(context.TODO(), []string{"us-east-1a", "us-east-1b", "us-east-1c"})
+
+	assert.NotNil(t, err)
+}
+
+func TestClusterRestoreShouldFailBackToSavedWriter(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockRdsApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewRdsApi().AnyTimes().Return(mockApi)
+	mockClusterTopology(mockApi, "db-c")
+
+	mockApi.EXPECT().FailoverDBCluster(gomock.Any(), &rds.FailoverDBClusterInput{
+		DBClusterIdentifier:        aws.String("test-cluster"),
+		TargetDBInstanceIdentifier: aws.String("db-a1"),
+	}).Times(1).Return(&rds.FailoverDBClusterOutput{}, nil)
+
+	resource, err := NewDBClusterFromState([]byte(`{"identifier":"test-cluster","writer":"db-a1"}`), mockProvider)
+	assert.Nil(t, err)
+
+	err = resource.Restore(context.TODO())
+
+	assert.Nil(t, err)
+}
+
+func TestClusterRestoreShouldWaitForFailoverBeforeFailingBack(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+	awsutils.WaitInterval = time.Millisecond
+
+	mockApi := awsapis_mocks.NewMockRdsApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewRdsApi().AnyTimes().Return(mockApi)
+	mockClusterState(mockApi, "db-c", "failing-over", 2)
+	mockClusterState(mockApi, "db-c", "available", 1)
+
+	mockApi.EXPECT().FailoverDBCluster(gomock.Any(), gomock.Any()).Times(1).
+		Return(&rds.FailoverDBClusterOutput{}, nil)
+
+	resource, err := NewDBClusterFromState([]byte(`{"identifier":"test-cluster","writer":"db-a1"}`), mockProvider)
+	assert.Nil(t, err)
+
+	err = resource.Restore(context.TODO())
+
+	assert.Nil(t, err)
+}
+
+func TestClusterVerifyRestoredShouldWaitForSavedWriter(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockRdsApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewRdsApi().AnyTimes().Return(mockApi)
+	mockClusterState(mockApi, "db-c", "failing-over", 1)
+	mockClusterState(mockApi, "db-a1", "available", 1)
+
+	mockApi.EXPECT().FailoverDBCluster(gomock.Any(), gomock.Any()).Times(0)
+
+	resource, err := NewDBClusterFromState([]byte(`{"identifier":"test-cluster","writer":"db-a1"}`), mockProvider)
+	assert.Nil(t, err)
+	verifier := resource.(*DBCluster)
+
+	restored, err := verifier.VerifyRestored(context.TODO())
+	assert.False(t, restored)
+	assert.NotNil(t, err)
+
+	restored, err = verifier.VerifyRestored(context.TODO())
+	assert.True(t, restored)
+	assert.Nil(t, err)
+}
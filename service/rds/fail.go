@@ -0,0 +1,254 @@
+package rds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/mcastellin/aws-fail-az/awsapis"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/logging"
+	"github.com/mcastellin/aws-fail-az/service/awsutils"
+	"github.com/mcastellin/aws-fail-az/state"
+	"golang.org/x/exp/slices"
+)
+
+const dbInstanceStatusAvailable = "available"
+
+type DBInstanceState struct {
+	Identifier string `json:"identifier"`
+	PrimaryAz  string `json:"primaryAz"`
+	FailBack   bool   `json:"failBack"`
+}
+
+type DBInstance struct {
+	Provider   awsapis.AWSProvider
+	Identifier string
+	// Fail back to the primary AZ saved in state when the instance is restored
+	FailBack bool
+
+	statePrimaryAz string
+}
+
+func (db *DBInstance) ResourceType() string {
+	return domain.ResourceTypeRdsInstance
+}
+
+func (db *DBInstance) ResourceKey() string {
+	return db.Identifier
+}
+
+func (db *DBInstance) logger() *slog.Logger {
+	return logging.ForResource(db.ResourceType(), db.ResourceKey())
+}
+
+func (db *DBInstance) Check(ctx context.Context) (bool, error) {
+	db.logger().Debug("Checking resource state before failure simulation")
+
+	instance, err := describeDBInstance(ctx, db.Provider.NewRdsApi(), db.Identifier)
+	if err != nil {
+		return false, err
+	}
+
+	if !instance.MultiAZ {
+		err := fmt.Errorf("DB instance %s is not a Multi-AZ deployment and cannot fail over to another AZ",
+			db.Identifier)
+		return false, domain.ActivityFailedError{Wrap: err, Temporary: false}
+	}
+	if status := aws.ToString(instance.DBInstanceStatus); status != dbInstanceStatusAvailable {
+		return false, fmt.Errorf("DB instance %s is not available. Found status %s.", db.Identifier, status)
+	}
+
+	return true, nil
+}
+
+func (db *DBInstance) Save(ctx context.Context, stateManager state.StateManager) error {
+
+	instance, err := describeDBInstance(ctx, db.Provider.NewRdsApi(), db.Identifier)
+	if err != nil {
+		return err
+	}
+
+	state := &DBInstanceState{
+		Identifier: db.Identifier,
+		PrimaryAz:  aws.ToString(instance.AvailabilityZone),
+		FailBack:   db.FailBack,
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		db.logger().Error("Error while marshalling DB instance state", logging.Err(err))
+		return err
+	}
+	err = stateManager.Save(ctx, domain.ResourceTypeRdsInstance, db.Identifier, data)
+	if err != nil {
+		return awsutils.ClassifyError(err)
+	}
+	db.statePrimaryAz = state.PrimaryAz
+
+	return nil
+}
+
+func (db *DBInstance) Fail(ctx context.Context, azs []string) error {
+
+	api := db.Provider.NewRdsApi()
+
+	instance, err := describeDBInstance(ctx, api, db.Identifier)
+	if err != nil {
+		return err
+	}
+	primaryAz := aws.ToString(instance.AvailabilityZone)
+	if !slices.Contains(azs, primaryAz) {
+		db.logger().Info("Primary DB instance is not in a failed AZ, skipping failover", "primaryAz", primaryAz)
+		return nil
+	}
+	if slices.Contains(azs, aws.ToString(instance.SecondaryAvailabilityZone)) {
+		err := fmt.Errorf("AZ failure for DB instance %s would fail over to standby AZ %s, which is also failed",
+			db.Identifier, aws.ToString(instance.SecondaryAvailabilityZone))
+		return domain.ActivityFailedError{Wrap: err, Temporary: false}
+	}
+
+	db.logger().Info("Failing over DB instance", "azs", azs, "primaryAz", primaryAz)
+
+	_, err = api.RebootDBInstance(ctx, &rds.RebootDBInstanceInput{
+		DBInstanceIdentifier: aws.String(db.Identifier),
+		ForceFailover:        aws.Bool(true),
+	})
+	return awsutils.ClassifyError(err)
+}
+
+func (db *DBInstance) Plan(ctx context.Context, azs []string) (*domain.FailurePlan, error) {
+
+	instance, err := describeDBInstance(ctx, db.Provider.NewRdsApi(), db.Identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceAzs := []string{aws.ToString(instance.AvailabilityZone), aws.ToString(instance.SecondaryAvailabilityZone)}
+	currentSubnets := subnetsInAzs(instance.DBSubnetGroup, instanceAzs)
+	newSubnets := []string{}
+	for _, subnet := range currentSubnets {
+		if !slices.Contains(subnetsInAzs(instance.DBSubnetGroup, azs), subnet) {
+			newSubnets = append(newSubnets, subnet)
+		}
+	}
+
+	// A forced failover reboots the primary DB instance
+	terminations := []string{}
+	if slices.Contains(azs, aws.ToString(instance.AvailabilityZone)) {
+		terminations = append(terminations, db.Identifier)
+	}
+
+	return &domain.FailurePlan{
+		ResourceType:   domain.ResourceTypeRdsInstance,
+		ResourceKey:    db.ResourceKey(),
+		CurrentSubnets: currentSubnets,
+		NewSubnets:     newSubnets,
+		Terminations:   terminations,
+	}, nil
+}
+
+// Verifies the DB instance is available with its primary outside of the failed AZs
+func (db *DBInstance) VerifySteadyState(ctx context.Context, azs []string) (bool, error) {
+	instance, err := describeDBInstance(ctx, db.Provider.NewRdsApi(), db.Identifier)
+	if err != nil {
+		return false, err
+	}
+
+	if status := aws.ToString(instance.DBInstanceStatus); status != dbInstanceStatusAvailable {
+		return false, fmt.Errorf("DB instance %s is not available. Found status %s.", db.Identifier, status)
+	}
+	if primaryAz := aws.ToString(instance.AvailabilityZone); slices.Contains(azs, primaryAz) {
+		return false, fmt.Errorf("Primary DB instance %s is still in failed AZ %s", db.Identifier, primaryAz)
+	}
+	return true, nil
+}
+
+// Restores the DB instance. The failover is only reverted when fail back is enabled.
+// The fail back is issued once the DB instance is available, as the failover may still
+// be in progress when the instance is restored right after failure
+func (db *DBInstance) Restore(ctx context.Context) error {
+	if !db.FailBack {
+		db.logger().Info("Fail back not enabled for DB instance, nothing to restore")
+		return nil
+	}
+
+	api := db.Provider.NewRdsApi()
+
+	var instance *types.DBInstance
+	err := awsutils.WaitUntil(ctx, awsutils.DEFAULT_WAIT_TIMEOUT, "DB instance available", func() (bool, error) {
+		var err error
+		instance, err = describeDBInstance(ctx, api, db.Identifier)
+		if err != nil {
+			return false, err
+		}
+		return aws.ToString(instance.DBInstanceStatus) == dbInstanceStatusAvailable, nil
+	})
+	if err != nil {
+		return err
+	}
+	if aws.ToString(instance.AvailabilityZone) == db.statePrimaryAz {
+		return nil
+	}
+
+	db.logger().Info("Failing back DB instance", "primaryAz", db.statePrimaryAz)
+
+	_, err = api.RebootDBInstance(ctx, &rds.RebootDBInstanceInput{
+		DBInstanceIdentifier: aws.String(db.Identifier),
+		ForceFailover:        aws.Bool(true),
+	})
+	return awsutils.ClassifyError(err)
+}
+
+// Verifies the DB instance is available with its primary back in the AZ saved in state
+// when fail back is enabled. The fail back completes asynchronously, so the verification
+// is polled until it does
+func (db *DBInstance) VerifyRestored(ctx context.Context) (bool, error) {
+	if !db.FailBack {
+		return true, nil
+	}
+
+	instance, err := describeDBInstance(ctx, db.Provider.NewRdsApi(), db.Identifier)
+	if err != nil {
+		return false, err
+	}
+	if status := aws.ToString(instance.DBInstanceStatus); status != dbInstanceStatusAvailable {
+		return false, fmt.Errorf("DB instance %s is not available. Found status %s.", db.Identifier, status)
+	}
+	if primaryAz := aws.ToString(instance.AvailabilityZone); primaryAz != db.statePrimaryAz {
+		return false, fmt.Errorf("Primary DB instance %s is in AZ %s, expected saved AZ %s",
+			db.Identifier, primaryAz, db.statePrimaryAz)
+	}
+	return true, nil
+}
+
+func describeDBInstance(ctx context.Context, api awsapis.RdsInstancesDescriptor, identifier string) (*types.DBInstance, error) {
+	output, err := api.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(identifier),
+	})
+	if err != nil {
+		return nil, awsutils.ClassifyError(err)
+	}
+	if len(output.DBInstances) == 0 {
+		return nil, fmt.Errorf("Could not describe DB instance with identifier %s", identifier)
+	}
+	return &output.DBInstances[0], nil
+}
+
+// Returns the subnets of the DB subnet group in one of the `azs`
+func subnetsInAzs(subnetGroup *types.DBSubnetGroup, azs []string) []string {
+	subnets := []string{}
+	if subnetGroup == nil {
+		return subnets
+	}
+	for _, subnet := range subnetGroup.Subnets {
+		if subnet.SubnetAvailabilityZone != nil &&
+			slices.Contains(azs, aws.ToString(subnet.SubnetAvailabilityZone.Name)) {
+			subnets = append(subnets, aws.ToString(subnet.SubnetIdentifier))
+		}
+	}
+	return subnets
+}
@@ -0,0 +1,178 @@
+package rds
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/mcastellin/aws-fail-az/awsapis_mocks"
+	"github.com/mcastellin/aws-fail-az/service/awsutils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func describeOutput(primaryAz string, secondaryAz string) *rds.DescribeDBInstancesOutput {
+	return &rds.DescribeDBInstancesOutput{
+		DBInstances: []types.DBInstance{{
+			DBInstanceIdentifier:      aws.String("test-db"),
+			DBInstanceStatus:          aws.String("available"),
+			MultiAZ:                   true,
+			AvailabilityZone:          aws.String(primaryAz),
+			SecondaryAvailabilityZone: aws.String(secondaryAz),
+		}},
+	}
+}
+
+func TestCheckShouldFailForSingleAzInstance(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockRdsApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewRdsApi().AnyTimes().Return(mockApi)
+
+	output := describeOutput("us-east-1a", "")
+	output.DBInstances[0].MultiAZ = false
+	mockApi.EXPECT().DescribeDBInstances(gomock.Any(), gomock.Any()).Times(1).Return(output, nil)
+
+	result, err := (&DBInstance{Provider: mockProvider, Identifier: "test-db"}).Check(context.TODO())
+
+	assert.NotNil(t, err)
+	assert.False(t, result)
+}
+
+func TestFailShouldForceFailoverWhenPrimaryInFailedAz(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockRdsApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewRdsApi().AnyTimes().Return(mockApi)
+
+	mockApi.EXPECT().DescribeDBInstances(gomock.Any(), gomock.Any()).Times(1).
+		Return(describeOutput("us-east-1a", "us-east-1b"), nil)
+	mockApi.EXPECT().RebootDBInstance(gomock.Any(), &rds.RebootDBInstanceInput{
+		DBInstanceIdentifier: aws.String("test-db"),
+		ForceFailover:        aws.Bool(true),
+	}).Times(1).Return(&rds.RebootDBInstanceOutput{}, nil)
+
+	err := (&DBInstance{Provider: mockProvider, Identifier: "test-db"}).Fail(context.TODO(), []string{"us-east-1a"})
+
+	assert.Nil(t, err)
+}
+
+func TestFailShouldSkipWhenPrimaryNotInFailedAz(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockRdsApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewRdsApi().AnyTimes().Return(mockApi)
+
+	mockApi.EXPECT().DescribeDBInstances(gomock.Any(), gomock.Any()).Times(1).
+		Return(describeOutput("us-east-1b", "us-east-1a"), nil)
+	mockApi.EXPECT().RebootDBInstance(gomock.Any(), gomock.Any()).Times(0)
+
+	err := (&DBInstance{Provider: mockProvider, Identifier: "test-db"}).Fail(context.TODO(), []string{"us-east-1a"})
+
+	assert.Nil(t, err)
+}
+
+func TestRestoreShouldFailBackToSavedPrimaryAz(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockRdsApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewRdsApi().AnyTimes().Return(mockApi)
+
+	mockApi.EXPECT().DescribeDBInstances(gomock.Any(), gomock.Any()).Times(1).
+		Return(describeOutput("us-east-1b", "us-east-1a"), nil)
+	mockApi.EXPECT().RebootDBInstance(gomock.Any(), gomock.Any()).Times(1).
+		Return(&rds.RebootDBInstanceOutput{}, nil)
+
+	resource, err := NewDBInstanceFromState([]byte(`{"identifier":"test-db","primaryAz":"us-east-1a","failBack":true}`), mockProvider)
+	assert.Nil(t, err)
+
+	err = resource.Restore(context.TODO())
+
+	assert.Nil(t, err)
+}
+
+func TestRestoreShouldNotFailBackWhenDisabled(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockRdsApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewRdsApi().AnyTimes().Return(mockApi)
+
+	mockApi.EXPECT().RebootDBInstance(gomock.Any(), gomock.Any()).Times(0)
+
+	resource, err := NewDBInstanceFromState([]byte(`{"identifier":"test-db","primaryAz":"us-east-1a"}`), mockProvider)
+	assert.Nil(t, err)
+
+	err = resource.Restore(context.TODO())
+
+	assert.Nil(t, err)
+}
+
+func TestRestoreShouldWaitForFailoverBeforeFailingBack(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+	awsutils.WaitInterval = time.Millisecond
+
+	mockApi := awsapis_mocks.NewMockRdsApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewRdsApi().AnyTimes().Return(mockApi)
+
+	rebooting := describeOutput("us-east-1a", "us-east-1b")
+	rebooting.DBInstances[0].DBInstanceStatus = aws.String("rebooting")
+	gomock.InOrder(
+		mockApi.EXPECT().DescribeDBInstances(gomock.Any(), gomock.Any()).Times(2).Return(rebooting, nil),
+		mockApi.EXPECT().DescribeDBInstances(gomock.Any(), gomock.Any()).Times(1).
+			Return(describeOutput("us-east-1b", "us-east-1a"), nil),
+		mockApi.EXPECT().RebootDBInstance(gomock.Any(), gomock.Any()).Times(1).
+			Return(&rds.RebootDBInstanceOutput{}, nil),
+	)
+
+	resource, err := NewDBInstanceFromState([]byte(`{"identifier":"test-db","primaryAz":"us-east-1a","failBack":true}`), mockProvider)
+	assert.Nil(t, err)
+
+	err = resource.Restore(context.TODO())
+
+	assert.Nil(t, err)
+}
+
+func TestVerifyRestoredShouldWaitForAvailableInstanceInSavedAz(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	mockApi := awsapis_mocks.NewMockRdsApi(ctrl)
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewRdsApi().AnyTimes().Return(mockApi)
+
+	rebooting := describeOutput("us-east-1a", "us-east-1b")
+	rebooting.DBInstances[0].DBInstanceStatus = aws.String("rebooting")
+	gomock.InOrder(
+		mockApi.EXPECT().DescribeDBInstances(gomock.Any(), gomock.Any()).Times(1).Return(rebooting, nil),
+		mockApi.EXPECT().DescribeDBInstances(gomock.Any(), gomock.Any()).Times(1).
+			Return(describeOutput("us-east-1a", "us-east-1b"), nil),
+	)
+	mockApi.EXPECT().RebootDBInstance(gomock.Any(), gomock.Any()).Times(0)
+
+	resource, err := NewDBInstanceFromState([]byte(`{"identifier":"test-db","primaryAz":"us-east-1a","failBack":true}`), mockProvider)
+	assert.Nil(t, err)
+	verifier := resource.(*DBInstance)
+
+	restored, err := verifier.VerifyRestored(context.TODO())
+	assert.False(t, restored)
+	assert.NotNil(t, err)
+
+	restored, err = verifier.VerifyRestored(context.TODO())
+	assert.True(t, restored)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,181 @@
+package rds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/mcastellin/aws-fail-az/awsapis"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/mcastellin/aws-fail-az/service/awsutils"
+)
+
+func NewDBInstanceFromState(stateData []byte, provider awsapis.AWSProvider) (domain.ConsistentStateResource, error) {
+	var state DBInstanceState
+	err := json.Unmarshal(stateData, &state)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := &DBInstance{
+		Provider:       provider,
+		Identifier:     state.Identifier,
+		FailBack:       state.FailBack,
+		statePrimaryAz: state.PrimaryAz,
+	}
+	return resource, nil
+}
+
+func NewDBInstanceFaultFromConfig(ctx context.Context, selector domain.TargetSelector, provider awsapis.AWSProvider) ([]domain.ConsistentStateResource, error) {
+
+	if selector.Type != domain.ResourceTypeRdsInstance {
+		return nil, fmt.Errorf("Unable to create DBInstance object from selector of type %s.", selector.Type)
+	}
+
+	var identifiers []string
+	var err error
+
+	err = selector.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := awsutils.TokenizeResourceFilter(selector.Filter, []string{"identifier"})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(attributes) == 1 {
+		identifiers = []string{attributes["identifier"]}
+	} else if len(selector.Tags) > 0 {
+		api := provider.NewRdsApi()
+
+		identifiers, err = filterDBInstancesByTags(ctx, api, selector.Tags)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	objs := make([]domain.ConsistentStateResource, len(identifiers))
+	for idx := range identifiers {
+		objs[idx] = &DBInstance{
+			Provider:   provider,
+			Identifier: identifiers[idx],
+			FailBack:   selector.FailBack,
+		}
+	}
+
+	return objs, nil
+}
+
+func filterDBInstancesByTags(ctx context.Context, api awsapis.RdsApi, tags []domain.AWSTag) ([]string, error) {
+	identifiers := []string{}
+
+	paginator := api.NewDescribeDBInstancesPaginator(&rds.DescribeDBInstancesInput{})
+	for paginator.HasMorePages() {
+		response, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, instance := range response.DBInstances {
+			if resourceTagsMatchFilters(instance.TagList, tags) {
+				identifiers = append(identifiers, *instance.DBInstanceIdentifier)
+			}
+		}
+	}
+
+	return identifiers, nil
+}
+
+func resourceTagsMatchFilters(resourceTags []types.Tag, filterTags []domain.AWSTag) bool {
+	allMatch := len(resourceTags) >= len(filterTags)
+	for _, filterTag := range filterTags {
+		match := false
+		for _, resourceTag := range resourceTags {
+			if *resourceTag.Key == filterTag.Name && *resourceTag.Value == filterTag.Value {
+				match = true
+			}
+		}
+		allMatch = allMatch && match
+	}
+	return allMatch
+}
+
+func NewDBClusterFromState(stateData []byte, provider awsapis.AWSProvider) (domain.ConsistentStateResource, error) {
+	var state DBClusterState
+	err := json.Unmarshal(stateData, &state)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := &DBCluster{
+		Provider:    provider,
+		Identifier:  state.Identifier,
+		stateWriter: state.Writer,
+	}
+	return resource, nil
+}
+
+func NewDBClusterFaultFromConfig(ctx context.Context, selector domain.TargetSelector, provider awsapis.AWSProvider) ([]domain.ConsistentStateResource, error) {
+
+	if selector.Type != domain.ResourceTypeAuroraCluster {
+		return nil, fmt.Errorf("Unable to create DBCluster object from selector of type %s.", selector.Type)
+	}
+
+	var identifiers []string
+	var err error
+
+	err = selector.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := awsutils.TokenizeResourceFilter(selector.Filter, []string{"identifier"})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(attributes) == 1 {
+		identifiers = []string{attributes["identifier"]}
+	} else if len(selector.Tags) > 0 {
+		api := provider.NewRdsApi()
+
+		identifiers, err = filterDBClustersByTags(ctx, api, selector.Tags)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	objs := make([]domain.ConsistentStateResource, len(identifiers))
+	for idx := range identifiers {
+		objs[idx] = &DBCluster{
+			Provider:   provider,
+			Identifier: identifiers[idx],
+		}
+	}
+
+	return objs, nil
+}
+
+func filterDBClustersByTags(ctx context.Context, api awsapis.RdsApi, tags []domain.AWSTag) ([]string, error) {
+	identifiers := []string{}
+
+	paginator := api.NewDescribeDBClustersPaginator(&rds.DescribeDBClustersInput{})
+	for paginator.HasMorePages() {
+		response, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cluster := range response.DBClusters {
+			if resourceTagsMatchFilters(cluster.TagList, tags) {
+				identifiers = append(identifiers, *cluster.DBClusterIdentifier)
+			}
+		}
+	}
+
+	return identifiers, nil
+}
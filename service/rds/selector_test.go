@@ -0,0 +1,73 @@
+package rds
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/mcastellin/aws-fail-az/awsapis_mocks"
+	"github.com/mcastellin/aws-fail-az/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFilterDBInstancesByTagsShouldMatchInAllPages(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	defer ctrl.Finish()
+
+	matchingTags := []types.Tag{
+		{Key: aws.String("Environment"), Value: aws.String("live")},
+		{Key: aws.String("Application"), Value: aws.String("test")},
+	}
+	pages := [][]types.DBInstance{
+		{
+			{DBInstanceIdentifier: aws.String("db-1"), TagList: matchingTags},
+		},
+		{
+			{DBInstanceIdentifier: aws.String("db-2"), TagList: matchingTags[:1]},
+			{DBInstanceIdentifier: aws.String("db-3"), TagList: matchingTags},
+		},
+	}
+	pager := createDescribeDBInstancesPager(ctrl, pages)
+
+	mockApi := awsapis_mocks.NewMockRdsApi(ctrl)
+	mockApi.EXPECT().NewDescribeDBInstancesPaginator(gomock.Any()).Times(1).Return(pager)
+
+	mockProvider := awsapis_mocks.NewMockAWSProvider(ctrl)
+	mockProvider.EXPECT().NewRdsApi().AnyTimes().Return(mockApi)
+
+	config := domain.TargetSelector{
+		Type:     domain.ResourceTypeRdsInstance,
+		Tags:     []domain.AWSTag{{Name: "Environment", Value: "live"}, {Name: "Application", Value: "test"}},
+		FailBack: true,
+	}
+	results, err := NewDBInstanceFaultFromConfig(context.TODO(), config, mockProvider)
+
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "db-1", results[0].(*DBInstance).Identifier)
+	assert.Equal(t, "db-3", results[1].(*DBInstance).Identifier)
+	assert.True(t, results[1].(*DBInstance).FailBack)
+}
+
+func createDescribeDBInstancesPager(ctrl *gomock.Controller, pages [][]types.DBInstance) *awsapis_mocks.MockDescribeDBInstancesPager {
+	pager := awsapis_mocks.NewMockDescribeDBInstancesPager(ctrl)
+
+	gomock.InOrder(
+		pager.EXPECT().HasMorePages().Times(len(pages)).Return(true),
+		pager.EXPECT().HasMorePages().Times(1).Return(false),
+	)
+
+	calls := make([]any, len(pages))
+	for idx := range pages {
+		calls[idx] = pager.EXPECT().NextPage(gomock.Any()).Times(1).
+			Return(&rds.DescribeDBInstancesOutput{
+				DBInstances: pages[idx],
+			}, nil)
+	}
+	gomock.InOrder(calls...)
+
+	return pager
+}
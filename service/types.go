@@ -1,13 +1,18 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/mcastellin/aws-fail-az/awsapis"
 	"github.com/mcastellin/aws-fail-az/domain"
 	"github.com/mcastellin/aws-fail-az/service/asg"
 	"github.com/mcastellin/aws-fail-az/service/ecs"
+	"github.com/mcastellin/aws-fail-az/service/eks"
+	"github.com/mcastellin/aws-fail-az/service/elasticache"
 	"github.com/mcastellin/aws-fail-az/service/elbv2"
+	"github.com/mcastellin/aws-fail-az/service/lambda"
+	"github.com/mcastellin/aws-fail-az/service/rds"
 	"github.com/mcastellin/aws-fail-az/state"
 )
 
@@ -15,22 +20,32 @@ import (
 func InitServiceFaults() *FaultsInitFns {
 
 	initFns := &FaultsInitFns{
-		faults: map[string]func(domain.TargetSelector, awsapis.AWSProvider) ([]domain.ConsistentStateResource, error){
+		faults: map[string]func(context.Context, domain.TargetSelector, awsapis.AWSProvider) ([]domain.ConsistentStateResource, error){
 
 			// Register init functions for new fault types in this structure
 
 			domain.ResourceTypeEcsService:        ecs.NewEcsServiceFaultFromConfig,
 			domain.ResourceTypeAutoScalingGroup:  asg.NewAutoScalingGroupFaultFromConfig,
 			domain.ResourceTypeElbv2LoadBalancer: elbv2.NewElbv2LoadBalancerFaultFromConfig,
+			domain.ResourceTypeRdsInstance:       rds.NewDBInstanceFaultFromConfig,
+			domain.ResourceTypeAuroraCluster:     rds.NewDBClusterFaultFromConfig,
+			domain.ResourceTypeElastiCacheGroup:  elasticache.NewReplicationGroupFaultFromConfig,
+			domain.ResourceTypeEksNodegroup:      eks.NewNodegroupFaultFromConfig,
+			domain.ResourceTypeLambdaFunction:    lambda.NewLambdaFunctionFaultFromConfig,
 		},
 
-		restore: map[string]func([]byte, awsapis.AWSProvider) error{
+		fromState: map[string]func([]byte, awsapis.AWSProvider) (domain.ConsistentStateResource, error){
 
-			// Register restore from state functions for new fault types in this structure
+			// Register init from state functions for new fault types in this structure
 
-			domain.ResourceTypeEcsService:        ecs.RestoreEcsServicesFromState,
-			domain.ResourceTypeAutoScalingGroup:  asg.RestoreAutoScalingGroupsFromState,
-			domain.ResourceTypeElbv2LoadBalancer: elbv2.RestoreElbv2LoadBalancersFromState,
+			domain.ResourceTypeEcsService:        ecs.NewEcsServiceFromState,
+			domain.ResourceTypeAutoScalingGroup:  asg.NewAutoScalingGroupFromState,
+			domain.ResourceTypeElbv2LoadBalancer: elbv2.NewElbv2LoadBalancerFromState,
+			domain.ResourceTypeRdsInstance:       rds.NewDBInstanceFromState,
+			domain.ResourceTypeAuroraCluster:     rds.NewDBClusterFromState,
+			domain.ResourceTypeElastiCacheGroup:  elasticache.NewReplicationGroupFromState,
+			domain.ResourceTypeEksNodegroup:      eks.NewNodegroupFromState,
+			domain.ResourceTypeLambdaFunction:    lambda.NewLambdaFunctionFromState,
 		},
 	}
 	return initFns
@@ -40,35 +55,47 @@ func InitServiceFaults() *FaultsInitFns {
 type FaultsInitFns struct {
 
 	// A map of all available fault types and their initialization functions
-	faults map[string]func(domain.TargetSelector, awsapis.AWSProvider) ([]domain.ConsistentStateResource, error)
+	faults map[string]func(context.Context, domain.TargetSelector, awsapis.AWSProvider) ([]domain.ConsistentStateResource, error)
 
-	// A map of all available fault types and their restore functions
-	restore map[string]func([]byte, awsapis.AWSProvider) error
+	// A map of all available fault types and the functions to initialize them from their saved state
+	fromState map[string]func([]byte, awsapis.AWSProvider) (domain.ConsistentStateResource, error)
 }
 
 // Initialize new resource faults from their selector
-func (obj *FaultsInitFns) NewResourceForType(selector domain.TargetSelector,
+func (obj *FaultsInitFns) NewResourceForType(ctx context.Context, selector domain.TargetSelector,
 	provider awsapis.AWSProvider) ([]domain.ConsistentStateResource, error) {
 
 	initFn, ok := obj.faults[selector.Type]
 	if ok {
-		return initFn(selector, provider)
+		return initFn(ctx, selector, provider)
 	}
 
 	err := fmt.Errorf("Could not recognize resource type %s", selector.Type)
 	return nil, err
 }
 
-// Call the specific RestoreFromState function for the resource type specified in the state object
-func (obj *FaultsInitFns) RestoreFromState(state state.ResourceState, provider awsapis.AWSProvider) error {
-	restoreFn, ok := obj.restore[state.ResourceType]
+// Initialize a resource from its saved state object. The resource returned can restore
+// the saved configuration with the Restore function
+func (obj *FaultsInitFns) NewResourceFromState(state state.ResourceState,
+	provider awsapis.AWSProvider) (domain.ConsistentStateResource, error) {
+
+	fromStateFn, ok := obj.fromState[state.ResourceType]
 	if ok {
-		return restoreFn(state.State, provider)
+		return fromStateFn(state.State, provider)
 	}
 
 	err := fmt.Errorf("unknown resource of type %s found in state with key %s. Object will be ignored",
 		state.ResourceType,
 		state.Key,
 	)
-	return err
+	return nil, err
+}
+
+// Restore the resource saved in the state object
+func (obj *FaultsInitFns) RestoreFromState(ctx context.Context, state state.ResourceState, provider awsapis.AWSProvider) error {
+	resource, err := obj.NewResourceFromState(state, provider)
+	if err != nil {
+		return err
+	}
+	return resource.Restore(ctx)
 }
@@ -0,0 +1,125 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/mcastellin/aws-fail-az/logging"
+)
+
+// The namespace of the state table where experiment records are stored
+const EXPERIMENTS_NAMESPACE string = "_experiments"
+
+// Phases of an experiment
+const (
+	ExperimentPhaseFail    = "Fail"
+	ExperimentPhaseHold    = "Hold"
+	ExperimentPhaseRestore = "Restore"
+	ExperimentPhaseDone    = "Done"
+)
+
+// Outcomes of a completed experiment
+const (
+	ExperimentOutcomeSucceeded = "succeeded"
+	ExperimentOutcomeFailed    = "failed"
+)
+
+// A record of an AZ failure experiment run in a namespace
+type Experiment struct {
+	Namespace      string          `json:"namespace"`
+	Azs            []string        `json:"azs"`
+	Config         json.RawMessage `json:"config,omitempty"`
+	StartTime      time.Time       `json:"startTime"`
+	EndTime        *time.Time      `json:"endTime,omitempty"`
+	CallerIdentity string          `json:"callerIdentity"`
+	Phase          string          `json:"phase"`
+	// The outcome of the experiment, empty while the experiment is active
+	Outcome string `json:"outcome,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Returns true if the experiment has not completed yet
+func (e Experiment) IsActive() bool {
+	return e.Outcome == ""
+}
+
+// Saves the experiment record for the state manager namespace, replacing any previous record
+func (m *stateManagerImpl) SaveExperiment(ctx context.Context, experiment Experiment) error {
+	if err := m.checkInitialized(); err != nil {
+		return err
+	}
+	experiment.Namespace = m.Namespace
+
+	data, err := json.Marshal(experiment)
+	if err != nil {
+		return err
+	}
+	item, err := attributevalue.MarshalMap(ResourceState{
+		Namespace:    EXPERIMENTS_NAMESPACE,
+		Key:          fmt.Sprintf("/%s/%s", EXPERIMENTS_NAMESPACE, m.Namespace),
+		ResourceKey:  m.Namespace,
+		ResourceType: "experiment",
+		CreatedTime:  experiment.StartTime.Unix(),
+		State:        data,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = m.Api.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(m.TableName),
+		Item:      item,
+	})
+	return err
+}
+
+// Finds the experiment records of all namespaces, or of a single namespace if `namespace` is set
+func (m *stateManagerImpl) QueryExperiments(ctx context.Context, namespace string) ([]Experiment, error) {
+	if err := m.checkInitialized(); err != nil {
+		return nil, err
+	}
+
+	keyExpr := expression.Key("namespace").Equal(expression.Value(EXPERIMENTS_NAMESPACE))
+	builder := expression.NewBuilder().WithKeyCondition(keyExpr)
+	builder = QueryStatesInput{ResourceKey: namespace}.filterExpression(builder)
+	expr, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	experiments := []Experiment{}
+	paginator := m.Api.NewQueryPaginator(&dynamodb.QueryInput{
+		TableName:                 aws.String(m.TableName),
+		IndexName:                 aws.String("LSINamespace"),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	for paginator.HasMorePages() {
+		queryOutput, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var records []ResourceState
+		if err := attributevalue.UnmarshalListOfMaps(queryOutput.Items, &records); err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			var experiment Experiment
+			if err := json.Unmarshal(record.State, &experiment); err != nil {
+				slog.Warn("Ignoring invalid experiment record", "record", record.Key, logging.Err(err))
+				continue
+			}
+			experiments = append(experiments, experiment)
+		}
+	}
+	return experiments, nil
+}
@@ -0,0 +1,81 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/mcastellin/aws-fail-az/awsapis_mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSaveExperimentShouldWriteRecordInExperimentsNamespace(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	mockApi := awsapis_mocks.NewMockDynamodbApi(ctrl)
+
+	var saved ResourceState
+	mockApi.EXPECT().PutItem(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, params *dynamodb.PutItemInput,
+			f ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+
+			assert.Nil(t, attributevalue.UnmarshalMap(params.Item, &saved))
+			return &dynamodb.PutItemOutput{}, nil
+		})
+
+	mgr := stateManagerImpl{Api: mockApi, Namespace: "exp-1"}
+	mgr.isInitialized = true
+
+	err := mgr.SaveExperiment(context.TODO(), Experiment{Azs: []string{"us-east-1a"}, Phase: ExperimentPhaseFail})
+
+	assert.Nil(t, err)
+	assert.Equal(t, EXPERIMENTS_NAMESPACE, saved.Namespace)
+	assert.Equal(t, "exp-1", saved.ResourceKey)
+
+	var experiment Experiment
+	assert.Nil(t, json.Unmarshal(saved.State, &experiment))
+	assert.Equal(t, "exp-1", experiment.Namespace)
+	assert.Equal(t, ExperimentPhaseFail, experiment.Phase)
+	assert.True(t, experiment.IsActive())
+}
+
+func TestQueryExperimentsShouldReturnRecords(t *testing.T) {
+	ctrl, _ := gomock.WithContext(context.Background(), t)
+	mockApi := awsapis_mocks.NewMockDynamodbApi(ctrl)
+	mockPager := awsapis_mocks.NewMockDynamodbQueryPager(ctrl)
+
+	items := []map[string]types.AttributeValue{}
+	for _, experiment := range []Experiment{
+		{Namespace: "exp-1", StartTime: time.Now(), Outcome: ExperimentOutcomeSucceeded},
+		{Namespace: "exp-2", StartTime: time.Now()},
+	} {
+		data, _ := json.Marshal(experiment)
+		item, _ := attributevalue.MarshalMap(ResourceState{
+			Namespace: EXPERIMENTS_NAMESPACE, ResourceKey: experiment.Namespace, State: data})
+		items = append(items, item)
+	}
+	items = append(items, map[string]types.AttributeValue{
+		"state": &types.AttributeValueMemberB{Value: []byte("not json")}})
+
+	mockApi.EXPECT().NewQueryPaginator(gomock.Any()).Times(1).Return(mockPager)
+	gomock.InOrder(
+		mockPager.EXPECT().HasMorePages().Return(true),
+		mockPager.EXPECT().NextPage(gomock.Any()).Return(&dynamodb.QueryOutput{Items: items}, nil),
+		mockPager.EXPECT().HasMorePages().Return(false),
+	)
+
+	mgr := stateManagerImpl{Api: mockApi}
+	mgr.isInitialized = true
+
+	experiments, err := mgr.QueryExperiments(context.TODO(), "")
+
+	assert.Nil(t, err)
+	assert.Len(t, experiments, 2)
+	assert.False(t, experiments[0].IsActive())
+	assert.True(t, experiments[1].IsActive())
+}
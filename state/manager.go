@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"time"
 
@@ -14,6 +14,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/mcastellin/aws-fail-az/awsapis"
+	"github.com/mcastellin/aws-fail-az/logging"
 )
 
 // The current schema version of the state table
@@ -35,21 +36,27 @@ type StateManager interface {
 	// Initialize the state manager by establishing the connection with Dynamodb
 	// This function only needs to be called once for every object that implements
 	// StateManager. Further calls will have no effect
-	Initialize() error
+	Initialize(ctx context.Context) error
 
 	// Save a new state in storage
-	Save(resourceType string, resourceKey string, state []byte) error
+	Save(ctx context.Context, resourceType string, resourceKey string, state []byte) error
 
 	// Reads a single state object from storage
 	// Returns a pointer to a ResourceState object or an error if the state is not found
-	GetState(resourceType string, resourceKey string) (*ResourceState, error)
+	GetState(ctx context.Context, resourceType string, resourceKey string) (*ResourceState, error)
 
 	// QueryStates finds state objects in storage by resourceType or resourceKey
 	// Returns a list of ResourceState objects found in storage
-	QueryStates(params *QueryStatesInput) ([]ResourceState, error)
+	QueryStates(ctx context.Context, params *QueryStatesInput) ([]ResourceState, error)
 
 	// Removes a single state object from storage
-	RemoveState(stateObj ResourceState) error
+	RemoveState(ctx context.Context, stateObj ResourceState) error
+
+	// Saves the record of the experiment run in the namespace, replacing any previous record
+	SaveExperiment(ctx context.Context, experiment Experiment) error
+
+	// Finds the experiment records of all namespaces, or of a single namespace if `namespace` is set
+	QueryExperiments(ctx context.Context, namespace string) ([]Experiment, error)
 }
 
 // Represents the input of a QueryStates operation
@@ -93,11 +100,11 @@ type ResourceState struct {
 func (state ResourceState) GetKey() map[string]types.AttributeValue {
 	namespace, err := attributevalue.Marshal(state.Namespace)
 	if err != nil {
-		log.Panic(err)
+		panic(err)
 	}
 	key, err := attributevalue.Marshal(state.Key)
 	if err != nil {
-		log.Panic(err)
+		panic(err)
 	}
 	return map[string]types.AttributeValue{"namespace": namespace, "key": key}
 }
@@ -110,27 +117,28 @@ type stateManagerImpl struct {
 	isInitialized bool
 }
 
-func (m *stateManagerImpl) Initialize() error {
+func (m *stateManagerImpl) Initialize(ctx context.Context) error {
 	stateTableName := os.Getenv("AWS_FAIL_AZ_STATE_TABLE")
 	if stateTableName == "" {
-		log.Printf("AWS_FAIL_AZ_STATE_TABLE variable is not set. Using default %s", FALLBACK_STATE_TABLE_NAME)
+		slog.Debug("AWS_FAIL_AZ_STATE_TABLE variable is not set, using default state table",
+			"table", FALLBACK_STATE_TABLE_NAME)
 		m.TableName = FALLBACK_STATE_TABLE_NAME
 	} else {
 		m.TableName = stateTableName
 	}
 
-	exists, err := m.tableExists()
+	exists, err := m.tableExists(ctx)
 	if err != nil {
 		return fmt.Errorf("An unknown error occurred: %v", err)
 	}
 
 	if !exists {
-		log.Printf("State table with name %s not found. Creating...", stateTableName)
-		_, err := m.createTable()
+		slog.Info("State table not found, creating it", "table", m.TableName)
+		_, err := m.createTable(ctx)
 		if err != nil {
 			return fmt.Errorf("ERROR: creating state table in Dynamodb. %v", err)
 		}
-		err = m.writeSchemaVersion()
+		err = m.writeSchemaVersion(ctx)
 		if err != nil {
 			return fmt.Errorf("ERROR: populating state table version in Dynamodb. %v", err)
 		}
@@ -140,7 +148,7 @@ func (m *stateManagerImpl) Initialize() error {
 		m.Namespace = "default"
 	}
 
-	if err := m.checkSchemaVersion(); err != nil {
+	if err := m.checkSchemaVersion(ctx); err != nil {
 		return fmt.Errorf("ERROR: state table version check failed. %v", err)
 	}
 
@@ -148,7 +156,7 @@ func (m *stateManagerImpl) Initialize() error {
 	return nil
 }
 
-func (m *stateManagerImpl) Save(resourceType string, resourceKey string, state []byte) error {
+func (m *stateManagerImpl) Save(ctx context.Context, resourceType string, resourceKey string, state []byte) error {
 	if err := m.checkInitialized(); err != nil {
 		return err
 	}
@@ -166,7 +174,7 @@ func (m *stateManagerImpl) Save(resourceType string, resourceKey string, state [
 		TableName: aws.String(m.TableName),
 		Key:       stateObj.GetKey(),
 	}
-	response, err := m.Api.GetItem(context.TODO(), getItemInput)
+	response, err := m.Api.GetItem(ctx, getItemInput)
 	if err != nil {
 		return err
 	}
@@ -179,7 +187,7 @@ func (m *stateManagerImpl) Save(resourceType string, resourceKey string, state [
 	if err != nil {
 		return err
 	}
-	_, err = m.Api.PutItem(context.TODO(), &dynamodb.PutItemInput{
+	_, err = m.Api.PutItem(ctx, &dynamodb.PutItemInput{
 		TableName: aws.String(m.TableName),
 		Item:      item,
 	})
@@ -187,7 +195,7 @@ func (m *stateManagerImpl) Save(resourceType string, resourceKey string, state [
 	return err
 }
 
-func (m *stateManagerImpl) GetState(resourceType string, resourceKey string) (*ResourceState, error) {
+func (m *stateManagerImpl) GetState(ctx context.Context, resourceType string, resourceKey string) (*ResourceState, error) {
 	if err := m.checkInitialized(); err != nil {
 		return nil, err
 	}
@@ -204,7 +212,7 @@ func (m *stateManagerImpl) GetState(resourceType string, resourceKey string) (*R
 		TableName: aws.String(m.TableName),
 		Key:       stateObj.GetKey(),
 	}
-	response, err := m.Api.GetItem(context.TODO(), getItemInput)
+	response, err := m.Api.GetItem(ctx, getItemInput)
 	if err != nil {
 		return nil, err
 	}
@@ -220,7 +228,7 @@ func (m *stateManagerImpl) GetState(resourceType string, resourceKey string) (*R
 	return &out, nil
 }
 
-func (m *stateManagerImpl) QueryStates(params *QueryStatesInput) ([]ResourceState, error) {
+func (m *stateManagerImpl) QueryStates(ctx context.Context, params *QueryStatesInput) ([]ResourceState, error) {
 	if err := m.checkInitialized(); err != nil {
 		return nil, err
 	}
@@ -231,7 +239,7 @@ func (m *stateManagerImpl) QueryStates(params *QueryStatesInput) ([]ResourceStat
 
 	expr, err := builder.Build()
 	if err != nil {
-		log.Println("Unable to build query expression to fetch resource states")
+		slog.Error("Unable to build query expression to fetch resource states", logging.Err(err))
 		return []ResourceState{}, err
 	}
 
@@ -248,7 +256,7 @@ func (m *stateManagerImpl) QueryStates(params *QueryStatesInput) ([]ResourceStat
 
 	paginator := m.Api.NewQueryPaginator(queryInput)
 	for paginator.HasMorePages() {
-		queryOutput, err := paginator.NextPage(context.TODO())
+		queryOutput, err := paginator.NextPage(ctx)
 		if err != nil {
 			return []ResourceState{}, err
 		}
@@ -256,7 +264,7 @@ func (m *stateManagerImpl) QueryStates(params *QueryStatesInput) ([]ResourceStat
 		var states []ResourceState
 		err = attributevalue.UnmarshalListOfMaps(queryOutput.Items, &states)
 		if err != nil {
-			log.Println("Error unmarshalling resource states")
+			slog.Error("Error unmarshalling resource states", logging.Err(err))
 			return []ResourceState{}, err
 		} else {
 			resourceStates = append(resourceStates, states...)
@@ -266,7 +274,7 @@ func (m *stateManagerImpl) QueryStates(params *QueryStatesInput) ([]ResourceStat
 	return resourceStates, nil
 }
 
-func (m *stateManagerImpl) RemoveState(stateObj ResourceState) error {
+func (m *stateManagerImpl) RemoveState(ctx context.Context, stateObj ResourceState) error {
 	if err := m.checkInitialized(); err != nil {
 		return err
 	}
@@ -275,7 +283,7 @@ func (m *stateManagerImpl) RemoveState(stateObj ResourceState) error {
 		TableName: aws.String(m.TableName),
 		Key:       stateObj.GetKey(),
 	}
-	_, err := m.Api.DeleteItem(context.TODO(), deleteItemInput)
+	_, err := m.Api.DeleteItem(ctx, deleteItemInput)
 	if err != nil {
 		return err
 	}
@@ -293,12 +301,12 @@ func (m *stateManagerImpl) checkInitialized() error {
 
 // Check if the state table already exists for the current AWS Account/Region
 // Returns: true if the table exists, false otherwise
-func (m *stateManagerImpl) tableExists() (bool, error) {
+func (m *stateManagerImpl) tableExists(ctx context.Context) (bool, error) {
 	input := &dynamodb.DescribeTableInput{
 		TableName: aws.String(m.TableName),
 	}
 
-	_, err := m.Api.DescribeTable(context.TODO(), input)
+	_, err := m.Api.DescribeTable(ctx, input)
 	if err != nil {
 		var t *types.ResourceNotFoundException
 		if errors.As(err, &t) {
@@ -311,7 +319,7 @@ func (m *stateManagerImpl) tableExists() (bool, error) {
 
 // Creates the resource state table in Dynamodb for the current AWS Account/Region
 // and wait for table creationg before returning
-func (m *stateManagerImpl) createTable() (*dynamodb.CreateTableOutput, error) {
+func (m *stateManagerImpl) createTable(ctx context.Context) (*dynamodb.CreateTableOutput, error) {
 	input := &dynamodb.CreateTableInput{
 		TableName: aws.String(m.TableName),
 		KeySchema: []types.KeySchemaElement{
@@ -358,27 +366,27 @@ func (m *stateManagerImpl) createTable() (*dynamodb.CreateTableOutput, error) {
 		},
 	}
 
-	createOutput, err := m.Api.CreateTable(context.TODO(), input)
+	createOutput, err := m.Api.CreateTable(ctx, input)
 	if err != nil {
-		log.Fatalf("Failed to create Dynamodb Table to store the current resource state, %v", err)
+		return nil, fmt.Errorf("Failed to create Dynamodb Table to store the current resource state, %v", err)
 	}
 
-	log.Printf("Wait for table exists: %s", m.TableName)
+	slog.Info("Waiting for state table to exist", "table", m.TableName)
 	waiter := m.Api.NewTableExistsWaiter()
 	err = waiter.Wait(
-		context.TODO(),
+		ctx,
 		&dynamodb.DescribeTableInput{TableName: aws.String(m.TableName)},
 		5*time.Minute,
 	)
 	if err != nil {
-		log.Fatalf("Wait for table exists failed. It's not safe to continue this operation. %v", err)
+		return nil, fmt.Errorf("Wait for table exists failed. It's not safe to continue this operation. %v", err)
 	}
 
 	return createOutput, nil
 }
 
 // Writes the current schema version into the state table
-func (m *stateManagerImpl) writeSchemaVersion() error {
+func (m *stateManagerImpl) writeSchemaVersion(ctx context.Context) error {
 	versionObj := ResourceState{
 		Namespace:    "_system",
 		Key:          "/schema/version",
@@ -386,7 +394,7 @@ func (m *stateManagerImpl) writeSchemaVersion() error {
 		ResourceType: "nil",
 	}
 
-	response, err := m.Api.GetItem(context.TODO(), &dynamodb.GetItemInput{
+	response, err := m.Api.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(m.TableName),
 		Key:       versionObj.GetKey(),
 	})
@@ -402,7 +410,7 @@ func (m *stateManagerImpl) writeSchemaVersion() error {
 	if err != nil {
 		return err
 	}
-	_, err = m.Api.PutItem(context.TODO(), &dynamodb.PutItemInput{
+	_, err = m.Api.PutItem(ctx, &dynamodb.PutItemInput{
 		TableName: &m.TableName,
 		Item:      item,
 	})
@@ -411,13 +419,13 @@ func (m *stateManagerImpl) writeSchemaVersion() error {
 }
 
 // Checks the state table version is the same as the required version
-func (m *stateManagerImpl) checkSchemaVersion() error {
+func (m *stateManagerImpl) checkSchemaVersion(ctx context.Context) error {
 	versionObj := ResourceState{
 		Namespace: "_system",
 		Key:       "/schema/version",
 	}
 
-	response, err := m.Api.GetItem(context.TODO(), &dynamodb.GetItemInput{
+	response, err := m.Api.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(m.TableName),
 		Key:       versionObj.GetKey(),
 	})
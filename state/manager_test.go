@@ -47,7 +47,7 @@ func TestStateInitializeNewTableWithOsVar(t *testing.T) {
 		Api: mockApi,
 	}
 
-	err = mgr.Initialize()
+	err = mgr.Initialize(context.TODO())
 
 	assert.Nil(t, err)
 	assert.True(t, mgr.isInitialized)
@@ -80,7 +80,7 @@ func TestStateInitializeShouldFailWithWrongVersion(t *testing.T) {
 		Api: mockApi,
 	}
 
-	err = mgr.Initialize()
+	err = mgr.Initialize(context.TODO())
 
 	assert.NotNil(t, err)
 	assert.False(t, mgr.isInitialized)
@@ -130,7 +130,7 @@ func TestStateInitializeNewTable(t *testing.T) {
 
 	mgr := stateManagerImpl{Api: mockApi}
 
-	err = mgr.Initialize()
+	err = mgr.Initialize(context.TODO())
 
 	assert.Nil(t, err)
 }
@@ -151,7 +151,7 @@ func TestSaveStateShouldNotOverrideExistingKeys(t *testing.T) {
 	mgr := stateManagerImpl{Api: mockApi}
 	mgr.isInitialized = true
 
-	err := mgr.Save("type", "key", []byte("payload"))
+	err := mgr.Save(context.TODO(), "type", "key", []byte("payload"))
 
 	assert.NotNil(t, err)
 }